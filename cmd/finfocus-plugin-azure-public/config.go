@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// configFileEnv names the optional YAML or JSON configuration file.
+const configFileEnv = "FINFOCUS_CONFIG_FILE"
+
+// fileConfig is the schema of the FINFOCUS_CONFIG_FILE file. Durations are
+// strings such as "30s". Unset fields keep their defaults, and environment
+// variables override the file.
+type fileConfig struct {
+	BaseURL                   *string        `yaml:"base_url"`
+	RetryMax                  *int           `yaml:"retry_max"`
+	RetryWaitMin              *time.Duration `yaml:"retry_wait_min"`
+	RetryWaitMax              *time.Duration `yaml:"retry_wait_max"`
+	Timeout                   *time.Duration `yaml:"timeout"`
+	UserAgent                 *string        `yaml:"user_agent"`
+	MaxPages                  *int           `yaml:"max_pages"`
+	SupersetMaxPages          *int           `yaml:"superset_max_pages"`
+	MaxResponseBodyBytes      *int64         `yaml:"max_response_body_bytes"`
+	CacheMaxSize              *int           `yaml:"cache_max_size"`
+	CacheTTL                  *time.Duration `yaml:"cache_ttl"`
+	CacheExpiresAtTTL         *time.Duration `yaml:"cache_expires_at_ttl"`
+	CacheSoftTTL              *time.Duration `yaml:"cache_soft_ttl"`
+	CacheStaleWhileRevalidate *bool          `yaml:"cache_stale_while_revalidate"`
+	CacheNegativeTTL          *time.Duration `yaml:"cache_negative_ttl"`
+}
+
+// loadConfig builds the Azure client and cache configuration from defaults,
+// the optional FINFOCUS_CONFIG_FILE, and FINFOCUS_AZURE_* and FINFOCUS_CACHE_*
+// environment variables, in increasing precedence. Unreadable files,
+// malformed values, and configurations rejected by Validate are errors. The
+// effective configuration is logged.
+func loadConfig(logger zerolog.Logger) (azureclient.Config, azureclient.CacheConfig, error) {
+	clientConfig := azureclient.DefaultConfig()
+	clientConfig.Logger = logger
+	cacheConfig := azureclient.DefaultCacheConfig()
+	cacheConfig.Logger = logger
+
+	if path := os.Getenv(configFileEnv); path != "" {
+		if err := applyConfigFile(path, &clientConfig, &cacheConfig); err != nil {
+			return clientConfig, cacheConfig, err
+		}
+	}
+
+	if err := applyConfigEnv(&clientConfig, &cacheConfig); err != nil {
+		return clientConfig, cacheConfig, err
+	}
+	cacheConfig.SupersetServices = parseSupersetServices()
+
+	if err := clientConfig.Validate(); err != nil {
+		return clientConfig, cacheConfig, fmt.Errorf("invalid Azure client configuration: %w", err)
+	}
+	if err := cacheConfig.Validate(); err != nil {
+		return clientConfig, cacheConfig, fmt.Errorf("invalid cache configuration: %w", err)
+	}
+
+	logger.Info().
+		Str("base_url", clientConfig.BaseURL).
+		Int("retry_max", clientConfig.RetryMax).
+		Str("retry_wait_min", clientConfig.RetryWaitMin.String()).
+		Str("retry_wait_max", clientConfig.RetryWaitMax.String()).
+		Str("timeout", clientConfig.Timeout.String()).
+		Str("user_agent", clientConfig.UserAgent).
+		Int("max_pages", clientConfig.MaxPages).
+		Int("superset_max_pages", clientConfig.SupersetMaxPages).
+		Int64("max_response_body_bytes", clientConfig.MaxResponseBodyBytes).
+		Msg("effective Azure client configuration")
+	logger.Info().
+		Int("cache_max_size", cacheConfig.MaxSize).
+		Str("cache_ttl", cacheConfig.TTL.String()).
+		Str("cache_expires_at_ttl", cacheConfig.ExpiresAtTTL.String()).
+		Str("cache_soft_ttl", cacheConfig.SoftTTL.String()).
+		Bool("cache_stale_while_revalidate", cacheConfig.StaleWhileRevalidate).
+		Str("cache_negative_ttl", cacheConfig.NegativeTTL.String()).
+		Strs("cache_superset_services", cacheConfig.SupersetServices).
+		Msg("effective cache configuration")
+
+	return clientConfig, cacheConfig, nil
+}
+
+// applyConfigFile overlays the fields set in the YAML or JSON file at path.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func applyConfigFile(path string, clientConfig *azureclient.Config, cacheConfig *azureclient.CacheConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", configFileEnv, err)
+	}
+
+	var file fileConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("invalid %s %q: %w", configFileEnv, path, err)
+	}
+
+	setIfPresent(&clientConfig.BaseURL, file.BaseURL)
+	setIfPresent(&clientConfig.RetryMax, file.RetryMax)
+	setIfPresent(&clientConfig.RetryWaitMin, file.RetryWaitMin)
+	setIfPresent(&clientConfig.RetryWaitMax, file.RetryWaitMax)
+	setIfPresent(&clientConfig.Timeout, file.Timeout)
+	setIfPresent(&clientConfig.UserAgent, file.UserAgent)
+	setIfPresent(&clientConfig.MaxPages, file.MaxPages)
+	setIfPresent(&clientConfig.SupersetMaxPages, file.SupersetMaxPages)
+	setIfPresent(&clientConfig.MaxResponseBodyBytes, file.MaxResponseBodyBytes)
+	setIfPresent(&cacheConfig.MaxSize, file.CacheMaxSize)
+	setIfPresent(&cacheConfig.TTL, file.CacheTTL)
+	setIfPresent(&cacheConfig.ExpiresAtTTL, file.CacheExpiresAtTTL)
+	setIfPresent(&cacheConfig.SoftTTL, file.CacheSoftTTL)
+	setIfPresent(&cacheConfig.StaleWhileRevalidate, file.CacheStaleWhileRevalidate)
+	setIfPresent(&cacheConfig.NegativeTTL, file.CacheNegativeTTL)
+	return nil
+}
+
+// setIfPresent sets *dst to *src when src is non-nil.
+func setIfPresent[T any](dst *T, src *T) {
+	if src != nil {
+		*dst = *src
+	}
+}
+
+// applyConfigEnv overlays the configuration environment variables that are
+// set. All malformed values are reported together.
+func applyConfigEnv(clientConfig *azureclient.Config, cacheConfig *azureclient.CacheConfig) error {
+	envString("FINFOCUS_AZURE_BASE_URL", &clientConfig.BaseURL)
+	envString("FINFOCUS_AZURE_USER_AGENT", &clientConfig.UserAgent)
+
+	return errors.Join(
+		envInt("FINFOCUS_AZURE_RETRY_MAX", &clientConfig.RetryMax),
+		envDuration("FINFOCUS_AZURE_RETRY_WAIT_MIN", &clientConfig.RetryWaitMin),
+		envDuration("FINFOCUS_AZURE_RETRY_WAIT_MAX", &clientConfig.RetryWaitMax),
+		envDuration("FINFOCUS_AZURE_TIMEOUT", &clientConfig.Timeout),
+		envInt("FINFOCUS_AZURE_MAX_PAGES", &clientConfig.MaxPages),
+		envInt("FINFOCUS_AZURE_SUPERSET_MAX_PAGES", &clientConfig.SupersetMaxPages),
+		envInt64("FINFOCUS_AZURE_MAX_RESPONSE_BODY_BYTES", &clientConfig.MaxResponseBodyBytes),
+		envInt("FINFOCUS_CACHE_MAX_SIZE", &cacheConfig.MaxSize),
+		envDuration("FINFOCUS_CACHE_TTL", &cacheConfig.TTL),
+		envDuration("FINFOCUS_CACHE_EXPIRES_AT_TTL", &cacheConfig.ExpiresAtTTL),
+		envDuration("FINFOCUS_CACHE_SOFT_TTL", &cacheConfig.SoftTTL),
+		envBool("FINFOCUS_CACHE_STALE_WHILE_REVALIDATE", &cacheConfig.StaleWhileRevalidate),
+		envDuration("FINFOCUS_CACHE_NEGATIVE_TTL", &cacheConfig.NegativeTTL),
+	)
+}
+
+// envString sets *dst to the value of name when it is set.
+func envString(name string, dst *string) {
+	if val := os.Getenv(name); val != "" {
+		*dst = val
+	}
+}
+
+// envInt sets *dst to the integer value of name when it is set.
+func envInt(name string, dst *int) error {
+	val := os.Getenv(name)
+	if val == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %q is not an integer: %w", name, val, err)
+	}
+	*dst = n
+	return nil
+}
+
+// envInt64 sets *dst to the 64-bit integer value of name when it is set.
+func envInt64(name string, dst *int64) error {
+	val := os.Getenv(name)
+	if val == "" {
+		return nil
+	}
+	n, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %q is not an integer: %w", name, val, err)
+	}
+	*dst = n
+	return nil
+}
+
+// envDuration sets *dst to the duration value of name (e.g., "30s") when it
+// is set.
+func envDuration(name string, dst *time.Duration) error {
+	val := os.Getenv(name)
+	if val == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %q is not a duration: %w", name, val, err)
+	}
+	*dst = d
+	return nil
+}
+
+// envBool sets *dst to the boolean value of name (e.g., "true", "0") when it
+// is set.
+func envBool(name string, dst *bool) error {
+	val := os.Getenv(name)
+	if val == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %q is not a boolean: %w", name, val, err)
+	}
+	*dst = b
+	return nil
+}
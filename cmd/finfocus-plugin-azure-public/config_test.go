@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// writeConfigFile writes content to a temporary file and points
+// FINFOCUS_CONFIG_FILE at it.
+func writeConfigFile(t *testing.T, name, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	t.Setenv(configFileEnv, path)
+}
+
+// TestLoadConfig_Defaults verifies that with nothing configured the loader
+// returns the package defaults.
+func TestLoadConfig_Defaults(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+
+	clientConfig, cacheConfig, err := loadConfig(zerolog.Nop())
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	wantClient := azureclient.DefaultConfig()
+	if clientConfig.BaseURL != wantClient.BaseURL || clientConfig.Timeout != wantClient.Timeout ||
+		clientConfig.RetryMax != wantClient.RetryMax || clientConfig.UserAgent != wantClient.UserAgent {
+		t.Errorf("client config = %+v, want defaults %+v", clientConfig, wantClient)
+	}
+	wantCache := azureclient.DefaultCacheConfig()
+	if cacheConfig.MaxSize != wantCache.MaxSize || cacheConfig.TTL != wantCache.TTL ||
+		cacheConfig.ExpiresAtTTL != wantCache.ExpiresAtTTL {
+		t.Errorf("cache config = %+v, want defaults %+v", cacheConfig, wantCache)
+	}
+}
+
+// TestLoadConfig_Env verifies that every client and cache setting is read
+// from its environment variable.
+func TestLoadConfig_Env(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+	t.Setenv("FINFOCUS_AZURE_BASE_URL", "http://proxy.internal/prices")
+	t.Setenv("FINFOCUS_AZURE_RETRY_MAX", "5")
+	t.Setenv("FINFOCUS_AZURE_RETRY_WAIT_MIN", "2s")
+	t.Setenv("FINFOCUS_AZURE_RETRY_WAIT_MAX", "10s")
+	t.Setenv("FINFOCUS_AZURE_TIMEOUT", "15s")
+	t.Setenv("FINFOCUS_AZURE_USER_AGENT", "custom-agent")
+	t.Setenv("FINFOCUS_AZURE_MAX_PAGES", "20")
+	t.Setenv("FINFOCUS_AZURE_SUPERSET_MAX_PAGES", "200")
+	t.Setenv("FINFOCUS_AZURE_MAX_RESPONSE_BODY_BYTES", "1048576")
+	t.Setenv("FINFOCUS_CACHE_MAX_SIZE", "50")
+	t.Setenv("FINFOCUS_CACHE_EXPIRES_AT_TTL", "1h")
+	t.Setenv("FINFOCUS_CACHE_TTL", "2h")
+	t.Setenv("FINFOCUS_CACHE_SOFT_TTL", "30m")
+	t.Setenv("FINFOCUS_CACHE_STALE_WHILE_REVALIDATE", "true")
+	t.Setenv("FINFOCUS_CACHE_NEGATIVE_TTL", "2m")
+
+	clientConfig, cacheConfig, err := loadConfig(zerolog.Nop())
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	if clientConfig.BaseURL != "http://proxy.internal/prices" {
+		t.Errorf("BaseURL = %q", clientConfig.BaseURL)
+	}
+	if clientConfig.RetryMax != 5 || clientConfig.RetryWaitMin != 2*time.Second ||
+		clientConfig.RetryWaitMax != 10*time.Second {
+		t.Errorf("retry settings = %d, %v, %v", clientConfig.RetryMax, clientConfig.RetryWaitMin, clientConfig.RetryWaitMax)
+	}
+	if clientConfig.Timeout != 15*time.Second || clientConfig.UserAgent != "custom-agent" {
+		t.Errorf("Timeout = %v, UserAgent = %q", clientConfig.Timeout, clientConfig.UserAgent)
+	}
+	if clientConfig.MaxPages != 20 || clientConfig.SupersetMaxPages != 200 ||
+		clientConfig.MaxResponseBodyBytes != 1048576 {
+		t.Errorf("fetch limits = %d, %d, %d",
+			clientConfig.MaxPages, clientConfig.SupersetMaxPages, clientConfig.MaxResponseBodyBytes)
+	}
+	if cacheConfig.MaxSize != 50 || cacheConfig.ExpiresAtTTL != time.Hour || cacheConfig.TTL != 2*time.Hour {
+		t.Errorf("cache settings = %d, %v, %v", cacheConfig.MaxSize, cacheConfig.ExpiresAtTTL, cacheConfig.TTL)
+	}
+	if cacheConfig.SoftTTL != 30*time.Minute || !cacheConfig.StaleWhileRevalidate {
+		t.Errorf("stale settings = %v, %v", cacheConfig.SoftTTL, cacheConfig.StaleWhileRevalidate)
+	}
+	if cacheConfig.NegativeTTL != 2*time.Minute {
+		t.Errorf("NegativeTTL = %v, want 2m", cacheConfig.NegativeTTL)
+	}
+}
+
+// TestLoadConfig_ZeroCacheTTL verifies that "0s" is accepted and disables
+// caching rather than being replaced by the default.
+func TestLoadConfig_ZeroCacheTTL(t *testing.T) {
+	t.Setenv(configFileEnv, "")
+	t.Setenv("FINFOCUS_CACHE_TTL", "0s")
+
+	_, cacheConfig, err := loadConfig(zerolog.Nop())
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if cacheConfig.TTL != 0 {
+		t.Errorf("TTL = %v, want 0", cacheConfig.TTL)
+	}
+}
+
+// TestLoadConfig_File verifies that YAML and JSON files are read and that
+// environment variables take precedence over them.
+func TestLoadConfig_File(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: "base_url: https://proxy.example.com/prices\n" +
+				"timeout: 30s\n" +
+				"retry_max: 1\n" +
+				"cache_max_size: 10\n" +
+				"cache_ttl: 1h\n" +
+				"cache_soft_ttl: 30m\n" +
+				"cache_stale_while_revalidate: true\n" +
+				"cache_negative_ttl: 2m\n",
+		},
+		{
+			name: "json",
+			file: "config.json",
+			content: `{"base_url": "https://proxy.example.com/prices", "timeout": "30s",` +
+				` "retry_max": 1, "cache_max_size": 10, "cache_ttl": "1h", "cache_soft_ttl": "30m",` +
+				` "cache_stale_while_revalidate": true, "cache_negative_ttl": "2m"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfigFile(t, tt.file, tt.content)
+			t.Setenv("FINFOCUS_AZURE_RETRY_MAX", "4")
+
+			clientConfig, cacheConfig, err := loadConfig(zerolog.Nop())
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if clientConfig.BaseURL != "https://proxy.example.com/prices" || clientConfig.Timeout != 30*time.Second {
+				t.Errorf("BaseURL = %q, Timeout = %v", clientConfig.BaseURL, clientConfig.Timeout)
+			}
+			if clientConfig.RetryMax != 4 {
+				t.Errorf("RetryMax = %d, want env value 4", clientConfig.RetryMax)
+			}
+			if cacheConfig.MaxSize != 10 || cacheConfig.TTL != time.Hour {
+				t.Errorf("cache MaxSize = %d, TTL = %v", cacheConfig.MaxSize, cacheConfig.TTL)
+			}
+			if cacheConfig.SoftTTL != 30*time.Minute || !cacheConfig.StaleWhileRevalidate ||
+				cacheConfig.NegativeTTL != 2*time.Minute {
+				t.Errorf("cache SoftTTL = %v, StaleWhileRevalidate = %v, NegativeTTL = %v",
+					cacheConfig.SoftTTL, cacheConfig.StaleWhileRevalidate, cacheConfig.NegativeTTL)
+			}
+		})
+	}
+}
+
+// TestLoadConfig_InvalidFailsFast verifies that malformed and out-of-range
+// values are errors rather than silently replaced by defaults.
+func TestLoadConfig_InvalidFailsFast(t *testing.T) {
+	tests := []struct {
+		name              string
+		env               map[string]string
+		wantInvalidConfig bool
+	}{
+		{name: "non_numeric_retry_max", env: map[string]string{"FINFOCUS_AZURE_RETRY_MAX": "many"}},
+		{name: "bad_timeout", env: map[string]string{"FINFOCUS_AZURE_TIMEOUT": "soon"}},
+		{name: "zero_timeout", env: map[string]string{"FINFOCUS_AZURE_TIMEOUT": "0s"}, wantInvalidConfig: true},
+		{name: "negative_retry_max", env: map[string]string{"FINFOCUS_AZURE_RETRY_MAX": "-1"}, wantInvalidConfig: true},
+		{
+			name:              "wait_min_above_max",
+			env:               map[string]string{"FINFOCUS_AZURE_RETRY_WAIT_MIN": "1m", "FINFOCUS_AZURE_RETRY_WAIT_MAX": "1s"},
+			wantInvalidConfig: true,
+		},
+		{name: "relative_base_url", env: map[string]string{"FINFOCUS_AZURE_BASE_URL": "prices"}, wantInvalidConfig: true},
+		{name: "negative_cache_size", env: map[string]string{"FINFOCUS_CACHE_MAX_SIZE": "-1"}, wantInvalidConfig: true},
+		{name: "bad_cache_ttl", env: map[string]string{"FINFOCUS_CACHE_TTL": "banana"}},
+		{name: "unitless_cache_ttl", env: map[string]string{"FINFOCUS_CACHE_TTL": "42"}},
+		{name: "negative_cache_ttl", env: map[string]string{"FINFOCUS_CACHE_TTL": "-5s"}, wantInvalidConfig: true},
+		{name: "bad_soft_ttl", env: map[string]string{"FINFOCUS_CACHE_SOFT_TTL": "soon"}},
+		{name: "negative_soft_ttl", env: map[string]string{"FINFOCUS_CACHE_SOFT_TTL": "-1h"}, wantInvalidConfig: true},
+		{name: "soft_ttl_above_ttl", env: map[string]string{"FINFOCUS_CACHE_SOFT_TTL": "25h"}, wantInvalidConfig: true},
+		{name: "bad_stale_while_revalidate", env: map[string]string{"FINFOCUS_CACHE_STALE_WHILE_REVALIDATE": "maybe"}},
+		{name: "bad_negative_ttl", env: map[string]string{"FINFOCUS_CACHE_NEGATIVE_TTL": "soon"}},
+		{name: "negative_negative_ttl", env: map[string]string{"FINFOCUS_CACHE_NEGATIVE_TTL": "-1m"}, wantInvalidConfig: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(configFileEnv, "")
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			_, _, err := loadConfig(zerolog.Nop())
+			if err == nil {
+				t.Fatal("loadConfig() error = nil, want error")
+			}
+			// Values that parse but fail Validate wrap ErrInvalidConfig.
+			if got := errors.Is(err, azureclient.ErrInvalidConfig); got != tt.wantInvalidConfig {
+				t.Errorf("errors.Is(%v, ErrInvalidConfig) = %v, want %v", err, got, tt.wantInvalidConfig)
+			}
+		})
+	}
+}
+
+// TestLoadConfig_InvalidFile verifies that unreadable files and unknown keys
+// are errors.
+func TestLoadConfig_InvalidFile(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		t.Setenv(configFileEnv, filepath.Join(t.TempDir(), "missing.yaml"))
+		if _, _, err := loadConfig(zerolog.Nop()); err == nil {
+			t.Error("loadConfig() error = nil, want error")
+		}
+	})
+
+	t.Run("unknown_key", func(t *testing.T) {
+		writeConfigFile(t, "config.yaml", "timout: 30s\n")
+		_, _, err := loadConfig(zerolog.Nop())
+		if err == nil || !strings.Contains(err.Error(), "timout") {
+			t.Errorf("loadConfig() error = %v, want unknown key error", err)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		writeConfigFile(t, "config.yaml", "")
+		if _, _, err := loadConfig(zerolog.Nop()); err != nil {
+			t.Errorf("loadConfig() error = %v, want nil", err)
+		}
+	})
+}
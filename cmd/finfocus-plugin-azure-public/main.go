@@ -9,16 +9,29 @@ import (
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	"google.golang.org/grpc"
 
 	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/metrics"
 	"github.com/rshade/finfocus-plugin-azure-public/internal/pricing"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/tracing"
 )
 
+// pluginName identifies the plugin to the host and in metrics.
+const pluginName = "finfocus-plugin-azure-public"
+
+// maxPort is the highest TCP port number.
+const maxPort = 65535
+
+// tracingShutdownTimeout bounds flushing buffered spans at exit.
+const tracingShutdownTimeout = 5 * time.Second
+
 // version is the plugin version, set at build time via ldflags.
 // Example: go build -ldflags "-X main.version=1.0.0" ./cmd/finfocus-plugin-azure-public.
 var version = "dev"
@@ -77,9 +90,43 @@ func run() error {
 		}
 	}
 
+	// Set up tracing; spans are exported over OTLP when it is configured.
+	shutdownTracing, otlpEnabled, err := tracing.Setup(context.Background(), pluginName, version)
+	if err != nil {
+		logger.Error().Err(err).Msg("failed to set up tracing")
+		return err
+	}
+	defer func() {
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancelShutdown()
+		if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+			logger.Warn().Err(shutdownErr).Msg("tracing shutdown failed")
+		}
+	}()
+	logger.Debug().Bool("otlp_export", otlpEnabled).Msg("tracing configured")
+
+	// Opt-in Prometheus metrics.
+	metricsPort, err := parseMetricsPort()
+	if err != nil {
+		logger.Error().Err(err).Msg("FINFOCUS_METRICS_PORT must be a port number")
+		return err
+	}
+	var pluginMetrics *metrics.Metrics
+	if metricsPort > 0 {
+		pluginMetrics = metrics.New(pluginName)
+	}
+
+	// Load Azure client and cache configuration; invalid values fail startup.
+	clientConfig, cacheConfig, err := loadConfig(logger)
+	if err != nil {
+		logger.Error().Err(err).Msg("invalid configuration")
+		return err
+	}
+
 	// Build Azure pricing client.
-	clientConfig := azureclient.DefaultConfig()
-	clientConfig.Logger = logger
+	if pluginMetrics != nil {
+		clientConfig.Observer = pluginMetrics
+	}
 
 	client, err := azureclient.NewClient(clientConfig)
 	if err != nil {
@@ -88,10 +135,9 @@ func run() error {
 	}
 
 	// Build cache wrapper around Azure pricing client.
-	cacheConfig := azureclient.DefaultCacheConfig()
-	cacheConfig.Logger = logger
-	cacheConfig.TTL = parseCacheTTL(logger)
-	logger.Info().Str("cache_ttl", cacheConfig.TTL.String()).Msg("effective cache TTL")
+	if pluginMetrics != nil {
+		cacheConfig.Observer = pluginMetrics
+	}
 	cachedClient, err := azureclient.NewCachedClient(client, cacheConfig)
 	if err != nil {
 		client.Close()
@@ -100,6 +146,30 @@ func run() error {
 	}
 	defer cachedClient.Close()
 
+	interceptors := []grpc.UnaryServerInterceptor{tracing.UnaryServerInterceptor()}
+	if pluginMetrics != nil {
+		pluginMetrics.RegisterCache(cachedClient)
+		interceptors = append(interceptors, pluginMetrics.UnaryInterceptor())
+
+		metricsServer, metricsErr := pluginsdk.StartMetricsServer(pluginsdk.MetricsServerConfig{
+			Port:     metricsPort,
+			Registry: pluginMetrics.Registry(),
+		})
+		if metricsErr != nil {
+			logger.Error().Int("metrics_port", metricsPort).Err(metricsErr).Msg("failed to start metrics server")
+			return metricsErr
+		}
+		defer func() {
+			if shutdownErr := metricsServer.Shutdown(context.Background()); shutdownErr != nil {
+				logger.Warn().Err(shutdownErr).Msg("metrics server shutdown failed")
+			}
+		}()
+		logger.Info().
+			Int("metrics_port", metricsPort).
+			Str("metrics_path", pluginsdk.DefaultMetricsPath).
+			Msg("metrics endpoint enabled")
+	}
+
 	// Create plugin instance with logger and cache-aware client.
 	azurePlugin := pricing.NewCalculator(logger, cachedClient)
 
@@ -116,12 +186,18 @@ func run() error {
 		cancel()
 	}()
 
+	// Warm the cache in the background while serving.
+	if queries := warmupQueries(logger); len(queries) > 0 {
+		go warmCache(ctx, logger, cachedClient, queries, parseWarmupConcurrency(logger))
+	}
+
 	// Serve using pluginsdk
 	config := pluginsdk.ServeConfig{
-		Plugin: azurePlugin,
-		Port:   port,
+		Plugin:            azurePlugin,
+		Port:              port,
+		UnaryInterceptors: interceptors,
 		PluginInfo: &pluginsdk.PluginInfo{
-			Name:        "finfocus-plugin-azure-public",
+			Name:        pluginName,
 			Version:     version,
 			SpecVersion: pluginsdk.SpecVersion,
 			Providers:   []string{"azure"},
@@ -140,28 +216,36 @@ func run() error {
 	return nil
 }
 
-// parseCacheTTL reads the FINFOCUS_CACHE_TTL environment variable and returns
-// the configured cache TTL duration. Returns the default TTL (24h) when the
-// variable is unset, empty, negative, or contains an invalid duration string.
-// Returns 0 for "0s", which disables caching.
-func parseCacheTTL(logger zerolog.Logger) time.Duration {
-	defaultTTL := azureclient.DefaultCacheConfig().TTL
+// parseSupersetServices reads FINFOCUS_CACHE_SUPERSET_SERVICES, a
+// comma-separated list of services (e.g., "Managed Disks") whose prices are
+// fetched once per region and filtered in memory. Returns nil (superset
+// caching disabled) when the variable is unset or lists no services.
+func parseSupersetServices() []string {
+	var services []string
+	for _, service := range strings.Split(os.Getenv("FINFOCUS_CACHE_SUPERSET_SERVICES"), ",") {
+		if service = strings.TrimSpace(service); service != "" {
+			services = append(services, service)
+		}
+	}
+	return services
+}
 
-	val := os.Getenv("FINFOCUS_CACHE_TTL")
+// parseMetricsPort reads FINFOCUS_METRICS_PORT, the port of the opt-in
+// Prometheus /metrics endpoint. Returns 0 (metrics disabled) when the
+// variable is unset, and an error when it is not a valid port number.
+func parseMetricsPort() (int, error) {
+	val := os.Getenv("FINFOCUS_METRICS_PORT")
 	if val == "" {
-		return defaultTTL
+		return 0, nil
 	}
 
-	d, err := time.ParseDuration(val)
+	port, err := strconv.Atoi(val)
 	if err != nil {
-		logger.Warn().Str("value", val).Err(err).Msg("invalid FINFOCUS_CACHE_TTL, using default")
-		return defaultTTL
+		return 0, fmt.Errorf("invalid FINFOCUS_METRICS_PORT: %q is not numeric: %w", val, err)
 	}
-
-	if d < 0 {
-		logger.Warn().Str("value", val).Msg("negative FINFOCUS_CACHE_TTL, using default")
-		return defaultTTL
+	if port < 1 || port > maxPort {
+		return 0, fmt.Errorf("invalid FINFOCUS_METRICS_PORT: %d is outside 1-%d", port, maxPort)
 	}
 
-	return d
+	return port, nil
 }
@@ -5,20 +5,20 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
-
-	"github.com/rs/zerolog"
-
-	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
 )
 
 //nolint:gochecknoglobals // Test fixtures require package-level state for sync.Once pattern.
@@ -1265,128 +1265,100 @@ func TestRapidStartupShutdown(t *testing.T) {
 }
 
 // =============================================================================
-// User Story: Cache TTL Environment Variable Tests (T001-T005)
+// User Story: Cache Environment Variable Tests
 // =============================================================================
 
-// TestParseCacheTTL_ValidDurations verifies parseCacheTTL correctly parses
-// valid Go duration strings from the FINFOCUS_CACHE_TTL env var.
-func TestParseCacheTTL_ValidDurations(t *testing.T) {
-	tests := []struct {
-		name     string
-		envValue string
-		want     time.Duration
-	}{
-		{name: "seconds", envValue: "10s", want: 10 * time.Second},
-		{name: "hours", envValue: "1h", want: time.Hour},
-		{name: "milliseconds", envValue: "500ms", want: 500 * time.Millisecond},
-		{name: "minutes", envValue: "30m", want: 30 * time.Minute},
-		{name: "complex", envValue: "1h30m", want: 90 * time.Minute},
+// TestParseSupersetServices verifies list parsing with blank entries dropped.
+func TestParseSupersetServices(t *testing.T) {
+	tests := map[string][]string{
+		"":                                   nil,
+		" , ":                                nil,
+		"Managed Disks":                      {"Managed Disks"},
+		"Managed Disks, Storage ,":           {"Managed Disks", "Storage"},
+		"Virtual Machines,Azure App Service": {"Virtual Machines", "Azure App Service"},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Setenv("FINFOCUS_CACHE_TTL", tt.envValue)
-			logger := zerolog.Nop()
-			got := parseCacheTTL(logger)
-			if got != tt.want {
-				t.Errorf("parseCacheTTL(%q) = %v, want %v", tt.envValue, got, tt.want)
-			}
-		})
+	for envValue, want := range tests {
+		t.Setenv("FINFOCUS_CACHE_SUPERSET_SERVICES", envValue)
+
+		if got := parseSupersetServices(); !slices.Equal(got, want) {
+			t.Errorf("parseSupersetServices(%q) = %q, want %q", envValue, got, want)
+		}
 	}
 }
 
-// TestParseCacheTTL_InvalidFallsBackToDefault verifies invalid values produce
-// a warning and fall back to the default 24h TTL.
-func TestParseCacheTTL_InvalidFallsBackToDefault(t *testing.T) {
-	defaultTTL := azureclient.DefaultCacheConfig().TTL
-
+// TestParseMetricsPort verifies that metrics stay disabled when unset and
+// that invalid ports are rejected.
+func TestParseMetricsPort(t *testing.T) {
 	tests := []struct {
-		name     string
 		envValue string
+		want     int
+		wantErr  bool
 	}{
-		{name: "word", envValue: "banana"},
-		{name: "number_without_unit", envValue: "42"},
-		{name: "empty_string_with_spaces", envValue: "  "},
+		{envValue: "", want: 0},
+		{envValue: "9090", want: 9090},
+		{envValue: "metrics", wantErr: true},
+		{envValue: "0", wantErr: true},
+		{envValue: "70000", wantErr: true},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Setenv("FINFOCUS_CACHE_TTL", tt.envValue)
-
-			var buf bytes.Buffer
-			logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
+		t.Setenv("FINFOCUS_METRICS_PORT", tt.envValue)
 
-			got := parseCacheTTL(logger)
-			if got != defaultTTL {
-				t.Errorf("parseCacheTTL(%q) = %v, want default %v", tt.envValue, got, defaultTTL)
-			}
-
-			// Verify warning was logged
-			if buf.Len() == 0 {
-				t.Errorf("expected warning log for invalid value %q, got none", tt.envValue)
-			}
-		})
+		got, err := parseMetricsPort()
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseMetricsPort(%q) = %d, %v; want %d, error %v", tt.envValue, got, err, tt.want, tt.wantErr)
+		}
 	}
 }
 
-// TestParseCacheTTL_UnsetUsesDefault verifies that when FINFOCUS_CACHE_TTL
-// is not set, the default 24h TTL is returned.
-func TestParseCacheTTL_UnsetUsesDefault(t *testing.T) {
-	// t.Setenv is not called, so the env var is implicitly unset
-	// (test binary clears inherited env vars for this key)
-	t.Setenv("FINFOCUS_CACHE_TTL", "")
-	os.Unsetenv("FINFOCUS_CACHE_TTL")
-
-	defaultTTL := azureclient.DefaultCacheConfig().TTL
-	logger := zerolog.Nop()
-
-	got := parseCacheTTL(logger)
-	if got != defaultTTL {
-		t.Errorf("parseCacheTTL(unset) = %v, want default %v", got, defaultTTL)
+// TestMetricsEndpointServesPluginMetrics verifies that FINFOCUS_METRICS_PORT
+// exposes the cache and RPC metrics over HTTP.
+func TestMetricsEndpointServesPluginMetrics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
 	}
-}
 
-// TestParseCacheTTL_ZeroDisablesCache verifies that "0s" returns 0
-// which causes NewCachedClient to disable caching.
-func TestParseCacheTTL_ZeroDisablesCache(t *testing.T) {
-	t.Setenv("FINFOCUS_CACHE_TTL", "0s")
-	logger := zerolog.Nop()
+	binaryPath := buildTestBinary(t)
 
-	got := parseCacheTTL(logger)
-	if got != 0 {
-		t.Errorf("parseCacheTTL(\"0s\") = %v, want 0", got)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to reserve a port: %v", err)
 	}
-}
-
-// TestParseCacheTTL_NegativeFallsBackToDefault verifies that negative durations
-// are treated as invalid and fall back to the default TTL.
-func TestParseCacheTTL_NegativeFallsBackToDefault(t *testing.T) {
-	defaultTTL := azureclient.DefaultCacheConfig().TTL
+	metricsPort := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	listener.Close()
 
-	tests := []struct {
-		name     string
-		envValue string
-	}{
-		{name: "negative_seconds", envValue: "-5s"},
-		{name: "negative_hours", envValue: "-1h"},
+	cmd := exec.Command(binaryPath)
+	cmd.Env = append(os.Environ(), "FINFOCUS_METRICS_PORT="+metricsPort)
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start plugin: %v", err)
 	}
+	defer func() {
+		cmd.Process.Signal(syscall.SIGTERM)
+		cmd.Wait()
+	}()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Setenv("FINFOCUS_CACHE_TTL", tt.envValue)
-
-			var buf bytes.Buffer
-			logger := zerolog.New(&buf).Level(zerolog.WarnLevel)
-
-			got := parseCacheTTL(logger)
-			if got != defaultTTL {
-				t.Errorf("parseCacheTTL(%q) = %v, want default %v", tt.envValue, got, defaultTTL)
+	var body []byte
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		resp, getErr := http.Get("http://127.0.0.1:" + metricsPort + "/metrics")
+		if getErr == nil {
+			body, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				t.Fatalf("failed to read metrics: %v", err)
 			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("metrics endpoint never came up: %v", getErr)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
 
-			// Verify warning was logged
-			if buf.Len() == 0 {
-				t.Errorf("expected warning log for negative value %q, got none", tt.envValue)
-			}
-		})
+	for _, name := range []string{"finfocus_azure_cache_hits_total", "finfocus_azure_cache_entries"} {
+		if !strings.Contains(string(body), name) {
+			t.Errorf("metrics output missing %s", name)
+		}
 	}
 }
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+const (
+	// defaultWarmupConcurrency bounds the warm-up requests in flight.
+	defaultWarmupConcurrency = 4
+
+	// warmupServiceName and warmupCurrency match the calculator defaults, so
+	// warmed entries share cache keys with the lookups they anticipate.
+	warmupServiceName = "Virtual Machines"
+	warmupCurrency    = "USD"
+)
+
+// warmupQueries reads the cache warm-up list from FINFOCUS_CACHE_WARMUP, a
+// comma-separated list of tuples, and FINFOCUS_CACHE_WARMUP_FILE, a file
+// with one tuple per line ("#" starts a comment). A tuple is
+// "region/service/sku" or "region/sku", which prices a VM size. Invalid
+// tuples and an unreadable file are logged and skipped.
+func warmupQueries(logger zerolog.Logger) []azureclient.PriceQuery {
+	var queries []azureclient.PriceQuery
+
+	if val := os.Getenv("FINFOCUS_CACHE_WARMUP"); val != "" {
+		for _, entry := range strings.Split(val, ",") {
+			queries = appendWarmupQuery(logger, queries, entry, "FINFOCUS_CACHE_WARMUP")
+		}
+	}
+
+	if path := os.Getenv("FINFOCUS_CACHE_WARMUP_FILE"); path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			logger.Warn().Str("path", path).Err(err).Msg("cannot open FINFOCUS_CACHE_WARMUP_FILE, skipping it")
+			return queries
+		}
+		defer file.Close()
+
+		fileQueries, err := readWarmupFile(logger, file, path)
+		if err != nil {
+			logger.Warn().Str("path", path).Err(err).Msg("cannot read FINFOCUS_CACHE_WARMUP_FILE, skipping it")
+			return queries
+		}
+		queries = append(queries, fileQueries...)
+	}
+
+	return queries
+}
+
+// readWarmupFile parses one warm-up tuple per line of r.
+func readWarmupFile(logger zerolog.Logger, r io.Reader, source string) ([]azureclient.PriceQuery, error) {
+	var queries []azureclient.PriceQuery
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		entry, _, _ := strings.Cut(scanner.Text(), "#")
+		queries = appendWarmupQuery(logger, queries, entry, source+":"+strconv.Itoa(line))
+	}
+	return queries, scanner.Err()
+}
+
+// appendWarmupQuery parses entry and appends its query. Blank entries are
+// ignored and invalid ones are logged.
+func appendWarmupQuery(
+	logger zerolog.Logger,
+	queries []azureclient.PriceQuery,
+	entry, source string,
+) []azureclient.PriceQuery {
+	if strings.TrimSpace(entry) == "" {
+		return queries
+	}
+	query, err := parseWarmupEntry(entry)
+	if err != nil {
+		logger.Warn().Str("source", source).Str("value", entry).Err(err).Msg("skipping invalid cache warm-up entry")
+		return queries
+	}
+	return append(queries, query)
+}
+
+// parseWarmupEntry parses a "region/service/sku" or "region/sku" tuple. The
+// region is normalized to its armRegionName so warmed entries share cache
+// keys with the lookups they anticipate.
+func parseWarmupEntry(entry string) (azureclient.PriceQuery, error) {
+	parts := strings.Split(entry, "/")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	query := azureclient.PriceQuery{ServiceName: warmupServiceName, CurrencyCode: warmupCurrency}
+	switch len(parts) {
+	case 2:
+		query.ArmRegionName, query.ArmSkuName = parts[0], parts[1]
+	case 3:
+		query.ArmRegionName, query.ArmSkuName = parts[0], parts[2]
+		if parts[1] != "" {
+			query.ServiceName = parts[1]
+		}
+	default:
+		return azureclient.PriceQuery{}, fmt.Errorf("want region/service/sku or region/sku, got %d fields", len(parts))
+	}
+	if query.ArmRegionName == "" || query.ArmSkuName == "" {
+		return azureclient.PriceQuery{}, errors.New("region and sku are required")
+	}
+	region, err := azureclient.NormalizeRegion(query.ArmRegionName)
+	if err != nil {
+		return azureclient.PriceQuery{}, err
+	}
+	query.ArmRegionName = region
+	return query, nil
+}
+
+// parseWarmupConcurrency reads FINFOCUS_CACHE_WARMUP_CONCURRENCY, the number
+// of warm-up requests in flight. Returns the default (4) when the variable is
+// unset, invalid, or not positive.
+func parseWarmupConcurrency(logger zerolog.Logger) int {
+	val := os.Getenv("FINFOCUS_CACHE_WARMUP_CONCURRENCY")
+	if val == "" {
+		return defaultWarmupConcurrency
+	}
+
+	n, err := strconv.Atoi(val)
+	if err != nil || n <= 0 {
+		logger.Warn().
+			Str("value", val).
+			Msg("FINFOCUS_CACHE_WARMUP_CONCURRENCY must be a positive integer, using default")
+		return defaultWarmupConcurrency
+	}
+
+	return n
+}
+
+// warmCache populates the cache with queries. Queries are grouped by region
+// and service so each group is fetched with as few requests as Prefetch
+// allows, and at most concurrency groups are fetched at once. Progress and
+// failures are logged; warm-up stops early when ctx is cancelled.
+func warmCache(
+	ctx context.Context,
+	logger zerolog.Logger,
+	cachedClient *azureclient.CachedClient,
+	queries []azureclient.PriceQuery,
+	concurrency int,
+) {
+	groups := groupWarmupQueries(queries)
+	logger.Info().
+		Int("query_count", len(queries)).
+		Int("group_count", len(groups)).
+		Int("concurrency", concurrency).
+		Msg("cache warm-up started")
+	start := time.Now()
+
+	jobs := make(chan []azureclient.PriceQuery)
+	var (
+		wg             sync.WaitGroup
+		done, failures atomic.Int64
+	)
+	for range min(concurrency, len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				if err := cachedClient.Prefetch(ctx, group); err != nil {
+					failures.Add(1)
+					logger.Warn().
+						Str("region", group[0].ArmRegionName).
+						Str("service", group[0].ServiceName).
+						Err(err).
+						Msg("cache warm-up group failed")
+				}
+				logger.Debug().
+					Int64("groups_done", done.Add(1)).
+					Int("group_count", len(groups)).
+					Msg("cache warm-up progress")
+			}
+		}()
+	}
+
+send:
+	for _, group := range groups {
+		select {
+		case jobs <- group:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	logger.Info().
+		Int64("groups_done", done.Load()).
+		Int64("groups_failed", failures.Load()).
+		Int("group_count", len(groups)).
+		Int("cache_size", cachedClient.Len()).
+		Str("duration", time.Since(start).Round(time.Millisecond).String()).
+		Msg("cache warm-up completed")
+}
+
+// groupWarmupQueries groups queries by region and service, in first-seen
+// order.
+func groupWarmupQueries(queries []azureclient.PriceQuery) [][]azureclient.PriceQuery {
+	var groups [][]azureclient.PriceQuery
+	index := make(map[string]int)
+	for _, query := range queries {
+		key := strings.ToLower(query.ArmRegionName + "|" + query.ServiceName)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], query)
+	}
+	return groups
+}
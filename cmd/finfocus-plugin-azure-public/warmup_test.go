@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestParseWarmupEntry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		entry   string
+		want    azureclient.PriceQuery
+		wantErr bool
+	}{
+		{
+			entry: "eastus/Standard_D2s_v3",
+			want: azureclient.PriceQuery{
+				ArmRegionName: "eastus", ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", CurrencyCode: "USD",
+			},
+		},
+		{
+			entry: " westeurope / Storage / Premium_LRS ",
+			want: azureclient.PriceQuery{
+				ArmRegionName: "westeurope", ArmSkuName: "Premium_LRS", ServiceName: "Storage", CurrencyCode: "USD",
+			},
+		},
+		{
+			entry: "eastus//Standard_B1s",
+			want: azureclient.PriceQuery{
+				ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", CurrencyCode: "USD",
+			},
+		},
+		{
+			entry: "East US/Standard_B1s",
+			want: azureclient.PriceQuery{
+				ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", CurrencyCode: "USD",
+			},
+		},
+		{entry: "eastus", wantErr: true},
+		{entry: "Atlantis North/Standard_B1s", wantErr: true},
+		{entry: "eastus/", wantErr: true},
+		{entry: "a/b/c/d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseWarmupEntry(tt.entry)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseWarmupEntry(%q) error = %v, wantErr %v", tt.entry, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseWarmupEntry(%q) = %+v, want %+v", tt.entry, got, tt.want)
+		}
+	}
+}
+
+func TestWarmupQueries_EnvAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warmup.txt")
+	content := "# expected VM sizes\neastus/Standard_D4s_v3\n\nnot-a-tuple\n" +
+		"westus/Virtual Machines/Standard_B1s # trailing\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write warm-up file: %v", err)
+	}
+	t.Setenv("FINFOCUS_CACHE_WARMUP", "eastus/Standard_D2s_v3, ,eastus")
+	t.Setenv("FINFOCUS_CACHE_WARMUP_FILE", path)
+
+	var skus []string
+	for _, query := range warmupQueries(zerolog.Nop()) {
+		skus = append(skus, query.ArmRegionName+"/"+query.ArmSkuName)
+	}
+	want := "eastus/Standard_D2s_v3,eastus/Standard_D4s_v3,westus/Standard_B1s"
+	if got := strings.Join(skus, ","); got != want {
+		t.Errorf("warmupQueries() = %s, want %s", got, want)
+	}
+}
+
+func TestWarmupQueries_MissingFileKeepsEnvEntries(t *testing.T) {
+	t.Setenv("FINFOCUS_CACHE_WARMUP", "eastus/Standard_D2s_v3")
+	t.Setenv("FINFOCUS_CACHE_WARMUP_FILE", filepath.Join(t.TempDir(), "missing.txt"))
+
+	if got := warmupQueries(zerolog.Nop()); len(got) != 1 {
+		t.Errorf("expected the env entry only, got %+v", got)
+	}
+}
+
+func TestParseWarmupConcurrency(t *testing.T) {
+	tests := map[string]int{"": 4, "8": 8, "0": 4, "-2": 4, "many": 4}
+
+	for envValue, want := range tests {
+		t.Setenv("FINFOCUS_CACHE_WARMUP_CONCURRENCY", envValue)
+
+		if got := parseWarmupConcurrency(zerolog.Nop()); got != want {
+			t.Errorf("parseWarmupConcurrency(%q) = %d, want %d", envValue, got, want)
+		}
+	}
+}
+
+func TestWarmCache_PopulatesCachePerRegion(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		filter := r.URL.Query().Get("$filter")
+		var items []azureclient.PriceItem
+		for _, sku := range []string{"Standard_B1s", "Standard_D2s_v3"} {
+			if strings.Contains(filter, "'"+sku+"'") {
+				items = append(items, azureclient.PriceItem{ArmSkuName: sku, RetailPrice: 0.01})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(azureclient.PriceResponse{Items: items, Count: len(items)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	clientConfig := azureclient.DefaultConfig()
+	clientConfig.BaseURL = server.URL
+	clientConfig.RetryMax = 0
+	clientConfig.Logger = zerolog.Nop()
+	client, err := azureclient.NewClient(clientConfig)
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	cacheConfig := azureclient.DefaultCacheConfig()
+	cacheConfig.TTL = time.Hour
+	cachedClient, err := azureclient.NewCachedClient(client, cacheConfig)
+	if err != nil {
+		t.Fatalf("NewCachedClient() failed: %v", err)
+	}
+	defer cachedClient.Close()
+
+	queries := []azureclient.PriceQuery{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", CurrencyCode: "USD"},
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", CurrencyCode: "USD"},
+		{ArmRegionName: "westus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", CurrencyCode: "USD"},
+	}
+	warmCache(context.Background(), zerolog.Nop(), cachedClient, queries, 2)
+
+	for _, query := range queries {
+		if !cachedClient.Cached(query) {
+			t.Errorf("expected %s to be warmed", azureclient.CacheKey(query))
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected one request per region, got %d", got)
+	}
+}
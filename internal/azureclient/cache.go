@@ -3,11 +3,13 @@ package azureclient
 import (
 	"context"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/golang-lru/v2/expirable"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -15,6 +17,9 @@ const (
 	defaultCacheTTL     = 24 * time.Hour
 	defaultExpiresAtTTL = 4 * time.Hour
 
+	defaultNegativeCacheMaxSize = 1000
+	defaultNegativeCacheTTL     = 5 * time.Minute
+
 	statsRequestInterval = 1000
 	statsTimeInterval    = 5 * time.Minute
 )
@@ -24,43 +29,107 @@ type CachedResult struct {
 	Items     []PriceItem
 	CreatedAt time.Time
 	ExpiresAt time.Time
+	// Stale reports that the result is older than CacheConfig.SoftTTL and was
+	// served while (or instead of) refreshing it.
+	Stale bool
 }
 
 // CacheConfig configures CachedClient behavior.
 type CacheConfig struct {
-	MaxSize      int
+	MaxSize int
+	// TTL is the hard TTL: entries are evicted once they are this old.
 	TTL          time.Duration
 	ExpiresAtTTL time.Duration
-	Logger       zerolog.Logger
+	// SoftTTL is the age after which an entry is stale but, until TTL, can
+	// still be served. Zero disables stale handling.
+	SoftTTL time.Duration
+	// StaleWhileRevalidate serves stale entries immediately and refreshes
+	// them in the background. Otherwise stale entries are refreshed in the
+	// foreground and only served when the refresh fails with
+	// ErrRateLimited, ErrServiceUnavailable, or ErrRequestFailed.
+	StaleWhileRevalidate bool
+	// NegativeMaxSize and NegativeTTL size the cache of ErrNotFound
+	// outcomes, kept apart from prices so a mistyped SKU does not re-query
+	// Azure on every lookup. Either being zero disables negative caching.
+	NegativeMaxSize int
+	NegativeTTL     time.Duration
+	// SupersetServices lists services (e.g., "Managed Disks") whose prices
+	// are fetched and cached as one region+service slice. Queries for a SKU
+	// or product of such a service are answered by filtering the cached
+	// slice in memory. Empty disables superset caching.
+	SupersetServices []string
+	// Observer receives cache eviction events. Nil disables observation.
+	Observer Observer
+	Logger   zerolog.Logger
 }
 
 // DefaultCacheConfig returns cache defaults suitable for production usage.
 func DefaultCacheConfig() CacheConfig {
 	return CacheConfig{
-		MaxSize:      defaultCacheMaxSize,
-		TTL:          defaultCacheTTL,
-		ExpiresAtTTL: defaultExpiresAtTTL,
-		Logger:       zerolog.Nop(),
+		MaxSize:         defaultCacheMaxSize,
+		TTL:             defaultCacheTTL,
+		ExpiresAtTTL:    defaultExpiresAtTTL,
+		NegativeMaxSize: defaultNegativeCacheMaxSize,
+		NegativeTTL:     defaultNegativeCacheTTL,
+		Logger:          zerolog.Nop(),
 	}
 }
 
-// CacheStats tracks cache hits and misses.
+// Validate reports whether c would be accepted by NewCachedClient.
+// Errors wrap ErrInvalidConfig.
+func (c CacheConfig) Validate() error {
+	if c.MaxSize < 0 {
+		return fmt.Errorf("%w: MaxSize must be >= 0", ErrInvalidConfig)
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("%w: TTL must be >= 0", ErrInvalidConfig)
+	}
+	if c.ExpiresAtTTL < 0 {
+		return fmt.Errorf("%w: ExpiresAtTTL must be >= 0", ErrInvalidConfig)
+	}
+	if c.SoftTTL < 0 {
+		return fmt.Errorf("%w: SoftTTL must be >= 0", ErrInvalidConfig)
+	}
+	if c.TTL > 0 && c.SoftTTL > c.TTL {
+		return fmt.Errorf("%w: SoftTTL must be <= TTL", ErrInvalidConfig)
+	}
+	if c.NegativeMaxSize < 0 {
+		return fmt.Errorf("%w: NegativeMaxSize must be >= 0", ErrInvalidConfig)
+	}
+	if c.NegativeTTL < 0 {
+		return fmt.Errorf("%w: NegativeTTL must be >= 0", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// CacheStats tracks cache hits and misses. Coalesced counts the misses that
+// joined an in-flight upstream fetch for the same key instead of starting
+// their own, and Stale counts stale results served. NegativeHits counts
+// lookups answered from the negative cache; they are neither hits nor misses.
 type CacheStats struct {
-	Hits   atomic.Int64
-	Misses atomic.Int64
+	Hits         atomic.Int64
+	Misses       atomic.Int64
+	Coalesced    atomic.Int64
+	Stale        atomic.Int64
+	NegativeHits atomic.Int64
 }
 
 // CachedClient wraps Client with an in-memory thread-safe LRU cache.
 type CachedClient struct {
 	client *Client
 	cache  *expirable.LRU[string, CachedResult]
-	config CacheConfig
-	logger zerolog.Logger
-	stats  CacheStats
+	// negative caches ErrNotFound outcomes; nil when disabled.
+	negative *expirable.LRU[string, error]
+	config   CacheConfig
+	logger   zerolog.Logger
+	stats    CacheStats
 
 	requests    atomic.Int64
 	lastStatsNS atomic.Int64
 	disabled    bool
+
+	inflightMu sync.Mutex
+	inflight   map[string]*inflightFetch
 }
 
 // NewCachedClient creates a cache wrapper around a pricing client.
@@ -68,14 +137,8 @@ func NewCachedClient(client *Client, config CacheConfig) (*CachedClient, error)
 	if client == nil {
 		return nil, fmt.Errorf("%w: client is required", ErrInvalidConfig)
 	}
-	if config.MaxSize < 0 {
-		return nil, fmt.Errorf("%w: MaxSize must be >= 0", ErrInvalidConfig)
-	}
-	if config.TTL < 0 {
-		return nil, fmt.Errorf("%w: TTL must be >= 0", ErrInvalidConfig)
-	}
-	if config.ExpiresAtTTL < 0 {
-		return nil, fmt.Errorf("%w: ExpiresAtTTL must be >= 0", ErrInvalidConfig)
+	if err := config.Validate(); err != nil {
+		return nil, err
 	}
 
 	cc := &CachedClient{
@@ -83,6 +146,7 @@ func NewCachedClient(client *Client, config CacheConfig) (*CachedClient, error)
 		config:   config,
 		logger:   config.Logger,
 		disabled: config.MaxSize == 0 || config.TTL == 0,
+		inflight: make(map[string]*inflightFetch),
 	}
 	cc.lastStatsNS.Store(time.Now().UnixNano())
 
@@ -96,30 +160,60 @@ func NewCachedClient(client *Client, config CacheConfig) (*CachedClient, error)
 				Str("cache_key", key).
 				Str("eviction_reason", reason).
 				Msg("cache entry evicted")
+			if config.Observer != nil {
+				config.Observer.ObserveEviction(reason)
+			}
 		}
 		cc.cache = expirable.NewLRU[string, CachedResult](config.MaxSize, onEvict, config.TTL)
+		if config.NegativeMaxSize > 0 && config.NegativeTTL > 0 {
+			cc.negative = expirable.NewLRU[string, error](config.NegativeMaxSize, nil, config.NegativeTTL)
+		}
 	}
 
 	return cc, nil
 }
 
 // GetPrices returns cached pricing data when available and fresh.
-func (cc *CachedClient) GetPrices(ctx context.Context, query PriceQuery) (CachedResult, error) {
+// Concurrent misses for the same key share a single upstream fetch. Each
+// lookup is traced as a span recording whether it hit the cache.
+func (cc *CachedClient) GetPrices(ctx context.Context, query PriceQuery) (result CachedResult, err error) {
+	ctx, span := tracer().Start(ctx, "CachedClient.GetPrices", trace.WithAttributes(queryAttributes(query)...))
+	defer func() { endSpanWithError(span, err) }()
+
+	return cc.getPrices(ctx, query)
+}
+
+// getPrices performs the GetPrices lookup.
+func (cc *CachedClient) getPrices(ctx context.Context, query PriceQuery) (CachedResult, error) {
+	if fetched := cc.fetchQuery(query); fetched != query {
+		setCacheResult(ctx, cacheResultSuperset, false)
+		return cc.getFromSuperset(ctx, query, fetched)
+	}
 	key := CacheKey(query)
 
 	if !cc.disabled {
 		if cached, ok := cc.cache.Get(key); ok {
+			if cc.isStale(cached) {
+				return cc.getStale(ctx, key, query, cached)
+			}
+			setCacheResult(ctx, cacheResultHit, true)
 			cc.recordHit(key)
 			return cloneCachedResult(cached), nil
 		}
+		if err := cc.negativeHit(key); err != nil {
+			setCacheResult(ctx, cacheResultNegative, true)
+			return CachedResult{}, err
+		}
 	}
+	setCacheResult(ctx, cacheResultMiss, false)
 	cc.recordMiss(key)
 
-	items, err := cc.client.GetPrices(ctx, query)
-	if err != nil {
-		return CachedResult{}, err
-	}
+	return cc.fetchCoalesced(ctx, key, query)
+}
 
+// store caches items under key, unless caching is disabled, and returns the
+// caller-facing result.
+func (cc *CachedClient) store(key string, items []PriceItem) CachedResult {
 	now := time.Now()
 	result := CachedResult{
 		Items:     clonePriceItems(items),
@@ -136,9 +230,22 @@ func (cc *CachedClient) GetPrices(ctx context.Context, query PriceQuery) (Cached
 			}
 		}
 		cc.cache.Add(key, cloneCachedResult(result))
+		cc.forgetNegative(key)
 	}
 
-	return result, nil
+	return result
+}
+
+// Cached reports whether a fresh result for query is cached. It never calls
+// the Azure API and does not affect hit/miss statistics or LRU recency.
+// For a superset service it reports whether the region+service slice is
+// cached.
+func (cc *CachedClient) Cached(query PriceQuery) bool {
+	if cc.disabled || cc.cache == nil {
+		return false
+	}
+	_, ok := cc.cache.Peek(CacheKey(cc.fetchQuery(query)))
+	return ok
 }
 
 // Stats returns cache hit/miss counters.
@@ -159,6 +266,9 @@ func (cc *CachedClient) Close() {
 	if cc.cache != nil {
 		cc.cache.Purge()
 	}
+	if cc.negative != nil {
+		cc.negative.Purge()
+	}
 	cc.client.Close()
 }
 
@@ -204,18 +314,27 @@ func (cc *CachedClient) maybeLogStats(total, hits int64) {
 	}
 
 	misses := cc.stats.Misses.Load()
+	coalesced := cc.stats.Coalesced.Load()
 	denom := hits + misses
 
-	var ratio float64
+	var ratio, coalescedRatio float64
 	if denom > 0 {
 		ratio = float64(hits) / float64(denom)
 	}
+	if misses > 0 {
+		coalescedRatio = float64(coalesced) / float64(misses)
+	}
 
 	cc.logger.Info().
 		Int64("cache_hits", hits).
 		Int64("cache_misses", misses).
 		Float64("cache_hit_ratio", ratio).
+		Int64("cache_coalesced", coalesced).
+		Float64("cache_coalesced_ratio", coalescedRatio).
+		Int64("cache_stale", cc.stats.Stale.Load()).
+		Int64("cache_negative_hits", cc.stats.NegativeHits.Load()).
 		Int("cache_size", cc.Len()).
+		Int("cache_negative_size", cc.negativeLen()).
 		Msg("cache stats")
 }
 
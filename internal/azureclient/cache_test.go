@@ -130,6 +130,54 @@ func TestCachedClientGetPrices_TTLExpiryCausesMiss(t *testing.T) {
 	}
 }
 
+func TestCachedClientCached_ReportsFreshEntriesWithoutStats(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		resp := PriceResponse{
+			Items: []PriceItem{
+				{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD", RetailPrice: 0.0104},
+			},
+			Count: 1,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Fatalf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	baseClient := newTestClient(t, server.URL)
+	cached := newTestCachedClient(t, baseClient, CacheConfig{
+		MaxSize:      100,
+		TTL:          50 * time.Millisecond,
+		ExpiresAtTTL: 4 * time.Hour,
+		Logger:       zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD"}
+	if cached.Cached(query) {
+		t.Fatal("expected query to be uncached before first fetch")
+	}
+	if _, err := cached.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("GetPrices() unexpected error: %v", err)
+	}
+	if !cached.Cached(query) {
+		t.Fatal("expected query to be cached after fetch")
+	}
+
+	stats := cached.Stats()
+	if hits, misses := stats.Hits.Load(), stats.Misses.Load(); hits != 0 || misses != 1 {
+		t.Fatalf("Cached() must not record stats, got hits=%d misses=%d", hits, misses)
+	}
+
+	time.Sleep(70 * time.Millisecond)
+	if cached.Cached(query) {
+		t.Fatal("expected expired entry to be reported as uncached")
+	}
+}
+
 func TestCachedClientGetPrices_ErrorsAreNotCached(t *testing.T) {
 	t.Parallel()
 
@@ -2,7 +2,9 @@ package azureclient
 
 import "strings"
 
-// CacheKey returns a normalized cache key for a pricing query.
+// CacheKey returns a normalized cache key for a pricing query. A non-default
+// price type and an API version are appended as extra segments, so
+// Consumption queries of the default version keep their existing keys.
 func CacheKey(query PriceQuery) string {
 	parts := []string{
 		normalizeKeyPart(query.ArmRegionName),
@@ -11,6 +13,13 @@ func CacheKey(query PriceQuery) string {
 		normalizeKeyPart(query.ServiceName),
 		normalizeKeyPart(query.CurrencyCode),
 	}
+	if priceType := normalizeKeyPart(query.PriceType); priceType != "" &&
+		!strings.EqualFold(priceType, defaultPriceType) {
+		parts = append(parts, priceType)
+	}
+	if apiVersion := normalizeKeyPart(query.APIVersion); apiVersion != "" {
+		parts = append(parts, "api-version="+apiVersion)
+	}
 
 	return strings.Join(parts, "|")
 }
@@ -40,6 +40,32 @@ func TestCacheKey(t *testing.T) {
 			},
 			want: "eastus|standard_b1s|product name|virtual machines|usd",
 		},
+		{
+			name: "appends non-default price type",
+			query: PriceQuery{
+				ArmRegionName: "eastus",
+				ArmSkuName:    "Standard_B1s",
+				PriceType:     " Reservation ",
+			},
+			want: "eastus|standard_b1s||||reservation",
+		},
+		{
+			name: "omits default price type",
+			query: PriceQuery{
+				ArmRegionName: "eastus",
+				PriceType:     "consumption",
+			},
+			want: "eastus||||",
+		},
+		{
+			name: "appends api version",
+			query: PriceQuery{
+				ArmRegionName: "eastus",
+				ArmSkuName:    "Standard_B1s",
+				APIVersion:    SavingsPlanAPIVersion,
+			},
+			want: "eastus|standard_b1s||||api-version=2023-01-01-preview",
+		},
 	}
 
 	for _, tc := range tests {
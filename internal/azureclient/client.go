@@ -14,6 +14,8 @@ import (
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -24,10 +26,6 @@ const (
 	// maxSnippetLen is the maximum length of a response snippet included in error messages.
 	maxSnippetLen = 256
 
-	// maxResponseBodyBytes is the maximum number of bytes to read from a success response body.
-	// Azure API returns max 1000 items per page; 10 MB is a generous safety limit.
-	maxResponseBodyBytes = 10 * 1024 * 1024
-
 	// HTTP transport configuration for production use.
 	transportMaxIdleConns        = 100
 	transportMaxIdleConnsPerHost = 10 // Higher than default (2) for single-host API
@@ -36,10 +34,19 @@ const (
 
 // Client is an HTTP client for querying the Azure Retail Prices API.
 type Client struct {
-	httpClient *retryablehttp.Client
-	baseURL    string
-	userAgent  string
-	logger     zerolog.Logger
+	httpClient     *retryablehttp.Client
+	baseURL        string
+	userAgent      string
+	logger         zerolog.Logger
+	limits         fetchLimits
+	supersetLimits fetchLimits
+	observer       Observer
+}
+
+// fetchLimits bounds the pages and response bytes one query may read.
+type fetchLimits struct {
+	maxPages     int
+	maxBodyBytes int64
 }
 
 // NewClient creates a new Azure Retail Prices API client.
@@ -56,6 +63,15 @@ func NewClient(config Config) (*Client, error) {
 	if config.UserAgent == "" {
 		config.UserAgent = "finfocus-plugin-azure-public"
 	}
+	if config.MaxPages == 0 {
+		config.MaxPages = MaxPaginationPages
+	}
+	if config.SupersetMaxPages == 0 {
+		config.SupersetMaxPages = DefaultSupersetMaxPages
+	}
+	if config.MaxResponseBodyBytes == 0 {
+		config.MaxResponseBodyBytes = DefaultMaxResponseBodyBytes
+	}
 
 	// Create retryable HTTP client with optimized transport for production
 	transport := &http.Transport{
@@ -73,6 +89,15 @@ func NewClient(config Config) (*Client, error) {
 	retryClient.CheckRetry = customRetryPolicy
 	retryClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
 	retryClient.Logger = &zerologAdapter{logger: config.Logger}
+	retryClient.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		if attempt == 0 {
+			return
+		}
+		trace.SpanFromContext(req.Context()).SetAttributes(attribute.Int("http.request.resend_count", attempt))
+		if config.Observer != nil {
+			config.Observer.ObserveRetry()
+		}
+	}
 
 	// Custom backoff that respects Retry-After header
 	retryClient.Backoff = func(minWait, maxWait time.Duration, attemptNum int, resp *http.Response) time.Duration {
@@ -94,6 +119,12 @@ func NewClient(config Config) (*Client, error) {
 		baseURL:    config.BaseURL,
 		userAgent:  config.UserAgent,
 		logger:     config.Logger,
+		limits:     fetchLimits{maxPages: config.MaxPages, maxBodyBytes: config.MaxResponseBodyBytes},
+		supersetLimits: fetchLimits{
+			maxPages:     config.SupersetMaxPages,
+			maxBodyBytes: config.MaxResponseBodyBytes,
+		},
+		observer: config.Observer,
 	}, nil
 }
 
@@ -107,21 +138,72 @@ func (c *Client) Close() {
 
 // GetPrices queries the Azure Retail Prices API with the given filter.
 // It automatically handles pagination and returns all matching price items.
-// A safety limit of 10 pages is enforced to prevent infinite loops.
-// All errors include query context (region, SKU, service) for debugging.
+// A safety limit of Config.MaxPages pages is enforced to prevent infinite
+// loops. All errors include query context (region, SKU, service) for
+// debugging.
 func (c *Client) GetPrices(ctx context.Context, query PriceQuery) ([]PriceItem, error) {
+	return c.getPrices(ctx, query, buildFilterQuery(query), c.limits)
+}
+
+// GetServicePrices fetches every price of query's region and service, with
+// query.ArmSkuName and query.ProductName ignored. Such superset fetches are
+// bounded by Config.SupersetMaxPages instead of Config.MaxPages.
+func (c *Client) GetServicePrices(ctx context.Context, query PriceQuery) ([]PriceItem, error) {
+	query.ArmSkuName = ""
+	query.ProductName = ""
+	return c.getPrices(ctx, query, buildFilterQuery(query), c.supersetLimits)
+}
+
+// GetAllRegionPrices fetches query's prices in every region, with
+// query.ArmRegionName ignored. A SKU priced in every region spans many pages,
+// so such fetches are bounded by Config.SupersetMaxPages instead of
+// Config.MaxPages.
+func (c *Client) GetAllRegionPrices(ctx context.Context, query PriceQuery) ([]PriceItem, error) {
+	query.ArmRegionName = ""
+	return c.getPrices(ctx, query, buildFilterQuery(query), c.supersetLimits)
+}
+
+// GetPricesForSKUs fetches the prices of several SKUs that share the rest of
+// query with a single OData filter whose armSkuName conditions are OR'ed,
+// e.g. "(armSkuName eq 'a' or armSkuName eq 'b')". query.ArmSkuName is
+// ignored. Items are returned unsplit; callers match them by ArmSkuName.
+func (c *Client) GetPricesForSKUs(ctx context.Context, query PriceQuery, skus []string) ([]PriceItem, error) {
+	query.ArmSkuName = strings.Join(skus, ",")
+	return c.getPrices(ctx, query, buildSKUsFilterQuery(query, skus), c.limits)
+}
+
+// getPrices fetches all pages for filter within limits. query supplies the
+// error and log context.
+func (c *Client) getPrices(
+	ctx context.Context,
+	query PriceQuery,
+	filter string,
+	limits fetchLimits,
+) ([]PriceItem, error) {
 	var allItems []PriceItem
 	qctx := formatQueryContext(query)
 
-	// Build initial URL with filter
+	// Build initial URL with filter. Non-USD prices are only converted when
+	// the currency is also passed as a parameter, and savings plan prices
+	// need an api-version; next page links keep both.
+	var params []string
+	if apiVersion := strings.TrimSpace(query.APIVersion); apiVersion != "" {
+		params = append(params, "api-version="+url.QueryEscape(apiVersion))
+	}
+	if currency := currencyParameter(query.CurrencyCode); currency != "" {
+		params = append(params, currency)
+	}
+	if filter != "" {
+		params = append(params, "$filter="+url.QueryEscape(filter))
+	}
 	requestURL := c.baseURL
-	if filter := buildFilterQuery(query); filter != "" {
-		requestURL = fmt.Sprintf("%s?$filter=%s", c.baseURL, url.QueryEscape(filter))
+	if len(params) > 0 {
+		requestURL += "?" + strings.Join(params, "&")
 	}
 
 	// Paginate through all results with safety limit
-	for page := 0; requestURL != "" && page < MaxPaginationPages; page++ {
-		items, nextURL, err := c.fetchPage(ctx, requestURL)
+	for page := 0; requestURL != "" && page < limits.maxPages; page++ {
+		items, nextURL, err := c.fetchPage(ctx, requestURL, page, limits.maxBodyBytes)
 		if err != nil {
 			c.logError(query, requestURL, page, err)
 			return nil, fmt.Errorf("%s page %d: %w", qctx, page, err)
@@ -139,7 +221,7 @@ func (c *Client) GetPrices(ctx context.Context, query PriceQuery) ([]PriceItem,
 
 	// Check if we hit the safety limit
 	if requestURL != "" {
-		err := fmt.Errorf("%s: %w", qctx, ErrPaginationLimitExceeded)
+		err := fmt.Errorf("%s: %w (%d pages)", qctx, ErrPaginationLimitExceeded, limits.maxPages)
 		c.logError(query, requestURL, -1, ErrPaginationLimitExceeded)
 		return nil, err
 	}
@@ -154,8 +236,32 @@ func (c *Client) GetPrices(ctx context.Context, query PriceQuery) ([]PriceItem,
 	return allItems, nil
 }
 
-// fetchPage fetches a single page of results from the API.
-func (c *Client) fetchPage(ctx context.Context, requestURL string) ([]PriceItem, string, error) {
+// fetchPage fetches a single page of results from the API, reading at most
+// maxBodyBytes of a success response. The request is traced as a client span
+// with its page number, HTTP status, and retry attempts, and reported to the
+// observer.
+func (c *Client) fetchPage(
+	ctx context.Context,
+	requestURL string,
+	page int,
+	maxBodyBytes int64,
+) ([]PriceItem, string, error) {
+	ctx, span := tracer().Start(ctx, "Client.fetchPage",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("azure.page", page)),
+	)
+	start := time.Now()
+	items, nextURL, err := c.doFetchPage(ctx, requestURL, maxBodyBytes)
+	if c.observer != nil {
+		c.observer.ObserveRequest(requestCategory(err), time.Since(start))
+	}
+	span.SetAttributes(attribute.Int("azure.items", len(items)))
+	endSpanWithError(span, err)
+	return items, nextURL, err
+}
+
+// doFetchPage performs the page request for fetchPage.
+func (c *Client) doFetchPage(ctx context.Context, requestURL string, maxBodyBytes int64) ([]PriceItem, string, error) {
 	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
 	if err != nil {
 		return nil, "", fmt.Errorf("creating request: %w", err)
@@ -172,6 +278,7 @@ func (c *Client) fetchPage(ctx context.Context, requestURL string) ([]PriceItem,
 		return nil, "", fmt.Errorf("%w: %w", ErrRequestFailed, err)
 	}
 	defer resp.Body.Close()
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
 	// Check for non-success status codes
 	if resp.StatusCode != http.StatusOK {
@@ -194,21 +301,21 @@ func (c *Client) fetchPage(ctx context.Context, requestURL string) ([]PriceItem,
 	}
 
 	// Parse response with bounded read to prevent excessive memory usage
-	bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyBytes))
+	bodyBytes, readErr := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
 	if readErr != nil {
 		return nil, "", fmt.Errorf("%w: reading response: %w", ErrInvalidResponse, readErr)
 	}
 
-	// Detect silent truncation: if we read exactly maxResponseBodyBytes, the response
+	// Detect silent truncation: if we read exactly maxBodyBytes, the response
 	// may have been truncated by io.LimitReader and JSON decoding would fail misleadingly.
-	if int64(len(bodyBytes)) == maxResponseBodyBytes {
+	if int64(len(bodyBytes)) == maxBodyBytes {
 		snippet := string(bodyBytes)
 		if len(snippet) > maxSnippetLen {
 			snippet = snippet[:maxSnippetLen]
 		}
 		return nil, "", fmt.Errorf(
 			"%w: response exceeded %d bytes limit (truncated: %s)",
-			ErrInvalidResponse, maxResponseBodyBytes, snippet,
+			ErrInvalidResponse, maxBodyBytes, snippet,
 		)
 	}
 
@@ -224,8 +331,20 @@ func (c *Client) fetchPage(ctx context.Context, requestURL string) ([]PriceItem,
 	return priceResp.Items, priceResp.NextPageLink, nil
 }
 
+// Validate reports whether c would be accepted by NewClient. Errors wrap
+// ErrInvalidConfig.
+func (c Config) Validate() error {
+	return validateConfig(c)
+}
+
 // validateConfig validates the client configuration.
 func validateConfig(config Config) error {
+	if config.BaseURL != "" {
+		u, err := url.Parse(config.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: BaseURL must be an absolute http(s) URL", ErrInvalidConfig)
+		}
+	}
 	if config.RetryMax < 0 {
 		return fmt.Errorf("%w: RetryMax must be >= 0", ErrInvalidConfig)
 	}
@@ -235,6 +354,12 @@ func validateConfig(config Config) error {
 	if config.RetryWaitMin > config.RetryWaitMax {
 		return fmt.Errorf("%w: RetryWaitMin must be <= RetryWaitMax", ErrInvalidConfig)
 	}
+	if config.MaxPages < 0 || config.SupersetMaxPages < 0 {
+		return fmt.Errorf("%w: MaxPages and SupersetMaxPages must be >= 0", ErrInvalidConfig)
+	}
+	if config.MaxResponseBodyBytes < 0 {
+		return fmt.Errorf("%w: MaxResponseBodyBytes must be >= 0", ErrInvalidConfig)
+	}
 	return nil
 }
 
@@ -250,6 +375,12 @@ func formatQueryContext(query PriceQuery) string {
 	if query.ServiceName != "" {
 		parts = append(parts, "service="+query.ServiceName)
 	}
+	if query.PriceType != "" {
+		parts = append(parts, "priceType="+query.PriceType)
+	}
+	if query.APIVersion != "" {
+		parts = append(parts, "apiVersion="+query.APIVersion)
+	}
 	if len(parts) == 0 {
 		return "query []"
 	}
@@ -403,6 +534,12 @@ func extractResponseSnippet(err error) string {
 	return snippet
 }
 
+// FilterQuery returns the exact OData $filter expression GetPrices sends for
+// query, before URL encoding.
+func FilterQuery(query PriceQuery) string {
+	return buildFilterQuery(query)
+}
+
 // buildFilterQuery builds an OData filter query from a PriceQuery.
 func buildFilterQuery(query PriceQuery) string {
 	return NewFilterBuilder().
@@ -411,5 +548,23 @@ func buildFilterQuery(query PriceQuery) string {
 		Service(query.ServiceName).
 		ProductName(query.ProductName).
 		CurrencyCode(query.CurrencyCode).
+		Type(query.PriceType).
+		Build()
+}
+
+// buildSKUsFilterQuery builds an OData filter matching any of skus, with the
+// remaining PriceQuery fields as AND conditions.
+func buildSKUsFilterQuery(query PriceQuery, skus []string) string {
+	conditions := make([]FilterCondition, 0, len(skus))
+	for _, sku := range skus {
+		conditions = append(conditions, SKU(sku))
+	}
+	return NewFilterBuilder().
+		Region(query.ArmRegionName).
+		Service(query.ServiceName).
+		ProductName(query.ProductName).
+		CurrencyCode(query.CurrencyCode).
+		Type(query.PriceType).
+		Or(conditions...).
 		Build()
 }
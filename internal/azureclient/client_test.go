@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -59,6 +60,36 @@ func TestNewClient_InvalidConfig(t *testing.T) {
 				Timeout:      60 * time.Second,
 			},
 		},
+		{
+			name: "NegativeMaxPages",
+			config: Config{
+				BaseURL:  DefaultBaseURL,
+				Timeout:  60 * time.Second,
+				MaxPages: -1,
+			},
+		},
+		{
+			name: "NegativeMaxResponseBodyBytes",
+			config: Config{
+				BaseURL:              DefaultBaseURL,
+				Timeout:              60 * time.Second,
+				MaxResponseBodyBytes: -1,
+			},
+		},
+		{
+			name: "RelativeBaseURL",
+			config: Config{
+				BaseURL: "prices.azure.com/api/retail/prices",
+				Timeout: 60 * time.Second,
+			},
+		},
+		{
+			name: "NonHTTPBaseURL",
+			config: Config{
+				BaseURL: "ftp://prices.azure.com/api/retail/prices",
+				Timeout: 60 * time.Second,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -67,10 +98,22 @@ func TestNewClient_InvalidConfig(t *testing.T) {
 			if !errors.Is(err, ErrInvalidConfig) {
 				t.Errorf("expected ErrInvalidConfig, got %v", err)
 			}
+			if err := tt.config.Validate(); !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("Validate() = %v, want ErrInvalidConfig", err)
+			}
 		})
 	}
 }
 
+func TestConfigValidate_Defaults(t *testing.T) {
+	if err := DefaultConfig().Validate(); err != nil {
+		t.Errorf("DefaultConfig().Validate() = %v, want nil", err)
+	}
+	if err := DefaultCacheConfig().Validate(); err != nil {
+		t.Errorf("DefaultCacheConfig().Validate() = %v, want nil", err)
+	}
+}
+
 func TestClient_GetPrices_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := PriceResponse{
@@ -115,6 +158,50 @@ func TestClient_GetPrices_Success(t *testing.T) {
 	}
 }
 
+func TestClient_GetPrices_APIVersionOptIn(t *testing.T) {
+	var rawQueries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQueries = append(rawQueries, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items": [{"armSkuName": "Standard_D2s_v3", "retailPrice": 0.096,
+			"savingsPlan": [{"unitPrice": 0.05, "retailPrice": 0.05, "term": "3 Years"},
+				{"unitPrice": 0.07, "retailPrice": 0.07, "term": "1 Year"}]}], "Count": 1}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err = client.GetPrices(context.Background(), PriceQuery{ArmSkuName: "Standard_D2s_v3"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prices, err := client.GetPrices(context.Background(), PriceQuery{
+		ArmSkuName: "Standard_D2s_v3",
+		APIVersion: SavingsPlanAPIVersion,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(rawQueries[0], "api-version") {
+		t.Errorf("default query = %q, want no api-version", rawQueries[0])
+	}
+	if !strings.Contains(rawQueries[1], "api-version="+SavingsPlanAPIVersion) {
+		t.Errorf("savings plan query = %q, want api-version %s", rawQueries[1], SavingsPlanAPIVersion)
+	}
+	want := []SavingsPlanPrice{
+		{UnitPrice: 0.05, RetailPrice: 0.05, Term: "3 Years"},
+		{UnitPrice: 0.07, RetailPrice: 0.07, Term: "1 Year"},
+	}
+	if len(prices) != 1 || !reflect.DeepEqual(prices[0].SavingsPlan, want) {
+		t.Errorf("SavingsPlan = %+v, want %+v", prices, want)
+	}
+}
+
 func TestClient_GetPrices_Pagination(t *testing.T) {
 	callCount := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -482,6 +569,129 @@ func TestClient_GetPrices_ExactlyTenPagesSucceeds(t *testing.T) {
 	}
 }
 
+// newPagingServer serves pages of one item each and reports the filter of
+// every request. Page n links to page n+1 until pages are served.
+func newPagingServer(t *testing.T, pages int, filters *[]string) *httptest.Server {
+	t.Helper()
+
+	callCount := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		callCount++
+		*filters = append(*filters, r.URL.Query().Get("$filter"))
+		resp := PriceResponse{
+			Items: []PriceItem{{ArmSkuName: fmt.Sprintf("SKU-%03d", callCount), CurrencyCode: "USD"}},
+			Count: 1,
+		}
+		if callCount < pages {
+			resp.NextPageLink = fmt.Sprintf("http://%s/page%d", r.Host, callCount+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Logf("error encoding response: %v", err)
+		}
+	}))
+}
+
+func TestClient_GetPrices_ConfiguredMaxPages(t *testing.T) {
+	var filters []string
+	server := newPagingServer(t, 5, &filters)
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.MaxPages = 3
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.GetPrices(context.Background(), PriceQuery{ArmRegionName: "eastus"})
+	if !errors.Is(err, ErrPaginationLimitExceeded) {
+		t.Fatalf("expected ErrPaginationLimitExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "(3 pages)") {
+		t.Errorf("error %q should name the configured limit", err)
+	}
+	if len(filters) != 3 {
+		t.Errorf("expected 3 page requests, got %d", len(filters))
+	}
+}
+
+func TestClient_GetServicePrices_UsesSupersetLimit(t *testing.T) {
+	var filters []string
+	server := newPagingServer(t, 4, &filters)
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.MaxPages = 2
+	config.SupersetMaxPages = 4
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := client.GetServicePrices(context.Background(), PriceQuery{
+		ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks", ProductName: "Premium SSD",
+	})
+	if err != nil {
+		t.Fatalf("GetServicePrices() unexpected error: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 items, got %d", len(items))
+	}
+	if strings.Contains(filters[0], "armSkuName") || strings.Contains(filters[0], "productName") {
+		t.Errorf("superset filter %q must not narrow by SKU or product", filters[0])
+	}
+}
+
+func TestClient_GetAllRegionPrices_OmitsRegionAndUsesSupersetLimit(t *testing.T) {
+	var filters []string
+	server := newPagingServer(t, 4, &filters)
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.MaxPages = 2
+	config.SupersetMaxPages = 4
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	items, err := client.GetAllRegionPrices(context.Background(), PriceQuery{
+		ArmRegionName: "eastus", ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines",
+	})
+	if err != nil {
+		t.Fatalf("GetAllRegionPrices() unexpected error: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("expected 4 items, got %d", len(items))
+	}
+	if strings.Contains(filters[0], "armRegionName") || !strings.Contains(filters[0], "armSkuName eq 'Standard_D2s_v3'") {
+		t.Errorf("all-region filter %q must narrow by SKU but not by region", filters[0])
+	}
+}
+
+func TestClient_GetPrices_ConfiguredMaxResponseBodyBytes(t *testing.T) {
+	var filters []string
+	server := newPagingServer(t, 1, &filters)
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.MaxResponseBodyBytes = 16
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = client.GetPrices(context.Background(), PriceQuery{ArmRegionName: "eastus"})
+	if !errors.Is(err, ErrInvalidResponse) || !strings.Contains(err.Error(), "exceeded 16 bytes") {
+		t.Errorf("expected a 16-byte limit ErrInvalidResponse, got %v", err)
+	}
+}
+
 func TestClient_GetPrices_MultiPageQueryLogsPaginationProgress(t *testing.T) {
 	callCount := 0
 	pageSizes := []int{100, 100, 50}
@@ -916,6 +1126,23 @@ func TestBuildFilterQuery_AllFields(t *testing.T) {
 	}
 }
 
+func TestFilterQuery_MatchesRequestFilter(t *testing.T) {
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD"}
+
+	if got, want := FilterQuery(query), buildFilterQuery(query); got != want {
+		t.Errorf("FilterQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildFilterQuery_PriceType(t *testing.T) {
+	query := PriceQuery{ArmRegionName: "eastus", PriceType: "Reservation"}
+
+	expected := "armRegionName eq 'eastus' and priceType eq 'Reservation'"
+	if filter := buildFilterQuery(query); filter != expected {
+		t.Errorf("expected %s, got %s", expected, filter)
+	}
+}
+
 func TestBuildFilterQuery_ODataEscape(t *testing.T) {
 	// Test that single quotes are properly escaped to prevent OData injection
 	query := PriceQuery{ArmRegionName: "east'us"}
@@ -0,0 +1,109 @@
+package azureclient
+
+import (
+	"context"
+	"fmt"
+)
+
+// inflightFetch is an upstream fetch shared by concurrent cache misses for
+// one key. result and err are written before done is closed; waiters is
+// guarded by CachedClient.inflightMu.
+type inflightFetch struct {
+	done    chan struct{}
+	result  CachedResult
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// fetchCoalesced returns the result of the in-flight fetch for key, starting
+// one if none is running. The fetch runs detached from any single caller's
+// context (keeping its values, such as trace IDs): a caller that gives up
+// returns its own context error without affecting the others, and the fetch
+// is cancelled only once every caller has given up.
+func (cc *CachedClient) fetchCoalesced(ctx context.Context, key string, query PriceQuery) (CachedResult, error) {
+	call, joined := cc.joinFetch(ctx, key, query, 1)
+	if joined {
+		cc.recordCoalesced(key)
+	}
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return CachedResult{}, call.err
+		}
+		return cloneCachedResult(call.result), nil
+	case <-ctx.Done():
+		cc.leaveFetch(key, call)
+		return CachedResult{}, fmt.Errorf("%s: %w", formatQueryContext(query), ctx.Err())
+	}
+}
+
+// joinFetch adds waiters to the in-flight fetch for key and reports true,
+// or starts a fetch with that many waiters and reports false. A fetch
+// without waiters (a background refresh) runs to completion.
+func (cc *CachedClient) joinFetch(
+	ctx context.Context,
+	key string,
+	query PriceQuery,
+	waiters int,
+) (*inflightFetch, bool) {
+	cc.inflightMu.Lock()
+	defer cc.inflightMu.Unlock()
+
+	if call, ok := cc.inflight[key]; ok {
+		call.waiters += waiters
+		return call, true
+	}
+
+	fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	call := &inflightFetch{done: make(chan struct{}), waiters: waiters, cancel: cancel}
+	cc.inflight[key] = call
+	go cc.runFetch(fetchCtx, key, query, call)
+	return call, false
+}
+
+// runFetch performs the upstream fetch for call, caches a successful result,
+// and releases the waiters.
+func (cc *CachedClient) runFetch(ctx context.Context, key string, query PriceQuery, call *inflightFetch) {
+	defer call.cancel()
+
+	items, err := cc.fetch(ctx, query)
+	if err == nil {
+		call.result = cc.store(key, items)
+	} else {
+		cc.storeNegative(key, err)
+	}
+	call.err = err
+
+	cc.inflightMu.Lock()
+	if cc.inflight[key] == call {
+		delete(cc.inflight, key)
+	}
+	cc.inflightMu.Unlock()
+	close(call.done)
+}
+
+// leaveFetch removes a caller that gave up on call. When the last caller
+// leaves, the fetch is cancelled and forgotten so later misses start afresh.
+func (cc *CachedClient) leaveFetch(key string, call *inflightFetch) {
+	cc.inflightMu.Lock()
+	defer cc.inflightMu.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+	if cc.inflight[key] == call {
+		delete(cc.inflight, key)
+	}
+	call.cancel()
+}
+
+func (cc *CachedClient) recordCoalesced(key string) {
+	cc.stats.Coalesced.Add(1)
+
+	cc.logger.Debug().
+		Str("cache_key", key).
+		Msg("cache miss coalesced")
+}
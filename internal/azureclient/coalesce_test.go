@@ -0,0 +1,171 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newBlockingPriceServer serves one price item once release is closed and
+// counts upstream requests.
+func newBlockingPriceServer(t *testing.T, release <-chan struct{}, calls *atomic.Int64) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items":[{"armSkuName":"Standard_B1s","retailPrice":0.0104}],"Count":1}`))
+	}))
+}
+
+// waitForCoalesced waits until want misses have joined an in-flight fetch.
+func waitForCoalesced(t *testing.T, cached *CachedClient, want int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for cached.Stats().Coalesced.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("coalesced = %d, want %d", cached.Stats().Coalesced.Load(), want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCachedClientGetPrices_CoalescesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	release := make(chan struct{})
+	server := newBlockingPriceServer(t, release, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	const callers = 50
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	var (
+		wg     sync.WaitGroup
+		failed atomic.Int64
+	)
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := cached.GetPrices(context.Background(), query)
+			if err != nil || len(result.Items) != 1 {
+				failed.Add(1)
+			}
+		}()
+	}
+
+	waitForCoalesced(t, cached, callers-1)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+	if got := failed.Load(); got != 0 {
+		t.Errorf("%d callers failed", got)
+	}
+	if got := cached.Stats().Misses.Load(); got != callers {
+		t.Errorf("misses = %d, want %d", got, callers)
+	}
+}
+
+func TestCachedClientGetPrices_CancelledCallerDoesNotFailOthers(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	release := make(chan struct{})
+	server := newBlockingPriceServer(t, release, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+
+	// The first caller starts the fetch and then gives up.
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cached.GetPrices(leaderCtx, query)
+		leaderErr <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	followerErr := make(chan error, 1)
+	go func() {
+		_, err := cached.GetPrices(context.Background(), query)
+		followerErr <- err
+	}()
+	waitForCoalesced(t, cached, 1)
+
+	cancelLeader()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader error = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	if err := <-followerErr; err != nil {
+		t.Errorf("follower error = %v, want nil", err)
+	}
+	if !cached.Cached(query) {
+		t.Error("shared fetch result should be cached")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCachedClientGetPrices_AllCallersCancelledStartsFreshFetch(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	release := make(chan struct{})
+	server := newBlockingPriceServer(t, release, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := cached.GetPrices(ctx, query); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+
+	close(release)
+	if _, err := cached.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("GetPrices() after abandoned fetch failed: %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected a fresh upstream call, got %d calls", got)
+	}
+	if got := cached.Stats().Coalesced.Load(); got != 0 {
+		t.Errorf("coalesced = %d, want 0", got)
+	}
+}
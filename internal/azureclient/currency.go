@@ -0,0 +1,53 @@
+package azureclient
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// DefaultCurrency is the currency the Retail Prices API publishes prices in;
+// other currencies are converted from it.
+const DefaultCurrency = "USD"
+
+// supportedCurrencies lists the ISO 4217 codes the Retail Prices API accepts
+// as its currencyCode parameter, sorted.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var supportedCurrencies = []string{
+	"AUD", "BRL", "CAD", "CHF", "CNY", "DKK", "EUR", "GBP", "INR",
+	"JPY", "KRW", "NOK", "NZD", "RUB", "SEK", "TWD", "USD",
+}
+
+// NormalizeCurrency returns the upper-case ISO 4217 code of code, or an error
+// wrapping ErrUnsupportedCurrency when the Retail Prices API does not price
+// in it. A blank code is DefaultCurrency.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+	if _, ok := slices.BinarySearch(supportedCurrencies, code); !ok {
+		return "", fmt.Errorf("%w: %q (supported: %s)",
+			ErrUnsupportedCurrency, code, strings.Join(supportedCurrencies, ", "))
+	}
+	return code, nil
+}
+
+// SupportedCurrencies returns the currency codes the Retail Prices API
+// supports, sorted.
+func SupportedCurrencies() []string {
+	return slices.Clone(supportedCurrencies)
+}
+
+// currencyParameter returns the currencyCode query parameter that makes the
+// API convert prices to currency, or "" for USD and blank currencies, whose
+// prices need no conversion.
+func currencyParameter(currency string) string {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" || currency == DefaultCurrency {
+		return ""
+	}
+	return "currencyCode=" + url.QueryEscape("'"+currency+"'")
+}
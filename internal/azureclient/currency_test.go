@@ -0,0 +1,81 @@
+package azureclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := map[string]string{
+		"":      "USD",
+		"usd":   "USD",
+		" eur ": "EUR",
+		"JPY":   "JPY",
+	}
+
+	for input, want := range tests {
+		got, err := NormalizeCurrency(input)
+		if err != nil {
+			t.Errorf("NormalizeCurrency(%q) error = %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeCurrency(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"XYZ", "EURO", "MXN"} {
+		if _, err := NormalizeCurrency(input); !errors.Is(err, ErrUnsupportedCurrency) {
+			t.Errorf("NormalizeCurrency(%q) error = %v, want ErrUnsupportedCurrency", input, err)
+		}
+	}
+}
+
+func TestSupportedCurrencies(t *testing.T) {
+	currencies := SupportedCurrencies()
+	if !slices.IsSorted(currencies) || !slices.Contains(currencies, DefaultCurrency) {
+		t.Errorf("SupportedCurrencies() = %v, want a sorted list including %s", currencies, DefaultCurrency)
+	}
+	currencies[0] = "changed"
+	if SupportedCurrencies()[0] == "changed" {
+		t.Error("SupportedCurrencies() must return a copy")
+	}
+}
+
+func TestClient_GetPrices_CurrencyParameter(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+	}{
+		{currency: "EUR", want: "'EUR'"},
+		{currency: "USD", want: ""},
+		{currency: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			var got string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query().Get("currencyCode")
+				w.Header().Set("Content-Type", "application/json")
+				_ = json.NewEncoder(w).Encode(PriceResponse{Items: []PriceItem{{CurrencyCode: tt.currency}}, Count: 1})
+			}))
+			defer server.Close()
+
+			client := newTestClient(t, server.URL)
+			if _, err := client.GetPrices(context.Background(), PriceQuery{
+				ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: tt.currency,
+			}); err != nil {
+				t.Fatalf("GetPrices() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("currencyCode parameter = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
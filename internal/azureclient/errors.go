@@ -1,12 +1,9 @@
 package azureclient
 
-import (
-	"errors"
-	"fmt"
-)
+import "errors"
 
-// MaxPaginationPages is a safety limit to prevent infinite pagination loops.
-// Used by both client.go (pagination logic) and the ErrPaginationLimitExceeded message.
+// MaxPaginationPages is the default safety limit to prevent infinite
+// pagination loops (see Config.MaxPages).
 const MaxPaginationPages = 10
 
 // Sentinel errors returned by the client.
@@ -37,6 +34,15 @@ var (
 	// query returns zero results (empty result set).
 	ErrNotFound = errors.New("not found")
 
-	// ErrPaginationLimitExceeded is returned when pagination exceeds the safety limit.
-	ErrPaginationLimitExceeded = fmt.Errorf("pagination limit exceeded (%d pages)", MaxPaginationPages)
+	// ErrPaginationLimitExceeded is returned when pagination exceeds the safety
+	// limit. The wrapping error names the limit.
+	ErrPaginationLimitExceeded = errors.New("pagination limit exceeded")
+
+	// ErrUnknownRegion is returned when a region name matches no known Azure
+	// region. The wrapping error may suggest the closest known region.
+	ErrUnknownRegion = errors.New("unknown region")
+
+	// ErrUnsupportedCurrency is returned when a currency code is not one the
+	// Retail Prices API converts prices to.
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
 )
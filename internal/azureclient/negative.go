@@ -0,0 +1,71 @@
+package azureclient
+
+import (
+	"errors"
+	"fmt"
+)
+
+// negativeEnabled reports whether not-found outcomes are cached.
+func (cc *CachedClient) negativeEnabled() bool {
+	return cc.negative != nil
+}
+
+// negativeHit returns the cached not-found error for key, or nil.
+func (cc *CachedClient) negativeHit(key string) error {
+	if !cc.negativeEnabled() {
+		return nil
+	}
+	err, ok := cc.negative.Get(key)
+	if !ok {
+		return nil
+	}
+
+	cc.stats.NegativeHits.Add(1)
+	total := cc.requests.Add(1)
+	cc.logger.Debug().
+		Str("cache_key", key).
+		Msg("negative cache hit")
+	cc.maybeLogStats(total, cc.stats.Hits.Load())
+	return err
+}
+
+// storeNegative remembers a not-found outcome for key. Other errors are
+// never cached. Any positive entry for key is dropped, since Azure no longer
+// prices the query.
+func (cc *CachedClient) storeNegative(key string, err error) {
+	if !cc.negativeEnabled() || !errors.Is(err, ErrNotFound) {
+		return
+	}
+	cc.cache.Remove(key)
+	cc.negative.Add(key, err)
+}
+
+// forgetNegative drops the not-found outcome for key after it was priced.
+func (cc *CachedClient) forgetNegative(key string) {
+	if cc.negativeEnabled() {
+		cc.negative.Remove(key)
+	}
+}
+
+// negativeCached reports whether a not-found outcome is cached for key,
+// without affecting statistics or LRU recency.
+func (cc *CachedClient) negativeCached(key string) bool {
+	if !cc.negativeEnabled() {
+		return false
+	}
+	_, ok := cc.negative.Peek(key)
+	return ok
+}
+
+// negativeLen returns the number of cached not-found outcomes.
+func (cc *CachedClient) negativeLen() int {
+	if !cc.negativeEnabled() {
+		return 0
+	}
+	return cc.negative.Len()
+}
+
+// notFoundError is the error GetPrices reports for a query without items.
+func notFoundError(query PriceQuery) error {
+	return fmt.Errorf("%s: %w: no pricing data", formatQueryContext(query), ErrNotFound)
+}
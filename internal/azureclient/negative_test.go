@@ -0,0 +1,158 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCachedClientGetPrices_CachesNotFound(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusNotFound)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		NegativeMaxSize: 10, NegativeTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_Typo"}
+	_, first := cached.GetPrices(context.Background(), query)
+	if !errors.Is(first, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", first)
+	}
+	_, second := cached.GetPrices(context.Background(), query)
+	if second != first {
+		t.Errorf("negative hit error = %v, want the original %v", second, first)
+	}
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+	stats := cached.Stats()
+	if got := stats.NegativeHits.Load(); got != 1 {
+		t.Errorf("negative hits = %d, want 1", got)
+	}
+	if hits, misses := stats.Hits.Load(), stats.Misses.Load(); hits != 0 || misses != 1 {
+		t.Errorf("hits/misses = %d/%d, want 0/1", hits, misses)
+	}
+}
+
+func TestCachedClientGetPrices_NegativeEntryExpires(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusNotFound)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		NegativeMaxSize: 10, NegativeTTL: 20 * time.Millisecond, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	if _, err := cached.GetPrices(context.Background(), query); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	status.Store(http.StatusOK)
+	result, err := cached.GetPrices(context.Background(), query)
+	if err != nil {
+		t.Fatalf("GetPrices() after negative expiry failed: %v", err)
+	}
+	if len(result.Items) != 1 {
+		t.Errorf("expected 1 item, got %d", len(result.Items))
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", got)
+	}
+}
+
+func TestCachedClientGetPrices_TransientErrorsNotNegativelyCached(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusServiceUnavailable)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		NegativeMaxSize: 10, NegativeTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	if _, err := cached.GetPrices(context.Background(), query); err == nil {
+		t.Fatal("expected an error during the outage")
+	}
+	status.Store(http.StatusOK)
+	if _, err := cached.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("GetPrices() after recovery failed: %v", err)
+	}
+	if got := cached.Stats().NegativeHits.Load(); got != 0 {
+		t.Errorf("negative hits = %d, want 0", got)
+	}
+}
+
+func TestNewCachedClient_RejectsNegativeCacheSettings(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, "http://127.0.0.1")
+	defer client.Close()
+
+	for _, cfg := range []CacheConfig{
+		{MaxSize: 10, TTL: time.Minute, NegativeMaxSize: -1},
+		{MaxSize: 10, TTL: time.Minute, NegativeTTL: -time.Second},
+	} {
+		if _, err := NewCachedClient(client, cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewCachedClient(%+v) error = %v, want ErrInvalidConfig", cfg, err)
+		}
+	}
+}
+
+func TestCachedClientPrefetch_NegativelyCachesMissingSKUs(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusOK)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		NegativeMaxSize: 10, NegativeTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	found := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	missing := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_Typo"}
+	if err := cached.Prefetch(context.Background(), []PriceQuery{found, missing}); err != nil {
+		t.Fatalf("Prefetch() unexpected error: %v", err)
+	}
+
+	if _, err := cached.GetPrices(context.Background(), missing); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+	if err := cached.Prefetch(context.Background(), []PriceQuery{found, missing}); err != nil {
+		t.Fatalf("second Prefetch() unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+	if got := cached.Stats().NegativeHits.Load(); got != 1 {
+		t.Errorf("negative hits = %d, want 1", got)
+	}
+}
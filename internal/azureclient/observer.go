@@ -0,0 +1,27 @@
+package azureclient
+
+import "time"
+
+// Observer receives client and cache events, for example to export them as
+// metrics. Implementations must be safe for concurrent use.
+type Observer interface {
+	// ObserveRequest reports one upstream page request, including its
+	// retries. category is "ok" on success or the request's error category
+	// (e.g., "rate_limited", "not_found").
+	ObserveRequest(category string, duration time.Duration)
+
+	// ObserveRetry reports one retried upstream request attempt.
+	ObserveRetry()
+
+	// ObserveEviction reports a cache eviction by reason ("lru" or
+	// "expired").
+	ObserveEviction(reason string)
+}
+
+// requestCategory returns the Observer category of a page request result.
+func requestCategory(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return errorCategory(err)
+}
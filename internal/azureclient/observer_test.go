@@ -0,0 +1,107 @@
+package azureclient
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// recordingObserver records Observer events.
+type recordingObserver struct {
+	mu         sync.Mutex
+	categories []string
+	retries    int
+	evictions  []string
+}
+
+func (o *recordingObserver) ObserveRequest(category string, _ time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.categories = append(o.categories, category)
+}
+
+func (o *recordingObserver) ObserveRetry() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retries++
+}
+
+func (o *recordingObserver) ObserveEviction(reason string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.evictions = append(o.evictions, reason)
+}
+
+func TestClientObserver_RequestsAndRetries(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusServiceUnavailable)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.RetryMax = 2
+	cfg.RetryWaitMin = time.Millisecond
+	cfg.RetryWaitMax = time.Millisecond
+	cfg.Logger = zerolog.Nop()
+	cfg.Observer = observer
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error: %v", err)
+	}
+	defer client.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	if _, err := client.GetPrices(context.Background(), query); err == nil {
+		t.Fatal("expected an error while the service is unavailable")
+	}
+	status.Store(http.StatusOK)
+	if _, err := client.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("GetPrices() unexpected error: %v", err)
+	}
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.categories) != 2 || observer.categories[0] != "service_unavailable" ||
+		observer.categories[1] != "ok" {
+		t.Errorf("categories = %q, want [service_unavailable ok]", observer.categories)
+	}
+	if observer.retries != 2 {
+		t.Errorf("retries = %d, want 2", observer.retries)
+	}
+}
+
+func TestCachedClientObserver_Evictions(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusOK)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 1, TTL: time.Hour, ExpiresAtTTL: time.Hour, Observer: observer, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	for _, region := range []string{"eastus", "westus"} {
+		if _, err := cached.GetPrices(context.Background(), PriceQuery{ArmRegionName: region}); err != nil {
+			t.Fatalf("GetPrices(%s) unexpected error: %v", region, err)
+		}
+	}
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	if len(observer.evictions) != 1 || observer.evictions[0] != "lru" {
+		t.Errorf("evictions = %q, want [lru]", observer.evictions)
+	}
+}
@@ -0,0 +1,112 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// maxPrefetchSKUs caps the SKUs OR'ed into one filter so request URLs stay
+// well below common length limits.
+const maxPrefetchSKUs = 20
+
+// Prefetch warms the cache for queries with as few Azure API requests as
+// possible. Uncached queries that differ only in ArmSkuName are grouped by
+// region, service, product, currency, and price type and fetched with one
+// OR'ed SKU filter per group (up to maxPrefetchSKUs SKUs per request). The
+// returned items are split back out by ArmSkuName and cached under each
+// query's own key, so later GetPrices calls for those queries are hits.
+//
+// Queries of a superset service are replaced by their region+service
+// slice. Queries without a SKU, and groups with a single query, go through
+// GetPrices. SKUs without items are recorded in the negative cache, so their
+// GetPrices calls report ErrNotFound without another request.
+//
+// Prefetch is best effort: request errors are joined and returned, and
+// callers may ignore them and rely on GetPrices.
+func (cc *CachedClient) Prefetch(ctx context.Context, queries []PriceQuery) error {
+	if cc.disabled {
+		return nil
+	}
+
+	var (
+		groupKeys []string
+		groups    = make(map[string][]PriceQuery)
+		seen      = make(map[string]bool, len(queries))
+	)
+	for _, query := range queries {
+		if superset, ok := cc.supersetQuery(query); ok {
+			query = superset
+		}
+		key := CacheKey(query)
+		if seen[key] || cc.Cached(query) || cc.negativeCached(key) {
+			continue
+		}
+		seen[key] = true
+
+		base := query
+		base.ArmSkuName = ""
+		groupKey := key
+		if strings.TrimSpace(query.ArmSkuName) != "" {
+			groupKey = CacheKey(base)
+		}
+		if _, ok := groups[groupKey]; !ok {
+			groupKeys = append(groupKeys, groupKey)
+		}
+		groups[groupKey] = append(groups[groupKey], query)
+	}
+
+	var errs []error
+	for _, groupKey := range groupKeys {
+		group := groups[groupKey]
+		if len(group) == 1 {
+			if _, err := cc.GetPrices(ctx, group[0]); err != nil && !errors.Is(err, ErrNotFound) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		for start := 0; start < len(group); start += maxPrefetchSKUs {
+			end := min(start+maxPrefetchSKUs, len(group))
+			if err := cc.prefetchSKUs(ctx, group[start:end]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	cc.logger.Debug().
+		Int("query_count", len(queries)).
+		Int("uncached_query_count", len(seen)).
+		Int("group_count", len(groupKeys)).
+		Int("error_count", len(errs)).
+		Msg("prefetch completed")
+
+	return errors.Join(errs...)
+}
+
+// prefetchSKUs fetches queries that differ only in ArmSkuName with one
+// request and caches the items of each SKU under its query's key.
+func (cc *CachedClient) prefetchSKUs(ctx context.Context, queries []PriceQuery) error {
+	skus := make([]string, 0, len(queries))
+	for _, query := range queries {
+		skus = append(skus, query.ArmSkuName)
+	}
+
+	items, err := cc.client.GetPricesForSKUs(ctx, queries[0], skus)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	bySKU := make(map[string][]PriceItem, len(queries))
+	for _, item := range items {
+		sku := normalizeKeyPart(item.ArmSkuName)
+		bySKU[sku] = append(bySKU[sku], item)
+	}
+	for _, query := range queries {
+		if skuItems := bySKU[normalizeKeyPart(query.ArmSkuName)]; len(skuItems) > 0 {
+			cc.store(CacheKey(query), skuItems)
+		} else {
+			cc.storeNegative(CacheKey(query), notFoundError(query))
+		}
+	}
+	return nil
+}
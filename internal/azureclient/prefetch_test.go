@@ -0,0 +1,129 @@
+package azureclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestCachedClientPrefetch_GroupsSKUsByRegionAndService(t *testing.T) {
+	t.Parallel()
+
+	catalog := []PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", RetailPrice: 0.0104},
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B2s", ServiceName: "Virtual Machines", RetailPrice: 0.0416},
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B2s", ServiceName: "Virtual Machines", RetailPrice: 0.05},
+		{ArmRegionName: "westus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines", RetailPrice: 0.012},
+	}
+
+	var (
+		mu      sync.Mutex
+		filters []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("$filter")
+		mu.Lock()
+		filters = append(filters, filter)
+		mu.Unlock()
+
+		var items []PriceItem
+		for _, item := range catalog {
+			if strings.Contains(filter, "armRegionName eq '"+item.ArmRegionName+"'") &&
+				strings.Contains(filter, "armSkuName eq '"+item.ArmSkuName+"'") {
+				items = append(items, item)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(PriceResponse{Items: items, Count: len(items)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 100, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := func(region, sku string) PriceQuery {
+		return PriceQuery{ArmRegionName: region, ArmSkuName: sku, ServiceName: "Virtual Machines", CurrencyCode: "USD"}
+	}
+	queries := []PriceQuery{
+		query("eastus", "Standard_B1s"),
+		query("eastus", "Standard_B2s"),
+		query("eastus", "standard_b1s"), // Same cache key as the first query.
+		query("eastus", "Standard_D2s_v3"),
+		query("westus", "Standard_B1s"),
+	}
+	if err := cached.Prefetch(context.Background(), queries); err != nil {
+		t.Fatalf("Prefetch() unexpected error: %v", err)
+	}
+
+	if len(filters) != 2 {
+		t.Fatalf("expected one request per region, got %d: %q", len(filters), filters)
+	}
+	want := "(armSkuName eq 'Standard_B1s' or armSkuName eq 'Standard_B2s' or armSkuName eq 'Standard_D2s_v3')"
+	if !strings.Contains(filters[0], want) {
+		t.Errorf("filter = %q, want OR group %q", filters[0], want)
+	}
+
+	for _, q := range []PriceQuery{queries[0], queries[1], queries[4]} {
+		if !cached.Cached(q) {
+			t.Errorf("expected %s to be cached", CacheKey(q))
+		}
+	}
+	if cached.Cached(queries[3]) {
+		t.Error("SKU without items must not be cached")
+	}
+
+	result, err := cached.GetPrices(context.Background(), queries[1])
+	if err != nil {
+		t.Fatalf("GetPrices() unexpected error: %v", err)
+	}
+	if len(result.Items) != 2 {
+		t.Errorf("expected the two Standard_B2s items, got %+v", result.Items)
+	}
+	if len(filters) != 2 {
+		t.Errorf("GetPrices after Prefetch should hit the cache, got %d requests", len(filters))
+	}
+}
+
+func TestCachedClientPrefetch_SplitsLargeGroups(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items":[],"Count":0}`))
+	}))
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 100, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	queries := make([]PriceQuery, 0, maxPrefetchSKUs+1)
+	for i := 0; i <= maxPrefetchSKUs; i++ {
+		queries = append(queries, PriceQuery{ArmRegionName: "eastus", ArmSkuName: "sku" + string(rune('a'+i))})
+	}
+	if err := cached.Prefetch(context.Background(), queries); err != nil {
+		t.Fatalf("Prefetch() unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("expected 2 requests for %d SKUs, got %d", len(queries), requests)
+	}
+}
@@ -0,0 +1,219 @@
+package azureclient
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RegionInfo describes an Azure region.
+type RegionInfo struct {
+	// Name is the canonical armRegionName (e.g., "eastus").
+	Name string
+	// DisplayName is the portal and ARM display name (e.g., "East US").
+	DisplayName string
+	// PriceLocation is the PriceItem.Location the Retail Prices API reports
+	// for the region (e.g., "US East").
+	PriceLocation string
+}
+
+// knownRegions lists the Azure public cloud and US Government regions with
+// retail prices. Regions missing here still pass NormalizeRegion when given
+// as an ARM name (see isARMRegionName).
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var knownRegions = []RegionInfo{
+	{Name: "eastus", DisplayName: "East US", PriceLocation: "US East"},
+	{Name: "eastus2", DisplayName: "East US 2", PriceLocation: "US East 2"},
+	{Name: "centralus", DisplayName: "Central US", PriceLocation: "US Central"},
+	{Name: "northcentralus", DisplayName: "North Central US", PriceLocation: "US North Central"},
+	{Name: "southcentralus", DisplayName: "South Central US", PriceLocation: "US South Central"},
+	{Name: "westcentralus", DisplayName: "West Central US", PriceLocation: "US West Central"},
+	{Name: "westus", DisplayName: "West US", PriceLocation: "US West"},
+	{Name: "westus2", DisplayName: "West US 2", PriceLocation: "US West 2"},
+	{Name: "westus3", DisplayName: "West US 3", PriceLocation: "US West 3"},
+	{Name: "canadacentral", DisplayName: "Canada Central", PriceLocation: "CA Central"},
+	{Name: "canadaeast", DisplayName: "Canada East", PriceLocation: "CA East"},
+	{Name: "brazilsouth", DisplayName: "Brazil South", PriceLocation: "BR South"},
+	{Name: "brazilsoutheast", DisplayName: "Brazil Southeast", PriceLocation: "BR Southeast"},
+	{Name: "mexicocentral", DisplayName: "Mexico Central", PriceLocation: "MX Central"},
+	{Name: "chilecentral", DisplayName: "Chile Central", PriceLocation: "CL Central"},
+	{Name: "usgovvirginia", DisplayName: "USGov Virginia", PriceLocation: "US Gov Virginia"},
+	{Name: "usgovarizona", DisplayName: "USGov Arizona", PriceLocation: "US Gov AZ"},
+	{Name: "usgovtexas", DisplayName: "USGov Texas", PriceLocation: "US Gov TX"},
+	{Name: "usdodcentral", DisplayName: "USDoD Central", PriceLocation: "US DoD Central"},
+	{Name: "usdodeast", DisplayName: "USDoD East", PriceLocation: "US DoD East"},
+	{Name: "northeurope", DisplayName: "North Europe", PriceLocation: "EU North"},
+	{Name: "westeurope", DisplayName: "West Europe", PriceLocation: "EU West"},
+	{Name: "uksouth", DisplayName: "UK South", PriceLocation: "UK South"},
+	{Name: "ukwest", DisplayName: "UK West", PriceLocation: "UK West"},
+	{Name: "francecentral", DisplayName: "France Central", PriceLocation: "FR Central"},
+	{Name: "francesouth", DisplayName: "France South", PriceLocation: "FR South"},
+	{Name: "germanywestcentral", DisplayName: "Germany West Central", PriceLocation: "DE West Central"},
+	{Name: "germanynorth", DisplayName: "Germany North", PriceLocation: "DE North"},
+	{Name: "switzerlandnorth", DisplayName: "Switzerland North", PriceLocation: "CH North"},
+	{Name: "switzerlandwest", DisplayName: "Switzerland West", PriceLocation: "CH West"},
+	{Name: "norwayeast", DisplayName: "Norway East", PriceLocation: "NO East"},
+	{Name: "norwaywest", DisplayName: "Norway West", PriceLocation: "NO West"},
+	{Name: "swedencentral", DisplayName: "Sweden Central", PriceLocation: "SE Central"},
+	{Name: "swedensouth", DisplayName: "Sweden South", PriceLocation: "SE South"},
+	{Name: "polandcentral", DisplayName: "Poland Central", PriceLocation: "PL Central"},
+	{Name: "italynorth", DisplayName: "Italy North", PriceLocation: "IT North"},
+	{Name: "spaincentral", DisplayName: "Spain Central", PriceLocation: "ES Central"},
+	{Name: "austriaeast", DisplayName: "Austria East", PriceLocation: "AT East"},
+	{Name: "belgiumcentral", DisplayName: "Belgium Central", PriceLocation: "BE Central"},
+	{Name: "denmarkeast", DisplayName: "Denmark East", PriceLocation: "DK East"},
+	{Name: "eastasia", DisplayName: "East Asia", PriceLocation: "AP East"},
+	{Name: "southeastasia", DisplayName: "Southeast Asia", PriceLocation: "AP Southeast"},
+	{Name: "japaneast", DisplayName: "Japan East", PriceLocation: "JA East"},
+	{Name: "japanwest", DisplayName: "Japan West", PriceLocation: "JA West"},
+	{Name: "koreacentral", DisplayName: "Korea Central", PriceLocation: "KR Central"},
+	{Name: "koreasouth", DisplayName: "Korea South", PriceLocation: "KR South"},
+	{Name: "taiwannorth", DisplayName: "Taiwan North", PriceLocation: "TW North"},
+	{Name: "taiwannorthwest", DisplayName: "Taiwan Northwest", PriceLocation: "TW Northwest"},
+	{Name: "malaysiawest", DisplayName: "Malaysia West", PriceLocation: "MY West"},
+	{Name: "indonesiacentral", DisplayName: "Indonesia Central", PriceLocation: "ID Central"},
+	{Name: "australiaeast", DisplayName: "Australia East", PriceLocation: "AU East"},
+	{Name: "australiasoutheast", DisplayName: "Australia Southeast", PriceLocation: "AU Southeast"},
+	{Name: "australiacentral", DisplayName: "Australia Central", PriceLocation: "AU Central"},
+	{Name: "australiacentral2", DisplayName: "Australia Central 2", PriceLocation: "AU Central 2"},
+	{Name: "centralindia", DisplayName: "Central India", PriceLocation: "IN Central"},
+	{Name: "southindia", DisplayName: "South India", PriceLocation: "IN South"},
+	{Name: "westindia", DisplayName: "West India", PriceLocation: "IN West"},
+	{Name: "jioindiawest", DisplayName: "Jio India West", PriceLocation: "IN West Jio"},
+	{Name: "jioindiacentral", DisplayName: "Jio India Central", PriceLocation: "IN Central Jio"},
+	{Name: "uaenorth", DisplayName: "UAE North", PriceLocation: "AE North"},
+	{Name: "uaecentral", DisplayName: "UAE Central", PriceLocation: "AE Central"},
+	{Name: "qatarcentral", DisplayName: "Qatar Central", PriceLocation: "QA Central"},
+	{Name: "israelcentral", DisplayName: "Israel Central", PriceLocation: "IL Central"},
+	{Name: "southafricanorth", DisplayName: "South Africa North", PriceLocation: "ZA North"},
+	{Name: "southafricawest", DisplayName: "South Africa West", PriceLocation: "ZA West"},
+	{Name: "newzealandnorth", DisplayName: "New Zealand North", PriceLocation: "NZ North"},
+}
+
+// regionAliases maps region keys (see regionKey) of ARM names, display
+// names, and price locations to indexes in knownRegions.
+//
+//nolint:gochecknoglobals // Derived lookup table; immutable after init.
+var regionAliases = buildRegionAliases()
+
+func buildRegionAliases() map[string]int {
+	aliases := make(map[string]int, 3*len(knownRegions))
+	for i, region := range knownRegions {
+		aliases[regionKey(region.Name)] = i
+		aliases[regionKey(region.DisplayName)] = i
+		aliases[regionKey(region.PriceLocation)] = i
+	}
+	return aliases
+}
+
+// maxRegionSuggestionDistance bounds the edit distance of did-you-mean
+// suggestions for unknown regions.
+const maxRegionSuggestionDistance = 3
+
+// LookupRegion resolves an ARM name ("eastus"), display name ("East US"),
+// price location ("US East"), or a variant of them differing in case,
+// spaces, hyphens, or underscores ("east-us", "EASTUS") to its region.
+func LookupRegion(name string) (RegionInfo, bool) {
+	i, ok := regionAliases[regionKey(name)]
+	if !ok {
+		return RegionInfo{}, false
+	}
+	return knownRegions[i], true
+}
+
+// NormalizeRegion returns the canonical armRegionName of name (see
+// LookupRegion). A name that is not a known region but is shaped like an ARM
+// name ("newregion2") and close to no known alias is returned unchanged, so
+// regions Azure adds later need no plugin release. Other names return an
+// error wrapping ErrUnknownRegion that suggests the closest known region, if
+// any.
+func NormalizeRegion(name string) (string, error) {
+	if region, ok := LookupRegion(name); ok {
+		return region.Name, nil
+	}
+	suggestion, ok := suggestRegion(name)
+	if ok {
+		return "", fmt.Errorf("%w: %q (did you mean %q?)", ErrUnknownRegion, name, suggestion)
+	}
+	if trimmed := strings.TrimSpace(name); isARMRegionName(trimmed) {
+		return trimmed, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownRegion, name)
+}
+
+// isARMRegionName reports whether name looks like an armRegionName: a
+// lowercase letter followed by lowercase letters and digits.
+func isARMRegionName(name string) bool {
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// KnownRegions returns the known regions sorted by armRegionName.
+func KnownRegions() []RegionInfo {
+	regions := make([]RegionInfo, len(knownRegions))
+	copy(regions, knownRegions)
+	sort.Slice(regions, func(i, j int) bool { return regions[i].Name < regions[j].Name })
+	return regions
+}
+
+// suggestRegion returns the armRegionName whose alias is closest to name,
+// when it is within maxRegionSuggestionDistance edits.
+func suggestRegion(name string) (string, bool) {
+	key := regionKey(name)
+	if key == "" {
+		return "", false
+	}
+
+	best, bestDistance := -1, maxRegionSuggestionDistance+1
+	for alias, i := range regionAliases {
+		d := editDistance(key, alias)
+		// Map order is random; break ties by name for stable suggestions.
+		if d < bestDistance || (d == bestDistance && best >= 0 && knownRegions[i].Name < knownRegions[best].Name) {
+			best, bestDistance = i, d
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return knownRegions[best].Name, true
+}
+
+// regionKey lowercases name and drops spaces, hyphens, and underscores.
+func regionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(name)))
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
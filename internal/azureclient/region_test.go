@@ -0,0 +1,102 @@
+package azureclient
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeRegion(t *testing.T) {
+	tests := map[string]string{
+		"eastus":               "eastus",
+		"EASTUS":               "eastus",
+		"East US":              "eastus",
+		"east-us":              "eastus",
+		"east_us_2":            "eastus2",
+		" US East ":            "eastus",
+		"EU West":              "westeurope",
+		"West Europe":          "westeurope",
+		"germany-west-central": "germanywestcentral",
+		"AU Central 2":         "australiacentral2",
+		"USGov Virginia":       "usgovvirginia",
+		"US Gov AZ":            "usgovarizona",
+		"usgovtexas":           "usgovtexas",
+		"Chile Central":        "chilecentral",
+		"malaysiawest":         "malaysiawest",
+		"ID Central":           "indonesiacentral",
+		"austria-east":         "austriaeast",
+		// Unknown ARM-shaped names far from every alias pass through.
+		"contosocentral1": "contosocentral1",
+		" arcticnorth ":   "arcticnorth",
+	}
+
+	for input, want := range tests {
+		got, err := NormalizeRegion(input)
+		if err != nil {
+			t.Errorf("NormalizeRegion(%q) error = %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("NormalizeRegion(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizeRegion_Unknown(t *testing.T) {
+	tests := []struct {
+		input      string
+		suggestion string
+	}{
+		{input: "eastsu", suggestion: `did you mean "eastus"?`},
+		{input: "West Erope", suggestion: `did you mean "westeurope"?`},
+		{input: "mars-north-1"},
+		{input: "Arctic North"},
+		{input: "eastus2!", suggestion: `did you mean "eastus2"?`},
+		{input: ""},
+	}
+
+	for _, tt := range tests {
+		_, err := NormalizeRegion(tt.input)
+		if !errors.Is(err, ErrUnknownRegion) {
+			t.Errorf("NormalizeRegion(%q) error = %v, want ErrUnknownRegion", tt.input, err)
+			continue
+		}
+		hasSuggestion := strings.Contains(err.Error(), "did you mean")
+		if tt.suggestion == "" && hasSuggestion {
+			t.Errorf("NormalizeRegion(%q) error = %q, want no suggestion", tt.input, err)
+		}
+		if tt.suggestion != "" && !strings.Contains(err.Error(), tt.suggestion) {
+			t.Errorf("NormalizeRegion(%q) error = %q, want %q", tt.input, err, tt.suggestion)
+		}
+	}
+}
+
+func TestLookupRegion(t *testing.T) {
+	region, ok := LookupRegion("uk-south")
+	if !ok {
+		t.Fatal("LookupRegion(uk-south) not found")
+	}
+	if region.Name != "uksouth" || region.DisplayName != "UK South" || region.PriceLocation != "UK South" {
+		t.Errorf("LookupRegion(uk-south) = %+v", region)
+	}
+}
+
+func TestKnownRegions_UniqueAliases(t *testing.T) {
+	regions := KnownRegions()
+	if len(regions) != len(knownRegions) {
+		t.Fatalf("KnownRegions() returned %d regions, want %d", len(regions), len(knownRegions))
+	}
+
+	seen := make(map[string]string)
+	for i, region := range regions {
+		if i > 0 && regions[i-1].Name >= region.Name {
+			t.Errorf("KnownRegions() not sorted at %q", region.Name)
+		}
+		for _, alias := range []string{region.Name, region.DisplayName, region.PriceLocation} {
+			if other, dup := seen[regionKey(alias)]; dup && other != region.Name {
+				t.Errorf("alias %q maps to both %q and %q", alias, other, region.Name)
+			}
+			seen[regionKey(alias)] = region.Name
+		}
+	}
+}
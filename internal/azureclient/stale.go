@@ -0,0 +1,66 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// isStale reports whether a cached result is older than the soft TTL.
+func (cc *CachedClient) isStale(result CachedResult) bool {
+	return cc.config.SoftTTL > 0 && time.Since(result.CreatedAt) >= cc.config.SoftTTL
+}
+
+// getStale handles a cached result past its soft TTL. In
+// stale-while-revalidate mode the stale result is returned at once and a
+// background refresh is started (or joined). Otherwise the entry is
+// refreshed in the foreground, and the stale result is returned only when
+// the refresh fails with a transient upstream error.
+func (cc *CachedClient) getStale(
+	ctx context.Context,
+	key string,
+	query PriceQuery,
+	cached CachedResult,
+) (CachedResult, error) {
+	stale := cloneCachedResult(cached)
+	stale.Stale = true
+
+	if cc.config.StaleWhileRevalidate {
+		setCacheResult(ctx, cacheResultStale, true)
+		cc.recordHit(key)
+		cc.stats.Stale.Add(1)
+		if _, joined := cc.joinFetch(ctx, key, query, 0); !joined {
+			cc.logger.Debug().
+				Str("cache_key", key).
+				Msg("cache background refresh started")
+		}
+		return stale, nil
+	}
+
+	setCacheResult(ctx, cacheResultRefresh, false)
+	cc.recordMiss(key)
+	result, err := cc.fetchCoalesced(ctx, key, query)
+	if err == nil {
+		return result, nil
+	}
+	if !isTransientUpstreamError(err) {
+		return CachedResult{}, err
+	}
+
+	setCacheResult(ctx, cacheResultStale, true)
+	cc.stats.Stale.Add(1)
+	cc.logger.Warn().
+		Str("cache_key", key).
+		Time("cached_at", cached.CreatedAt).
+		Err(err).
+		Msg("serving stale price after upstream error")
+	return stale, nil
+}
+
+// isTransientUpstreamError reports whether err is an upstream failure that a
+// stale price may paper over.
+func isTransientUpstreamError(err error) bool {
+	return errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, ErrServiceUnavailable) ||
+		errors.Is(err, ErrRequestFailed)
+}
@@ -0,0 +1,165 @@
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newStatusPriceServer serves one price item while status is 200 and an
+// empty error response otherwise.
+func newStatusPriceServer(t *testing.T, status *atomic.Int64, calls *atomic.Int64) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		if code := int(status.Load()); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items":[{"armSkuName":"Standard_B1s","retailPrice":0.0104}],"Count":1}`))
+	}))
+}
+
+func TestCachedClientGetPrices_ServesStaleOnTransientError(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusOK)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, SoftTTL: 20 * time.Millisecond, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	if _, err := cached.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("initial GetPrices() failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	status.Store(http.StatusServiceUnavailable)
+	result, err := cached.GetPrices(context.Background(), query)
+	if err != nil {
+		t.Fatalf("GetPrices() during outage failed: %v", err)
+	}
+	if !result.Stale || len(result.Items) != 1 {
+		t.Errorf("expected the stale item, got %+v", result)
+	}
+	if got := cached.Stats().Stale.Load(); got != 1 {
+		t.Errorf("stale = %d, want 1", got)
+	}
+
+	status.Store(http.StatusOK)
+	result, err = cached.GetPrices(context.Background(), query)
+	if err != nil {
+		t.Fatalf("GetPrices() after recovery failed: %v", err)
+	}
+	if result.Stale {
+		t.Error("refreshed result should not be stale")
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 upstream calls, got %d", got)
+	}
+}
+
+func TestCachedClientGetPrices_StaleNotServedOnNotFound(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusOK)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, SoftTTL: 20 * time.Millisecond, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	if _, err := cached.GetPrices(context.Background(), query); err != nil {
+		t.Fatalf("initial GetPrices() failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	status.Store(http.StatusNotFound)
+	if _, err := cached.GetPrices(context.Background(), query); !errors.Is(err, ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCachedClientGetPrices_StaleWhileRevalidate(t *testing.T) {
+	t.Parallel()
+
+	var status, calls atomic.Int64
+	status.Store(http.StatusOK)
+	server := newStatusPriceServer(t, &status, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, SoftTTL: 20 * time.Millisecond, ExpiresAtTTL: time.Hour,
+		StaleWhileRevalidate: true, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	first, err := cached.GetPrices(context.Background(), query)
+	if err != nil {
+		t.Fatalf("initial GetPrices() failed: %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	result, err := cached.GetPrices(context.Background(), query)
+	if err != nil {
+		t.Fatalf("stale GetPrices() failed: %v", err)
+	}
+	if !result.Stale || !result.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("expected the original result marked stale, got %+v", result)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		result, err = cached.GetPrices(context.Background(), query)
+		if err != nil {
+			t.Fatalf("GetPrices() failed: %v", err)
+		}
+		if !result.Stale {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh never replaced the stale entry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if !result.CreatedAt.After(first.CreatedAt) {
+		t.Errorf("refreshed CreatedAt %s should follow %s", result.CreatedAt, first.CreatedAt)
+	}
+	if got := cached.Stats().Misses.Load(); got != 1 {
+		t.Errorf("misses = %d, want 1 (stale results are served as hits)", got)
+	}
+}
+
+func TestNewCachedClient_RejectsSoftTTLAboveTTL(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, "http://127.0.0.1")
+	defer client.Close()
+
+	for _, cfg := range []CacheConfig{
+		{MaxSize: 10, TTL: time.Minute, SoftTTL: time.Hour},
+		{MaxSize: 10, TTL: time.Minute, SoftTTL: -time.Second},
+	} {
+		if _, err := NewCachedClient(client, cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("NewCachedClient(%+v) error = %v, want ErrInvalidConfig", cfg, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package azureclient
+
+import (
+	"context"
+	"strings"
+)
+
+// supersetQuery returns the region+service slice query covering query when
+// its service is listed in CacheConfig.SupersetServices.
+func (cc *CachedClient) supersetQuery(query PriceQuery) (PriceQuery, bool) {
+	// A region-less query is already a cross-region slice; widening it to the
+	// whole service would fetch the service's global price list.
+	if cc.disabled || strings.TrimSpace(query.ArmRegionName) == "" {
+		return PriceQuery{}, false
+	}
+	service := normalizeKeyPart(query.ServiceName)
+	if service == "" {
+		return PriceQuery{}, false
+	}
+	for _, candidate := range cc.config.SupersetServices {
+		if normalizeKeyPart(candidate) == service {
+			query.ArmSkuName = ""
+			query.ProductName = ""
+			return query, true
+		}
+	}
+	return PriceQuery{}, false
+}
+
+// fetchQuery returns the query a cache miss for query fetches: the
+// region+service slice for a superset service, otherwise query itself.
+func (cc *CachedClient) fetchQuery(query PriceQuery) PriceQuery {
+	if superset, ok := cc.supersetQuery(query); ok {
+		return superset
+	}
+	return query
+}
+
+// Filter returns the OData $filter expression a cache miss for query sends
+// to the Azure API. For a superset service it is the region+service filter
+// of the slice rather than query's own filter. It never calls the Azure API.
+func (cc *CachedClient) Filter(query PriceQuery) string {
+	return buildFilterQuery(cc.fetchQuery(query))
+}
+
+// fetch calls the Azure API for query, using a superset fetch for
+// region+service slices and an all-region fetch for region-less queries.
+func (cc *CachedClient) fetch(ctx context.Context, query PriceQuery) ([]PriceItem, error) {
+	if strings.TrimSpace(query.ArmRegionName) == "" {
+		return cc.client.GetAllRegionPrices(ctx, query)
+	}
+	if superset, ok := cc.supersetQuery(query); ok && superset == query {
+		return cc.client.GetServicePrices(ctx, query)
+	}
+	return cc.client.GetPrices(ctx, query)
+}
+
+// GetAllRegionPrices returns query's prices in every region, with
+// query.ArmRegionName ignored. The region-less result is cached like any
+// other query.
+func (cc *CachedClient) GetAllRegionPrices(ctx context.Context, query PriceQuery) (CachedResult, error) {
+	query.ArmRegionName = ""
+	return cc.GetPrices(ctx, query)
+}
+
+// getFromSuperset answers query by filtering the cached (or freshly
+// fetched) region+service slice in memory. The result keeps the slice's
+// timestamps and staleness.
+func (cc *CachedClient) getFromSuperset(
+	ctx context.Context,
+	query, superset PriceQuery,
+) (CachedResult, error) {
+	result, err := cc.GetPrices(ctx, superset)
+	if err != nil {
+		return CachedResult{}, err
+	}
+
+	items := make([]PriceItem, 0)
+	for _, item := range result.Items {
+		if matchesKeyPart(item.ArmSkuName, query.ArmSkuName) && matchesKeyPart(item.ProductName, query.ProductName) {
+			items = append(items, item)
+		}
+	}
+	cc.logger.Debug().
+		Str("cache_key", CacheKey(query)).
+		Str("superset_key", CacheKey(superset)).
+		Int("superset_items", len(result.Items)).
+		Int("matched_items", len(items)).
+		Msg("answered from superset")
+	if len(items) == 0 {
+		return CachedResult{}, notFoundError(query)
+	}
+
+	result.Items = items
+	return result, nil
+}
+
+// matchesKeyPart reports whether value equals want, ignoring case and
+// surrounding whitespace. An empty want matches any value.
+func matchesKeyPart(value, want string) bool {
+	want = normalizeKeyPart(want)
+	return want == "" || strings.EqualFold(strings.TrimSpace(value), want)
+}
@@ -0,0 +1,190 @@
+package azureclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newDiskSliceServer serves an eastus Managed Disks slice and rejects
+// SKU-filtered requests, so superset caching must answer SKU queries.
+func newDiskSliceServer(t *testing.T, calls *atomic.Int64) *httptest.Server {
+	t.Helper()
+
+	items := []PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ProductName: "Premium SSD Managed Disks", RetailPrice: 19.71},
+		{ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ProductName: "Premium SSD Managed Disks", RetailPrice: 38.01},
+		{ArmRegionName: "eastus", ArmSkuName: "StandardSSD_LRS", ProductName: "Standard SSD Managed Disks", RetailPrice: 4.8},
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if strings.Contains(r.URL.Query().Get("$filter"), "armSkuName") {
+			t.Errorf("unexpected SKU-filtered request: %s", r.URL.Query().Get("$filter"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(PriceResponse{Items: items, Count: len(items)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+}
+
+func TestCachedClientGetPrices_AnswersSKUsFromSuperset(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	server := newDiskSliceServer(t, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		SupersetServices: []string{"managed disks"}, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := func(sku string) PriceQuery {
+		return PriceQuery{ArmRegionName: "eastus", ArmSkuName: sku, ServiceName: "Managed Disks", CurrencyCode: "USD"}
+	}
+	premium, err := cached.GetPrices(context.Background(), query("Premium_LRS"))
+	if err != nil {
+		t.Fatalf("GetPrices(Premium_LRS) failed: %v", err)
+	}
+	if len(premium.Items) != 2 {
+		t.Errorf("expected the 2 Premium_LRS items, got %+v", premium.Items)
+	}
+	standard, err := cached.GetPrices(context.Background(), query("standardssd_lrs"))
+	if err != nil {
+		t.Fatalf("GetPrices(StandardSSD_LRS) failed: %v", err)
+	}
+	if len(standard.Items) != 1 || standard.Items[0].RetailPrice != 4.8 {
+		t.Errorf("expected the StandardSSD_LRS item, got %+v", standard.Items)
+	}
+	if _, err := cached.GetPrices(context.Background(), query("UltraSSD_LRS")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown SKU error = %v, want ErrNotFound", err)
+	}
+
+	if !cached.Cached(query("UltraSSD_LRS")) {
+		t.Error("Cached() should report the region+service slice")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call for the slice, got %d", got)
+	}
+	if got := cached.Len(); got != 1 {
+		t.Errorf("expected only the slice to be cached, got %d entries", got)
+	}
+}
+
+func TestCachedClientPrefetch_FetchesSupersetOnce(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int64
+	server := newDiskSliceServer(t, &calls)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		SupersetServices: []string{"Managed Disks"}, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	queries := []PriceQuery{
+		{ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks"},
+		{ArmRegionName: "eastus", ArmSkuName: "StandardSSD_LRS", ServiceName: "Managed Disks"},
+	}
+	if err := cached.Prefetch(context.Background(), queries); err != nil {
+		t.Fatalf("Prefetch() unexpected error: %v", err)
+	}
+	for _, query := range queries {
+		if _, err := cached.GetPrices(context.Background(), query); err != nil {
+			t.Errorf("GetPrices(%s) failed: %v", query.ArmSkuName, err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 upstream call, got %d", got)
+	}
+}
+
+func TestCachedClientGetAllRegionPrices_BypassesSuperset(t *testing.T) {
+	t.Parallel()
+
+	var filters []string
+	server := newPagingServer(t, 1, &filters)
+	defer server.Close()
+
+	cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+		SupersetServices: []string{"managed disks"}, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks"}
+	for range 2 {
+		if _, err := cached.GetAllRegionPrices(context.Background(), query); err != nil {
+			t.Fatalf("GetAllRegionPrices() failed: %v", err)
+		}
+	}
+
+	if len(filters) != 1 {
+		t.Fatalf("expected one upstream request, got %d", len(filters))
+	}
+	if strings.Contains(filters[0], "armRegionName") || !strings.Contains(filters[0], "armSkuName") {
+		t.Errorf("filter %q must narrow by SKU but not by region", filters[0])
+	}
+	if cached.Cached(query) {
+		t.Error("the region-less result must not be cached under the regional key")
+	}
+}
+
+func TestCachedClientFilter_MatchesFetchedFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query PriceQuery
+	}{
+		{
+			name:  "superset service",
+			query: PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks"},
+		},
+		{
+			name:  "other service",
+			query: PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines"},
+		},
+		{
+			name:  "region-less",
+			query: PriceQuery{ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var filters []string
+			server := newPagingServer(t, 1, &filters)
+			defer server.Close()
+
+			cached := newTestCachedClient(t, newTestClient(t, server.URL), CacheConfig{
+				MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour,
+				SupersetServices: []string{"managed disks"}, Logger: zerolog.Nop(),
+			})
+			defer cached.Close()
+
+			want := cached.Filter(tt.query)
+			// The paging server only returns SKU-001, so lookups of other SKUs
+			// end in ErrNotFound after the fetch; only the filter matters here.
+			_, _ = cached.GetPrices(context.Background(), tt.query)
+
+			if len(filters) != 1 || filters[0] != want {
+				t.Errorf("Filter() = %q, fetched filters = %q", want, filters)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package azureclient
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the instrumentation scope of the client spans.
+const instrumentationName = "github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+
+// Cache lookup outcomes recorded in the cache.result span attribute.
+const (
+	cacheResultHit      = "hit"
+	cacheResultMiss     = "miss"
+	cacheResultStale    = "stale"
+	cacheResultRefresh  = "refresh"
+	cacheResultNegative = "negative"
+	cacheResultSuperset = "superset"
+)
+
+// tracer returns the tracer of the global tracer provider.
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// queryAttributes returns the span attributes describing query.
+func queryAttributes(query PriceQuery) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("azure.region", query.ArmRegionName),
+		attribute.String("azure.sku", query.ArmSkuName),
+		attribute.String("azure.service", query.ServiceName),
+	}
+}
+
+// setCacheResult records the cache lookup outcome on the span of ctx. hit
+// reports whether the lookup was answered without an upstream fetch.
+func setCacheResult(ctx context.Context, result string, hit bool) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("cache.result", result),
+		attribute.Bool("cache.hit", hit),
+	)
+}
+
+// endSpanWithError marks span failed when err is non-nil.
+func endSpanWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, errorCategory(err))
+	}
+	span.End()
+}
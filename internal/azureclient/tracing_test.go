@@ -0,0 +1,103 @@
+package azureclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// installSpanRecorder routes spans of the global tracer provider to a
+// recorder for the duration of the test. Tests using it must not run in
+// parallel.
+func installSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// hasAttribute reports whether span has the attribute kv.
+func hasAttribute(span sdktrace.ReadOnlySpan, kv attribute.KeyValue) bool {
+	return slices.Contains(span.Attributes(), kv)
+}
+
+func TestCachedClientGetPrices_TracesLookupsAndPages(t *testing.T) {
+	recorder := installSpanRecorder(t)
+
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items":[{"armSkuName":"Standard_B1s","retailPrice":0.0104}],"Count":1}`))
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.BaseURL = server.URL
+	cfg.RetryMax = 1
+	cfg.RetryWaitMin = time.Millisecond
+	cfg.RetryWaitMax = time.Millisecond
+	cfg.Logger = zerolog.Nop()
+	client, err := NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() error: %v", err)
+	}
+	cached := newTestCachedClient(t, client, CacheConfig{
+		MaxSize: 10, TTL: time.Hour, ExpiresAtTTL: time.Hour, Logger: zerolog.Nop(),
+	})
+	defer cached.Close()
+
+	query := PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	for range 2 {
+		if _, err := cached.GetPrices(context.Background(), query); err != nil {
+			t.Fatalf("GetPrices() failed: %v", err)
+		}
+	}
+
+	var lookups, pages []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		switch span.Name() {
+		case "CachedClient.GetPrices":
+			lookups = append(lookups, span)
+		case "Client.fetchPage":
+			pages = append(pages, span)
+		}
+	}
+	if len(lookups) != 2 || len(pages) != 1 {
+		t.Fatalf("got %d lookup and %d page spans, want 2 and 1", len(lookups), len(pages))
+	}
+
+	if !hasAttribute(lookups[0], attribute.Bool("cache.hit", false)) ||
+		!hasAttribute(lookups[1], attribute.Bool("cache.hit", true)) {
+		t.Error("expected a miss followed by a hit")
+	}
+	page := pages[0]
+	if page.Parent().SpanID() != lookups[0].SpanContext().SpanID() {
+		t.Error("page span should be a child of the missing lookup")
+	}
+	for _, kv := range []attribute.KeyValue{
+		attribute.Int("azure.page", 0),
+		attribute.Int("http.response.status_code", http.StatusOK),
+		attribute.Int("http.request.resend_count", 1),
+	} {
+		if !hasAttribute(page, kv) {
+			t.Errorf("page span missing %s=%s in %v", kv.Key, kv.Value.Emit(), page.Attributes())
+		}
+	}
+}
@@ -15,6 +15,10 @@ const (
 	// DefaultBaseURL is the Azure Retail Prices API endpoint.
 	DefaultBaseURL = "https://prices.azure.com/api/retail/prices"
 
+	// SavingsPlanAPIVersion is the first Retail Prices API version that
+	// returns savingsPlan prices. Queries opt in with PriceQuery.APIVersion.
+	SavingsPlanAPIVersion = "2023-01-01-preview"
+
 	// DefaultRetryMax is the default number of retry attempts.
 	DefaultRetryMax = 3
 
@@ -26,6 +30,15 @@ const (
 
 	// DefaultTimeoutSeconds is the default request timeout in seconds.
 	DefaultTimeoutSeconds = 60
+
+	// DefaultMaxResponseBodyBytes is the default size limit of one success
+	// response page. Azure returns at most 1000 items per page; 10 MB is a
+	// generous safety limit.
+	DefaultMaxResponseBodyBytes = 10 * 1024 * 1024
+
+	// DefaultSupersetMaxPages is the default page limit of region+service
+	// superset fetches (see CacheConfig.SupersetServices).
+	DefaultSupersetMaxPages = 100
 )
 
 // Config holds configuration for creating a Client.
@@ -57,6 +70,23 @@ type Config struct {
 	// UserAgent is the User-Agent header value.
 	// Default: "finfocus-plugin-azure-public"
 	UserAgent string
+
+	// MaxPages is the pagination safety limit of a query.
+	// Default: MaxPaginationPages (10)
+	MaxPages int
+
+	// SupersetMaxPages is the pagination safety limit of a region+service
+	// superset fetch, which returns far more items than a SKU query.
+	// Default: DefaultSupersetMaxPages (100)
+	SupersetMaxPages int
+
+	// MaxResponseBodyBytes is the size limit of one success response page.
+	// Default: DefaultMaxResponseBodyBytes (10 MB)
+	MaxResponseBodyBytes int64
+
+	// Observer receives upstream request and retry events.
+	// Default: nil (no observation)
+	Observer Observer
 }
 
 // DefaultConfig returns a Config with sensible defaults.
@@ -69,6 +99,10 @@ func DefaultConfig() Config {
 		Timeout:      DefaultTimeoutSeconds * time.Second,
 		Logger:       zerolog.Nop(),
 		UserAgent:    "finfocus-plugin-azure-public",
+
+		MaxPages:             MaxPaginationPages,
+		SupersetMaxPages:     DefaultSupersetMaxPages,
+		MaxResponseBodyBytes: DefaultMaxResponseBodyBytes,
 	}
 }
 
@@ -88,6 +122,15 @@ type PriceQuery struct {
 
 	// CurrencyCode filters by currency (default: "USD").
 	CurrencyCode string
+
+	// PriceType filters by price type (e.g., "Reservation").
+	// Default: "Consumption"
+	PriceType string
+
+	// APIVersion is the api-version parameter of the request (e.g.,
+	// SavingsPlanAPIVersion). Default: omitted, so the API's default version
+	// and response shape apply.
+	APIVersion string
 }
 
 // PriceItem represents a single price entry from the Azure Retail Prices API.
@@ -156,6 +199,10 @@ type PriceItem struct {
 	// This is the list price for the resource.
 	RetailPrice float64 `json:"retailPrice"`
 
+	// SavingsPlan lists the savings plan prices of a Consumption meter, one
+	// per term. Only present for meters a savings plan can cover.
+	SavingsPlan []SavingsPlanPrice `json:"savingsPlan,omitempty"`
+
 	// ServiceFamily is the service category (e.g., "Compute", "Storage", "Networking").
 	ServiceFamily string `json:"serviceFamily"`
 
@@ -188,6 +235,20 @@ type PriceItem struct {
 	UnitPrice float64 `json:"unitPrice"`
 }
 
+// SavingsPlanPrice is the savings plan price of a meter for one term, in the
+// meter's unit of measure.
+type SavingsPlanPrice struct {
+	// RetailPrice is the savings plan rate without any discount.
+	RetailPrice float64 `json:"retailPrice"`
+
+	// UnitPrice is the savings plan price per unit of measure.
+	UnitPrice float64 `json:"unitPrice"`
+
+	// Term is the savings plan commitment period.
+	// Values: "1 Year", "3 Years"
+	Term string `json:"term"`
+}
+
 // PriceResponse represents the envelope returned by the Azure Retail Prices API.
 // It contains a paginated list of price items along with metadata.
 //
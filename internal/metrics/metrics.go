@@ -0,0 +1,120 @@
+// Package metrics exports plugin metrics in the Prometheus format.
+// It combines the pluginsdk per-RPC request metrics with Azure Retail Prices
+// upstream request, retry, and price cache metrics.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	"google.golang.org/grpc"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// subsystem prefixes the plugin's own metrics, e.g.
+// "finfocus_azure_cache_hits_total".
+const subsystem = "azure"
+
+// Metrics holds the plugin's Prometheus collectors. It implements
+// azureclient.Observer, so it can be set as the Observer of the pricing
+// client and cache.
+type Metrics struct {
+	plugin *pluginsdk.PluginMetrics
+
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+	upstreamRetries  prometheus.Counter
+	cacheEvictions   *prometheus.CounterVec
+}
+
+// New creates the plugin metrics, registered with a new registry that also
+// holds the Go runtime, process, and per-RPC collectors.
+func New(pluginName string) *Metrics {
+	m := &Metrics{
+		plugin: pluginsdk.NewPluginMetrics(pluginName),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      "upstream_requests_total",
+			Help:      "Azure Retail Prices page requests by error category (ok on success).",
+		}, []string{"category"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Azure Retail Prices page request latency, including retries, by error category.",
+			Buckets:   pluginsdk.DefaultHistogramBuckets,
+		}, []string{"category"}),
+		upstreamRetries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      "upstream_retries_total",
+			Help:      "Retried Azure Retail Prices request attempts.",
+		}),
+		cacheEvictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      "cache_evictions_total",
+			Help:      "Price cache evictions by reason (lru or expired).",
+		}, []string{"reason"}),
+	}
+	m.plugin.Registry.MustRegister(m.upstreamRequests, m.upstreamDuration, m.upstreamRetries, m.cacheEvictions)
+	return m
+}
+
+// Registry returns the registry holding every plugin metric.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.plugin.Registry
+}
+
+// UnaryInterceptor returns the gRPC interceptor recording per-RPC request
+// counts by status code and latency.
+func (m *Metrics) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return pluginsdk.MetricsInterceptorWithRegistry(m.plugin)
+}
+
+// RegisterCache exposes the counters and size of cachedClient. Values are
+// read from azureclient.CacheStats on every scrape.
+func (m *Metrics) RegisterCache(cachedClient *azureclient.CachedClient) {
+	stats := cachedClient.Stats()
+	counter := func(name, help string, value func() int64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      name,
+			Help:      help,
+		}, func() float64 { return float64(value()) })
+	}
+
+	m.plugin.Registry.MustRegister(
+		counter("cache_hits_total", "Price cache hits.", stats.Hits.Load),
+		counter("cache_misses_total", "Price cache misses.", stats.Misses.Load),
+		counter("cache_coalesced_total", "Price cache misses that joined an in-flight fetch.", stats.Coalesced.Load),
+		counter("cache_stale_total", "Stale prices served.", stats.Stale.Load),
+		counter("cache_negative_hits_total", "Lookups answered from the not-found cache.", stats.NegativeHits.Load),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: pluginsdk.MetricNamespace,
+			Subsystem: subsystem,
+			Name:      "cache_entries",
+			Help:      "Entries in the price cache.",
+		}, func() float64 { return float64(cachedClient.Len()) }),
+	)
+}
+
+// ObserveRequest implements azureclient.Observer.
+func (m *Metrics) ObserveRequest(category string, duration time.Duration) {
+	m.upstreamRequests.WithLabelValues(category).Inc()
+	m.upstreamDuration.WithLabelValues(category).Observe(duration.Seconds())
+}
+
+// ObserveRetry implements azureclient.Observer.
+func (m *Metrics) ObserveRetry() {
+	m.upstreamRetries.Inc()
+}
+
+// ObserveEviction implements azureclient.Observer.
+func (m *Metrics) ObserveEviction(reason string) {
+	m.cacheEvictions.WithLabelValues(reason).Inc()
+}
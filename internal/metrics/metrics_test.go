@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// scrape returns the text exposition of m's registry.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	server := httptest.NewServer(promhttp.HandlerFor(m.Registry(), promhttp.HandlerOpts{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("scrape failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read scrape: %v", err)
+	}
+	return string(body)
+}
+
+func assertSeries(t *testing.T, exposition string, want ...string) {
+	t.Helper()
+
+	for _, series := range want {
+		if !strings.Contains(exposition, series) {
+			t.Errorf("missing series %q", series)
+		}
+	}
+}
+
+func TestMetrics_UpstreamAndEvictions(t *testing.T) {
+	t.Parallel()
+
+	m := New("test-plugin")
+	m.ObserveRequest("ok", 20*time.Millisecond)
+	m.ObserveRequest("rate_limited", time.Second)
+	m.ObserveRetry()
+	m.ObserveEviction("expired")
+
+	assertSeries(t, scrape(t, m),
+		`finfocus_azure_upstream_requests_total{category="ok"} 1`,
+		`finfocus_azure_upstream_requests_total{category="rate_limited"} 1`,
+		`finfocus_azure_upstream_request_duration_seconds_count{category="ok"} 1`,
+		`finfocus_azure_upstream_retries_total 1`,
+		`finfocus_azure_cache_evictions_total{reason="expired"} 1`,
+	)
+}
+
+func TestMetrics_CacheStats(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"Items":[{"armSkuName":"Standard_B1s","retailPrice":0.0104}],"Count":1}`))
+	}))
+	defer server.Close()
+
+	m := New("test-plugin")
+	clientConfig := azureclient.DefaultConfig()
+	clientConfig.BaseURL = server.URL
+	clientConfig.RetryMax = 0
+	clientConfig.Logger = zerolog.Nop()
+	clientConfig.Observer = m
+	client, err := azureclient.NewClient(clientConfig)
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	cacheConfig := azureclient.DefaultCacheConfig()
+	cacheConfig.Observer = m
+	cachedClient, err := azureclient.NewCachedClient(client, cacheConfig)
+	if err != nil {
+		t.Fatalf("NewCachedClient() failed: %v", err)
+	}
+	defer cachedClient.Close()
+	m.RegisterCache(cachedClient)
+
+	query := azureclient.PriceQuery{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s"}
+	for range 3 {
+		if _, err := cachedClient.GetPrices(context.Background(), query); err != nil {
+			t.Fatalf("GetPrices() failed: %v", err)
+		}
+	}
+
+	assertSeries(t, scrape(t, m),
+		"finfocus_azure_cache_hits_total 2",
+		"finfocus_azure_cache_misses_total 1",
+		"finfocus_azure_cache_entries 1",
+		`finfocus_azure_upstream_requests_total{category="ok"} 1`,
+	)
+}
+
+func TestMetrics_UnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	m := New("test-plugin")
+	interceptor := m.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/finfocus.v1.CostSource/EstimateCost"}
+	_, _ = interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		return nil, status.Error(codes.NotFound, "no price")
+	})
+
+	assertSeries(t, scrape(t, m),
+		`finfocus_plugin_requests_total{grpc_code="NotFound",grpc_method="finfocus.v1.CostSource/EstimateCost",`+
+			`plugin_name="test-plugin"} 1`,
+		`finfocus_plugin_request_duration_seconds_count{grpc_method="finfocus.v1.CostSource/EstimateCost"`,
+	)
+}
@@ -0,0 +1,481 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+const (
+	// aksServiceName is the Retail Prices serviceName of the AKS control plane.
+	aksServiceName = "Azure Kubernetes Service"
+	// defaultAKSOSDiskSizeGB is the OS disk size AKS provisions when none is set.
+	defaultAKSOSDiskSizeGB = 128
+	// defaultAKSOSDiskType is the disk type priced for managed OS disks when
+	// no explicit disk type is given (AKS defaults to Premium SSD).
+	defaultAKSOSDiskType = "premium_ssd_lrs"
+	// ephemeralOSDiskType marks node pools whose OS disk lives on the VM cache
+	// or temp disk and carries no separate disk charge.
+	ephemeralOSDiskType = "ephemeral"
+	// managedOSDiskType is the AKS osDiskType for a default managed OS disk.
+	managedOSDiskType = "managed"
+)
+
+// AKS attribute aliases, in lookup precedence order. Node pool keys are
+// looked up inside each node pool object.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	skuTierAttributeKeys        = []string{"skuTier", "sku_tier", "tier"}
+	nodePoolsAttributeKeys      = []string{"agentPoolProfiles", "nodePools", "node_pools"}
+	nodePoolVMSizeAttributeKeys = []string{"vmSize", "vm_size"}
+	nodePoolCountAttributeKeys  = []string{"count", "nodeCount", "node_count"}
+	nodePoolOSDiskTypeKeys      = []string{"osDiskType", "os_disk_type"}
+	nodePoolOSDiskSizeKeys      = []string{"osDiskSizeGB", "osDiskSizeGb", "os_disk_size_gb"}
+	// nodePoolPriorityKeys lead with the AKS scaleSetPriority property
+	// (Regular, Spot) before the shared pricing model aliases.
+	nodePoolPriorityKeys = append([]string{"scaleSetPriority", "scale_set_priority"}, pricingModelAttributeKeys...)
+)
+
+// aksSKUTiers maps lowercased cluster SKU tiers to their canonical names.
+// Standard and Premium are billed through an uptime SLA meter whose Retail
+// Prices skuName equals the canonical tier name; Free has no charge.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var aksSKUTiers = map[string]string{
+	"free":     "Free",
+	"standard": "Standard",
+	"premium":  "Premium",
+}
+
+// aksSKUTierNames lists the accepted cluster SKU tiers in display order.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var aksSKUTierNames = []string{"Free", "Standard", "Premium"}
+
+// nodePool is a validated AKS node pool.
+type nodePool struct {
+	Name   string
+	VMSize string
+	Count  int
+	// Model selects the VM meter family (Spot for spot node pools).
+	Model pricingModel
+	// OS is the node operating system (osLinux or osWindows) from the pool's
+	// osType; HybridBenefit prices Windows nodes at the Linux compute rate.
+	OS            string
+	HybridBenefit bool
+	// OSDisk is the managed OS disk type, or nil for ephemeral OS disks.
+	OSDisk       *diskTypeInfo
+	OSDiskSizeGB float64
+}
+
+// managedCluster is a validated AKS cluster description.
+type managedCluster struct {
+	Region    string
+	Currency  string
+	Tier      string
+	NodePools []nodePool
+}
+
+// estimateClusterCost handles AKS managed cluster cost estimation. Each node
+// pool is priced through the VM and disk lookups, and the uptime SLA meter is
+// added for paid control-plane tiers.
+func (c *Calculator) estimateClusterCost(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	cluster, err := estimateClusterFromRequest(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost cluster validation failed")
+		return nil, err
+	}
+
+	currency := cluster.Currency
+	category := finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD
+	var lineItems []LineItem
+	for _, pool := range cluster.NodePools {
+		// Any spot node pool makes part of the cluster cost float.
+		if pool.Model == pricingModelSpot {
+			category = pool.Model.pricingCategory()
+		}
+		poolLineItems, poolCurrency, poolErr := c.nodePoolLineItems(ctx, cluster, pool, resourceType)
+		if poolErr != nil {
+			return nil, poolErr
+		}
+		lineItems = append(lineItems, poolLineItems...)
+		currency = poolCurrency
+	}
+
+	slaLineItems, err := c.lookupUptimeSLALineItems(ctx, cluster, resourceType)
+	if err != nil {
+		return nil, err
+	}
+	slaMonthly := lineItemsTotal(slaLineItems)
+	lineItems = append(lineItems, slaLineItems...)
+	costMonthly := lineItemsTotal(lineItems)
+
+	log.Info().
+		Str("region", cluster.Region).
+		Str("sku_tier", cluster.Tier).
+		Int("node_pool_count", len(cluster.NodePools)).
+		Str("resource_type", resourceType).
+		Float64("control_plane_monthly", slaMonthly).
+		Float64("cost_monthly", costMonthly).
+		Str("currency", currency).
+		Str("result_status", "success").
+		Msg("EstimateCost cluster completed")
+
+	return &CostEstimate{
+		ResourceType:    routeManagedCluster.canonicalResourceType(),
+		Currency:        currency,
+		CostMonthly:     costMonthly,
+		PricingCategory: category,
+		LineItems:       lineItems,
+	}, nil
+}
+
+// nodePoolLineItems prices a node pool as count * (VM price + OS disk price)
+// using the shared VM and disk lookups. Line item descriptions are prefixed
+// with the pool name.
+func (c *Calculator) nodePoolLineItems(
+	ctx context.Context,
+	cluster managedCluster,
+	pool nodePool,
+	resourceType string,
+) ([]LineItem, string, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	unitPrice, err := c.lookupVMUnitPrice(ctx,
+		vmPricing{
+			Query:         nodePoolVMQuery(cluster, pool),
+			Model:         pool.Model,
+			OS:            pool.OS,
+			HybridBenefit: pool.HybridBenefit,
+		}, resourceType)
+	if err != nil {
+		return nil, "", err
+	}
+	currency := unitPrice.Currency
+	nodeMonthly := unitPrice.Hourly * pluginsdk.HoursPerMonth
+	lineItems := unitPrice.lineItems(pluginsdk.HoursPerMonth * float64(pool.Count))
+
+	var diskMonthly float64
+	if pool.OSDisk != nil {
+		diskPrice, diskErr := c.lookupDiskMonthlyPrice(ctx,
+			nodePoolDiskQuery(cluster, pool), *pool.OSDisk, pool.OSDiskSizeGB, resourceType)
+		if diskErr != nil {
+			return nil, "", diskErr
+		}
+		diskMonthly = diskPrice.Monthly
+		lineItems = append(lineItems, diskPrice.lineItem(float64(pool.Count)))
+	}
+
+	for i := range lineItems {
+		lineItems[i].Description = "node pool " + pool.Name + " " + lineItems[i].Description
+	}
+
+	poolMonthly := lineItemsTotal(lineItems)
+	log.Debug().
+		Str("node_pool", pool.Name).
+		Str("sku", pool.VMSize).
+		Str("pricing_model", string(pool.Model)).
+		Str("os", pool.OS).
+		Int("count", pool.Count).
+		Float64("node_monthly", nodeMonthly).
+		Float64("os_disk_monthly", diskMonthly).
+		Float64("pool_monthly", poolMonthly).
+		Msg("EstimateCost node pool priced")
+
+	return lineItems, currency, nil
+}
+
+// lookupUptimeSLALineItems returns the monthly control-plane charge for the
+// cluster's SKU tier. The Free tier is not billed and skips the lookup.
+func (c *Calculator) lookupUptimeSLALineItems(
+	ctx context.Context,
+	cluster managedCluster,
+	resourceType string,
+) ([]LineItem, error) {
+	if cluster.Tier == aksSKUTiers["free"] {
+		return nil, nil
+	}
+	log := logging.RequestLogger(ctx, c.logger)
+
+	query := uptimeSLAQuery(cluster)
+	result, err := c.cachedClient.GetPrices(ctx, query)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("sku_tier", cluster.Tier).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost uptime SLA pricing lookup failed")
+		return nil, err
+	}
+
+	meter, err := selectUptimeSLAMeter(result.Items, cluster.Tier)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("sku_tier", cluster.Tier).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost uptime SLA price lookup failed")
+		return nil, err
+	}
+	price, err := newTimePrice(meter, periodHour)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("sku_tier", cluster.Tier).
+			Str("unit_of_measure", meter.UnitOfMeasure).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost uptime SLA price lookup failed")
+		return nil, err
+	}
+	return []LineItem{price.lineItem("control plane "+cluster.Tier, price.Hourly, pluginsdk.HoursPerMonth)}, nil
+}
+
+// selectUptimeSLAMeter finds the hourly uptime SLA meter for a cluster tier.
+// The meter's skuName equals the tier name and its meterName contains
+// "Uptime SLA" (e.g., "Standard Uptime SLA").
+func selectUptimeSLAMeter(items []azureclient.PriceItem, tier string) (azureclient.PriceItem, error) {
+	for _, item := range items {
+		if strings.EqualFold(item.SkuName, tier) &&
+			strings.Contains(strings.ToLower(item.MeterName), "uptime sla") {
+			return item, nil
+		}
+	}
+	return azureclient.PriceItem{}, fmt.Errorf("no uptime SLA pricing found for tier %s: %w",
+		tier, azureclient.ErrNotFound)
+}
+
+// nodePoolVMQuery builds the per-node VM pricing query for a node pool.
+func nodePoolVMQuery(cluster managedCluster, pool nodePool) azureclient.PriceQuery {
+	return azureclient.PriceQuery{
+		ArmRegionName: cluster.Region,
+		ArmSkuName:    pool.VMSize,
+		ServiceName:   defaultServiceName,
+		CurrencyCode:  cluster.Currency,
+	}
+}
+
+// nodePoolDiskQuery builds the OS disk pricing query for a node pool with a
+// managed OS disk.
+func nodePoolDiskQuery(cluster managedCluster, pool nodePool) azureclient.PriceQuery {
+	return azureclient.PriceQuery{
+		ArmRegionName: cluster.Region,
+		ArmSkuName:    pool.OSDisk.ArmSkuName,
+		ServiceName:   "Managed Disks",
+		CurrencyCode:  cluster.Currency,
+	}
+}
+
+// uptimeSLAQuery builds the control-plane pricing query for a cluster.
+func uptimeSLAQuery(cluster managedCluster) azureclient.PriceQuery {
+	return azureclient.PriceQuery{
+		ArmRegionName: cluster.Region,
+		ServiceName:   aksServiceName,
+		CurrencyCode:  cluster.Currency,
+	}
+}
+
+// clusterQueries returns the distinct pricing queries needed to price a
+// cluster, in lookup order.
+func clusterQueries(cluster managedCluster) []azureclient.PriceQuery {
+	var queries []azureclient.PriceQuery
+	seen := make(map[string]bool)
+	add := func(query azureclient.PriceQuery) {
+		key := azureclient.CacheKey(query)
+		if !seen[key] {
+			seen[key] = true
+			queries = append(queries, query)
+		}
+	}
+	for _, pool := range cluster.NodePools {
+		add(nodePoolVMQuery(cluster, pool))
+		if pool.OSDisk != nil {
+			add(nodePoolDiskQuery(cluster, pool))
+		}
+	}
+	if cluster.Tier != aksSKUTiers["free"] {
+		add(uptimeSLAQuery(cluster))
+	}
+	return queries
+}
+
+// estimateClusterFromRequest extracts and validates AKS attributes from an
+// EstimateCostRequest. Node pools may be given as a list of objects or as a
+// JSON-encoded string (e.g., from resource tags). Returns an error listing
+// all missing fields, or describing the first invalid value.
+func estimateClusterFromRequest(req *finfocusv1.EstimateCostRequest) (managedCluster, error) {
+	attributes := estimateAttributes(req)
+
+	cluster := managedCluster{
+		Region: firstNonEmptyMapValue(attributes, regionAttributeKeys...),
+		Tier:   aksSKUTiers["free"],
+	}
+	if tier := firstNonEmptyMapValue(attributes, skuTierAttributeKeys...); tier != "" {
+		canonical, ok := aksSKUTiers[strings.ToLower(tier)]
+		if !ok {
+			return managedCluster{}, fmt.Errorf("unsupported sku_tier: %s (supported: %s)",
+				tier, strings.Join(aksSKUTierNames, ", "))
+		}
+		cluster.Tier = canonical
+	}
+
+	rawPools, err := nodePoolsFromAttributes(attributes)
+	if err != nil {
+		return managedCluster{}, err
+	}
+
+	var missingFields []string
+	if cluster.Region == "" {
+		missingFields = append(missingFields, "region")
+	}
+	if len(rawPools) == 0 {
+		missingFields = append(missingFields, "node_pools")
+	}
+	for i, raw := range rawPools {
+		if firstNonEmptyMapValue(raw, nodePoolVMSizeAttributeKeys...) == "" {
+			missingFields = append(missingFields, fmt.Sprintf("node_pools[%d].vm_size", i))
+		}
+		if firstNonEmptyMapValue(raw, nodePoolCountAttributeKeys...) == "" {
+			missingFields = append(missingFields, fmt.Sprintf("node_pools[%d].count", i))
+		}
+	}
+	if len(missingFields) > 0 {
+		return managedCluster{}, fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+	}
+
+	if cluster.Region, err = azureclient.NormalizeRegion(cluster.Region); err != nil {
+		return managedCluster{}, err
+	}
+	if cluster.Currency, err = currencyFromAttributes(attributes); err != nil {
+		return managedCluster{}, err
+	}
+	for i, raw := range rawPools {
+		pool, poolErr := parseNodePool(i, raw)
+		if poolErr != nil {
+			return managedCluster{}, poolErr
+		}
+		cluster.NodePools = append(cluster.NodePools, pool)
+	}
+	return cluster, nil
+}
+
+// nodePoolsFromAttributes returns the raw node pool objects from the first
+// node pool attribute present.
+func nodePoolsFromAttributes(attributes map[string]any) ([]map[string]any, error) {
+	var raw any
+	for _, key := range nodePoolsAttributeKeys {
+		if value, ok := attributes[key]; ok && value != nil {
+			raw = value
+			break
+		}
+	}
+
+	if text, ok := raw.(string); ok {
+		if strings.TrimSpace(text) == "" {
+			return nil, nil
+		}
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			return nil, fmt.Errorf("node_pools must be a list of node pool objects: %w", err)
+		}
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("node_pools must be a list of node pool objects, got %T", raw)
+	}
+	pools := make([]map[string]any, 0, len(list))
+	for i, item := range list {
+		pool, isMap := item.(map[string]any)
+		if !isMap {
+			return nil, fmt.Errorf("node_pools[%d] must be an object, got %T", i, item)
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// parseNodePool validates a single node pool object. Missing OS disk
+// settings default to a 128 GB Premium SSD managed disk, as AKS does.
+func parseNodePool(index int, raw map[string]any) (nodePool, error) {
+	field := func(name string) string { return fmt.Sprintf("node_pools[%d].%s", index, name) }
+
+	pool := nodePool{
+		Name:         firstNonEmptyMapValue(raw, "name"),
+		VMSize:       firstNonEmptyMapValue(raw, nodePoolVMSizeAttributeKeys...),
+		OSDiskSizeGB: defaultAKSOSDiskSizeGB,
+	}
+	if pool.Name == "" {
+		pool.Name = fmt.Sprintf("node_pools[%d]", index)
+	}
+
+	count, err := parseInstanceCount(field("count"), firstNonEmptyMapValue(raw, nodePoolCountAttributeKeys...))
+	if err != nil {
+		return nodePool{}, err
+	}
+	pool.Count = count
+
+	if pool.Model, err = parsePricingModel(firstNonEmptyMapValue(raw, nodePoolPriorityKeys...)); err != nil {
+		return nodePool{}, fmt.Errorf("%s: %w", field("scale_set_priority"), err)
+	}
+	if pool.OS, pool.HybridBenefit, err = vmOSFromAttributes(raw); err != nil {
+		return nodePool{}, fmt.Errorf("%s: %w", field("os_type"), err)
+	}
+
+	diskType := strings.ToLower(firstNonEmptyMapValue(raw, nodePoolOSDiskTypeKeys...))
+	switch diskType {
+	case ephemeralOSDiskType:
+		return pool, nil
+	case "", managedOSDiskType:
+		diskType = defaultAKSOSDiskType
+	}
+	diskInfo, err := normalizeDiskType(diskType)
+	if err != nil {
+		return nodePool{}, fmt.Errorf("%s: %w", field("os_disk_type"), err)
+	}
+	pool.OSDisk = &diskInfo
+
+	if sizeStr := firstNonEmptyMapValue(raw, nodePoolOSDiskSizeKeys...); sizeStr != "" {
+		if pool.OSDiskSizeGB, err = parseSizeGB(sizeStr); err != nil {
+			return nodePool{}, fmt.Errorf("%s: %w", field("os_disk_size_gb"), err)
+		}
+	}
+	return pool, nil
+}
+
+// isManagedClusterResourceType checks whether the lowercased resource type
+// refers to containerservice/managedcluster as a full segment.
+func isManagedClusterResourceType(lower string) bool {
+	return hasResourceTypeSegment(lower, "containerservice/managedcluster")
+}
@@ -0,0 +1,433 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestEstimate_ManagedCluster_SumsNodePoolsAndUptimeSLA(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newFilteringPriceServer(t, aksTestPriceItems(), &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"azure-native:containerservice/managedCluster:ManagedCluster",
+		map[string]any{
+			"location": "eastus",
+			"skuTier":  "standard",
+			"agentPoolProfiles": []any{
+				map[string]any{"name": "system", "vmSize": "Standard_D2s_v3", "count": 3},
+				map[string]any{
+					"name": "user", "vmSize": "Standard_D4s_v3", "count": 2,
+					"osDiskType": "Ephemeral",
+				},
+				map[string]any{
+					"name": "batch", "vmSize": "Standard_D2s_v3", "count": 1,
+					"osDiskType": "StandardSSD_LRS", "osDiskSizeGB": 64,
+				},
+			},
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	system := 3 * (0.096*730.0 + 19.71)
+	user := 2 * (0.192 * 730.0)
+	batch := 0.096*730.0 + 4.80
+	sla := 0.10 * 730.0
+	want := system + user + batch + sla
+	if math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Fatalf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+	if estimate.ResourceType != "containerservice/ManagedCluster" {
+		t.Errorf("ResourceType = %q", estimate.ResourceType)
+	}
+	// Two VM sizes, two disk types, and the control plane; repeated sizes hit the cache.
+	if got := calls.Load(); got != 5 {
+		t.Errorf("expected 5 upstream calls, got %d", got)
+	}
+}
+
+func TestEstimate_ManagedCluster_LineItems(t *testing.T) {
+	t.Parallel()
+
+	server := newFilteringPriceServer(t, aksTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region":  "eastus",
+			"skuTier": "Standard",
+			"nodePools": []any{
+				map[string]any{"name": "system", "vmSize": "Standard_D2s_v3", "count": 3},
+			},
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	assertLineItems(t, estimate, []LineItem{
+		{Description: "node pool system compute", UnitPrice: 0.096, Quantity: 3 * 730, CostMonthly: 0.096 * 3 * 730},
+		{Description: "node pool system disk P10", MeterName: "P10", UnitPrice: 19.71, Quantity: 3, CostMonthly: 3 * 19.71},
+		{
+			Description: "control plane Standard", MeterName: "Standard Uptime SLA",
+			UnitPrice: 0.10, Quantity: 730, CostMonthly: 0.10 * 730,
+		},
+	})
+}
+
+func TestEstimate_ManagedCluster_FreeTierSkipsUptimeSLA(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newFilteringPriceServer(t, aksTestPriceItems(), &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region":     "eastus",
+			"node_pools": `[{"vm_size": "Standard_D2s_v3", "node_count": 2, "os_disk_type": "Ephemeral"}]`,
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	want := 2 * 0.096 * 730.0
+	if math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Fatalf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected only the VM lookup, got %d upstream calls", got)
+	}
+}
+
+func TestEstimate_ManagedCluster_PremiumTier(t *testing.T) {
+	t.Parallel()
+
+	server := newFilteringPriceServer(t, aksTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region":    "eastus",
+			"tier":      "Premium",
+			"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 0}},
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+	if want := 0.60 * 730.0; math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Fatalf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+}
+
+func TestEstimate_ManagedCluster_SpotNodePool(t *testing.T) {
+	t.Parallel()
+
+	items := append(aksTestPriceItems(), azureclient.PriceItem{
+		ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", SkuName: "D2s v3 Spot",
+		MeterName: "D2s v3 Spot", CurrencyCode: "USD", RetailPrice: 0.02,
+	})
+	server := newFilteringPriceServer(t, items, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region": "eastus",
+			"agentPoolProfiles": []any{
+				map[string]any{"name": "system", "vmSize": "Standard_D2s_v3", "count": 1, "osDiskType": "Ephemeral"},
+				map[string]any{
+					"name": "spot", "vmSize": "Standard_D2s_v3", "count": 4,
+					"osDiskType": "Ephemeral", "scaleSetPriority": "Spot",
+				},
+			},
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	want := 0.096*730.0 + 4*0.02*730.0
+	if math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Fatalf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+	if estimate.PricingCategory != finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_DYNAMIC {
+		t.Errorf("PricingCategory = %v, want DYNAMIC", estimate.PricingCategory)
+	}
+}
+
+func TestEstimate_ManagedCluster_WindowsNodePool(t *testing.T) {
+	t.Parallel()
+
+	items := append(aksTestPriceItems(), azureclient.PriceItem{
+		ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", ProductName: "Virtual Machines DSv3 Series Windows",
+		MeterName: "D2s v3", CurrencyCode: "USD", RetailPrice: 0.188,
+	})
+	server := newFilteringPriceServer(t, items, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region": "eastus",
+			"agentPoolProfiles": []any{
+				map[string]any{"name": "system", "vmSize": "Standard_D2s_v3", "count": 1, "osDiskType": "Ephemeral"},
+				map[string]any{
+					"name": "win", "vmSize": "Standard_D2s_v3", "count": 2,
+					"osDiskType": "Ephemeral", "osType": "Windows",
+				},
+			},
+		},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	want := 0.096*730.0 + 2*0.188*730.0
+	if math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Fatalf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+}
+
+func TestEstimateCost_ManagedCluster_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		substrings []string
+	}{
+		{
+			name:       "missing region and node pools",
+			attributes: map[string]any{},
+			substrings: []string{"missing required field(s): region, node_pools"},
+		},
+		{
+			name: "node pool missing fields",
+			attributes: map[string]any{
+				"region":    "eastus",
+				"nodePools": []any{map[string]any{"name": "system"}},
+			},
+			substrings: []string{"node_pools[0].vm_size", "node_pools[0].count"},
+		},
+		{
+			name: "unsupported tier",
+			attributes: map[string]any{
+				"region":    "eastus",
+				"skuTier":   "Gold",
+				"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 1}},
+			},
+			substrings: []string{"unsupported sku_tier: Gold", "Free, Standard, Premium"},
+		},
+		{
+			name: "unsupported os disk type",
+			attributes: map[string]any{
+				"region":    "eastus",
+				"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 1, "osDiskType": "Ultra"}},
+			},
+			substrings: []string{"node_pools[0].os_disk_type", "unsupported disk type: ultra"},
+		},
+		{
+			name:       "node pools not a list",
+			attributes: map[string]any{"region": "eastus", "nodePools": 3},
+			substrings: []string{"node_pools must be a list"},
+		},
+		{
+			name:       "node pools invalid json",
+			attributes: map[string]any{"region": "eastus", "nodePools": "[{"},
+			substrings: []string{"node_pools must be a list"},
+		},
+		{
+			name: "fractional node count",
+			attributes: map[string]any{
+				"region":    "eastus",
+				"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 1.5}},
+			},
+			substrings: []string{"node_pools[0].count must be a whole number"},
+		},
+		{
+			name: "unsupported node pool priority",
+			attributes: map[string]any{
+				"region": "eastus",
+				"nodePools": []any{map[string]any{
+					"vmSize": "Standard_D2s_v3", "count": 1, "scaleSetPriority": "Premium",
+				}},
+			},
+			substrings: []string{"node_pools[0].scale_set_priority", "unsupported pricing_model: Premium"},
+		},
+		{
+			name: "unsupported node pool os",
+			attributes: map[string]any{
+				"region":    "eastus",
+				"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 1, "osType": "FreeBSD"}},
+			},
+			substrings: []string{"node_pools[0].os_type", "unsupported os: freebsd"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+				"containerservice/ManagedCluster", tc.attributes))
+			assertStatusCodeContains(t, err, codes.InvalidArgument, tc.substrings...)
+		})
+	}
+}
+
+func TestEstimateCost_ManagedCluster_MissingUptimeSLAMeter_ReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	items := []azureclient.PriceItem{
+		{ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", CurrencyCode: "USD", RetailPrice: 0.096},
+		{ServiceName: aksServiceName, SkuName: "Standard", MeterName: "Standard Long Term Support", RetailPrice: 0.5},
+	}
+	server := newFilteringPriceServer(t, items, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+		"containerservice/ManagedCluster",
+		map[string]any{
+			"region":    "eastus",
+			"skuTier":   "Standard",
+			"nodePools": []any{map[string]any{"vmSize": "Standard_D2s_v3", "count": 1, "osDiskType": "Ephemeral"}},
+		},
+	))
+	assertStatusCodeContains(t, err, codes.NotFound, "uptime SLA")
+}
+
+func TestClusterQueries_DeduplicatesLookups(t *testing.T) {
+	t.Parallel()
+
+	premium, err := normalizeDiskType(defaultAKSOSDiskType)
+	if err != nil {
+		t.Fatalf("normalizeDiskType() failed: %v", err)
+	}
+	cluster := managedCluster{
+		Region:   "eastus",
+		Currency: "USD",
+		Tier:     "Standard",
+		NodePools: []nodePool{
+			{VMSize: "Standard_D2s_v3", Count: 1, OSDisk: &premium, OSDiskSizeGB: 128},
+			{VMSize: "Standard_D2s_v3", Count: 2, OSDisk: &premium, OSDiskSizeGB: 256},
+		},
+	}
+
+	queries := clusterQueries(cluster)
+	if len(queries) != 3 {
+		t.Fatalf("expected VM, disk, and SLA queries, got %d: %+v", len(queries), queries)
+	}
+	if queries[2].ServiceName != aksServiceName {
+		t.Errorf("expected uptime SLA query last, got %+v", queries[2])
+	}
+}
+
+func aksTestPriceItems() []azureclient.PriceItem {
+	return []azureclient.PriceItem{
+		{ArmSkuName: "Standard_D2s_v3", ServiceName: "Virtual Machines", CurrencyCode: "USD", RetailPrice: 0.096},
+		{ArmSkuName: "Standard_D4s_v3", ServiceName: "Virtual Machines", CurrencyCode: "USD", RetailPrice: 0.192},
+		{ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks", MeterName: "P10", CurrencyCode: "USD", RetailPrice: 19.71},
+		{
+			ArmSkuName: "StandardSSD_LRS", ServiceName: "Managed Disks", MeterName: "E6",
+			CurrencyCode: "USD", RetailPrice: 4.80,
+		},
+		{ServiceName: aksServiceName, SkuName: "Standard", MeterName: "Standard Uptime SLA", RetailPrice: 0.10},
+		{ServiceName: aksServiceName, SkuName: "Premium", MeterName: "Premium Uptime SLA", RetailPrice: 0.60},
+	}
+}
+
+// filterEqPattern extracts "field eq 'value'" clauses from an OData filter.
+var filterEqPattern = regexp.MustCompile(`(\w+) eq '((?:[^']|'')*)'`)
+
+// newFilteringPriceServer serves the items whose armSkuName and serviceName
+// match the request's $filter, so one server can back multi-query estimates
+// and combined batch lookups.
+func newFilteringPriceServer(
+	t *testing.T,
+	items []azureclient.PriceItem,
+	counter *atomic.Int32,
+) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if counter != nil {
+			counter.Add(1)
+		}
+
+		// OR'ed conditions on the same field accumulate as alternatives.
+		clauses := map[string][]string{}
+		for _, match := range filterEqPattern.FindAllStringSubmatch(r.URL.Query().Get("$filter"), -1) {
+			clauses[match[1]] = append(clauses[match[1]], strings.ReplaceAll(match[2], "''", "'"))
+		}
+
+		var matched []azureclient.PriceItem
+		for _, item := range items {
+			if skus, ok := clauses["armSkuName"]; ok && !slices.Contains(skus, item.ArmSkuName) {
+				continue
+			}
+			if services, ok := clauses["serviceName"]; ok && !slices.Contains(services, item.ServiceName) {
+				continue
+			}
+			matched = append(matched, item)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(azureclient.PriceResponse{Items: matched, Count: len(matched)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+}
@@ -0,0 +1,290 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+const (
+	// appServiceName is the Retail Prices serviceName of App Service Plans.
+	appServiceName = "Azure App Service"
+	// osLinux and osWindows are the canonical operating system names.
+	osLinux   = "Linux"
+	osWindows = "Windows"
+)
+
+// App Service Plan attribute aliases, in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	planSKUAttributeKeys      = []string{"sku", "skuName", "sku_name"}
+	planCapacityAttributeKeys = []string{"capacity", "instances", "instanceCount", "workerCount", "worker_count"}
+	osAttributeKeys           = []string{"os", "osType", "os_type"}
+)
+
+// appServiceTiers maps a plan SKU family (letter plus optional "v<N>"
+// generation suffix, lowercased) to the tier name used in the Retail Prices
+// productName "Azure App Service <tier> Plan".
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var appServiceTiers = map[string]string{
+	"f":   "Free",
+	"d":   "Shared",
+	"b":   "Basic",
+	"s":   "Standard",
+	"p":   "Premium",
+	"pv2": "Premium v2",
+	"pv3": "Premium v3",
+	"i":   "Isolated",
+	"iv2": "Isolated v2",
+}
+
+// appServiceSKUPattern parses plan SKUs such as "B1", "P1v3", "P1 v3",
+// "P1mv3", and "I1v2" into family letter, size, memory-optimized marker, and
+// generation.
+//
+//nolint:gochecknoglobals // Compiled once; immutable after init.
+var appServiceSKUPattern = regexp.MustCompile(`^([a-z])(\d+)(m?)\s*(?:v(\d+))?$`)
+
+// appServicePlan is a validated App Service Plan description.
+type appServicePlan struct {
+	Query azureclient.PriceQuery
+	// SkuName is the canonical plan SKU (e.g., "P1v3").
+	SkuName  string
+	OS       string
+	Capacity int
+}
+
+// estimateAppServicePlanCost handles App Service Plan cost estimation: the
+// hourly plan SKU price times the instance count.
+func (c *Calculator) estimateAppServicePlanCost(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	plan, err := estimateAppServicePlanFromRequest(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost app service plan validation failed")
+		return nil, err
+	}
+
+	if c.cachedClient == nil {
+		unimplementedErr := status.Error(codes.Unimplemented, "not yet implemented")
+		log.Warn().
+			Str("region", plan.Query.ArmRegionName).
+			Str("sku", plan.SkuName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(unimplementedErr).
+			Msg("EstimateCost unavailable")
+		return nil, unimplementedErr
+	}
+
+	result, err := c.cachedClient.GetPrices(ctx, plan.Query)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", plan.Query.ArmRegionName).
+			Str("sku", plan.SkuName).
+			Str("os", plan.OS).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost app service plan pricing lookup failed")
+		return nil, err
+	}
+
+	price, err := findAppServicePlanPrice(result.Items, plan.SkuName)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", plan.Query.ArmRegionName).
+			Str("sku", plan.SkuName).
+			Str("os", plan.OS).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost app service plan price lookup failed")
+		return nil, err
+	}
+
+	currency := price.Currency
+	lineItem := price.lineItem("plan "+plan.SkuName, price.Hourly, pluginsdk.HoursPerMonth*float64(plan.Capacity))
+	costMonthly := lineItem.CostMonthly
+
+	log.Info().
+		Str("region", plan.Query.ArmRegionName).
+		Str("sku", plan.SkuName).
+		Str("os", plan.OS).
+		Int("capacity", plan.Capacity).
+		Str("resource_type", resourceType).
+		Float64("cost_monthly", costMonthly).
+		Str("currency", currency).
+		Str("result_status", "success").
+		Msg("EstimateCost app service plan completed")
+
+	return &CostEstimate{
+		ResourceType:    routeAppServicePlan.canonicalResourceType(),
+		Currency:        currency,
+		CostMonthly:     costMonthly,
+		PricingCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		LineItems:       []LineItem{lineItem},
+	}, nil
+}
+
+// estimateAppServicePlanFromRequest extracts and validates App Service Plan
+// attributes. The instance count defaults to 1 and the OS to Windows.
+func estimateAppServicePlanFromRequest(req *finfocusv1.EstimateCostRequest) (appServicePlan, error) {
+	attributes := estimateAttributes(req)
+
+	region := firstNonEmptyMapValue(attributes, regionAttributeKeys...)
+	sku := firstNonEmptyMapValue(attributes, planSKUAttributeKeys...)
+	var missingFields []string
+	if region == "" {
+		missingFields = append(missingFields, "region")
+	}
+	if sku == "" {
+		missingFields = append(missingFields, "sku")
+	}
+	if len(missingFields) > 0 {
+		return appServicePlan{}, fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+	}
+
+	region, err := azureclient.NormalizeRegion(region)
+	if err != nil {
+		return appServicePlan{}, err
+	}
+	skuName, tier, err := normalizeAppServiceSKU(sku)
+	if err != nil {
+		return appServicePlan{}, err
+	}
+	osName, err := planOS(attributes)
+	if err != nil {
+		return appServicePlan{}, err
+	}
+
+	capacity := 1
+	if capacityStr := firstNonEmptyMapValue(attributes, planCapacityAttributeKeys...); capacityStr != "" {
+		if capacity, err = parseInstanceCount("capacity", capacityStr); err != nil {
+			return appServicePlan{}, err
+		}
+	}
+
+	productName := "Azure App Service " + tier + " Plan"
+	if osName == osLinux {
+		productName += " - Linux"
+	}
+	currency, err := currencyFromAttributes(attributes)
+	if err != nil {
+		return appServicePlan{}, err
+	}
+
+	return appServicePlan{
+		Query: azureclient.PriceQuery{
+			ArmRegionName: region,
+			ServiceName:   appServiceName,
+			ProductName:   productName,
+			CurrencyCode:  currency,
+		},
+		SkuName:  skuName,
+		OS:       osName,
+		Capacity: capacity,
+	}, nil
+}
+
+// normalizeAppServiceSKU validates a plan SKU and returns its canonical form
+// (e.g., "p1 v3" → "P1v3") and Retail Prices tier name.
+func normalizeAppServiceSKU(sku string) (string, string, error) {
+	match := appServiceSKUPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(sku)))
+	if match == nil {
+		return "", "", fmt.Errorf("unsupported app service plan sku: %s", sku)
+	}
+	family, size, memory, generation := match[1], match[2], match[3], match[4]
+
+	key := family
+	if generation != "" {
+		key += "v" + generation
+	}
+	tier, ok := appServiceTiers[key]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported app service plan sku: %s", sku)
+	}
+
+	canonical := strings.ToUpper(family) + size + memory
+	if generation != "" {
+		canonical += "v" + generation
+	}
+	return canonical, tier, nil
+}
+
+// planOS resolves the plan operating system from the os aliases, falling back
+// to the ARM conventions kind="linux" and reserved=true. Defaults to Windows.
+func planOS(attributes map[string]any) (string, error) {
+	value := strings.ToLower(firstNonEmptyMapValue(attributes, osAttributeKeys...))
+	switch value {
+	case "":
+		kind := strings.ToLower(firstNonEmptyMapValue(attributes, "kind"))
+		reserved := strings.ToLower(firstNonEmptyMapValue(attributes, "reserved"))
+		if strings.Contains(kind, "linux") || reserved == "true" {
+			return osLinux, nil
+		}
+		return osWindows, nil
+	case "linux":
+		return osLinux, nil
+	case "windows":
+		return osWindows, nil
+	default:
+		return "", fmt.Errorf("unsupported os: %s (supported: %s, %s)", value, osLinux, osWindows)
+	}
+}
+
+// findAppServicePlanPrice returns the hourly price of a plan SKU, normalized
+// from its meter's unit of measure.
+func findAppServicePlanPrice(items []azureclient.PriceItem, skuName string) (timePrice, error) {
+	meter, err := findAppServicePlanMeter(items, skuName)
+	if err != nil {
+		return timePrice{}, err
+	}
+	return newTimePrice(meter, periodHour)
+}
+
+// findAppServicePlanMeter returns the price item of a plan SKU. SKU names are
+// compared without spaces and case so "P1 v3" in the API matches "P1v3".
+func findAppServicePlanMeter(items []azureclient.PriceItem, skuName string) (azureclient.PriceItem, error) {
+	want := compactSKU(skuName)
+	for _, item := range items {
+		if compactSKU(item.SkuName) == want {
+			return item, nil
+		}
+	}
+	return azureclient.PriceItem{}, fmt.Errorf("no pricing found for app service plan sku %s: %w",
+		skuName, azureclient.ErrNotFound)
+}
+
+// compactSKU lowercases a SKU name and removes spaces.
+func compactSKU(sku string) string {
+	return strings.ToLower(strings.ReplaceAll(sku, " ", ""))
+}
+
+// isAppServicePlanResourceType checks whether the lowercased resource type
+// refers to web/appserviceplan as a full segment.
+func isAppServicePlanResourceType(lower string) bool {
+	return hasResourceTypeSegment(lower, "web/appserviceplan")
+}
@@ -0,0 +1,183 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestEstimateCost_AppServicePlan(t *testing.T) {
+	t.Parallel()
+
+	items := []azureclient.PriceItem{
+		{ServiceName: appServiceName, SkuName: "P0 v3", MeterName: "P0 v3 App", CurrencyCode: "USD", RetailPrice: 0.077},
+		{ServiceName: appServiceName, SkuName: "P1 v3", MeterName: "P1 v3 App", CurrencyCode: "USD", RetailPrice: 0.155},
+	}
+
+	var mu sync.Mutex
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		filters = append(filters, r.URL.Query().Get("$filter"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(azureclient.PriceResponse{Items: items, Count: len(items)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	resp, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+		"web/AppServicePlan",
+		map[string]any{"location": "eastus", "sku": "p1v3", "capacity": 2, "kind": "linux"},
+	))
+	if err != nil {
+		t.Fatalf("EstimateCost() failed: %v", err)
+	}
+
+	if want := 0.155 * 730.0 * 2; math.Abs(resp.GetCostMonthly()-want) > 0.000001 {
+		t.Errorf("CostMonthly = %.6f, want %.6f", resp.GetCostMonthly(), want)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(filters) != 1 {
+		t.Fatalf("expected one upstream call, got %d", len(filters))
+	}
+	for _, clause := range []string{
+		"armRegionName eq 'eastus'",
+		"serviceName eq 'Azure App Service'",
+		"productName eq 'Azure App Service Premium v3 Plan - Linux'",
+	} {
+		if !strings.Contains(filters[0], clause) {
+			t.Errorf("filter = %q, want clause %q", filters[0], clause)
+		}
+	}
+}
+
+func TestEstimateCost_AppServicePlan_UnknownSKU_ReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ServiceName: appServiceName, SkuName: "B1", CurrencyCode: "USD", RetailPrice: 0.075},
+	}, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+		"web/AppServicePlan", map[string]any{"region": "eastus", "sku": "B3"}))
+	assertStatusCodeContains(t, err, codes.NotFound, "B3")
+}
+
+func TestEstimateAppServicePlanFromRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		attributes  map[string]any
+		wantSKU     string
+		wantProduct string
+		wantCount   int
+		wantErr     string
+	}{
+		{
+			name:        "basic windows default capacity",
+			attributes:  map[string]any{"region": "eastus", "sku": "B1"},
+			wantSKU:     "B1",
+			wantProduct: "Azure App Service Basic Plan",
+			wantCount:   1,
+		},
+		{
+			name:        "premium v2 with space",
+			attributes:  map[string]any{"region": "eastus", "skuName": "P2 v2", "os": "Windows", "instances": 3},
+			wantSKU:     "P2v2",
+			wantProduct: "Azure App Service Premium v2 Plan",
+			wantCount:   3,
+		},
+		{
+			name:        "isolated v2 reserved linux",
+			attributes:  map[string]any{"region": "eastus", "sku": "I1v2", "reserved": true},
+			wantSKU:     "I1v2",
+			wantProduct: "Azure App Service Isolated v2 Plan - Linux",
+			wantCount:   1,
+		},
+		{
+			name:        "memory optimized premium v3",
+			attributes:  map[string]any{"region": "eastus", "sku": "P1mv3", "os": "linux"},
+			wantSKU:     "P1mv3",
+			wantProduct: "Azure App Service Premium v3 Plan - Linux",
+			wantCount:   1,
+		},
+		{name: "missing fields", attributes: map[string]any{}, wantErr: "missing required field(s): region, sku"},
+		{
+			name:       "unknown family",
+			attributes: map[string]any{"region": "eastus", "sku": "X1"},
+			wantErr:    "unsupported app service plan sku: X1",
+		},
+		{
+			name:       "unknown generation",
+			attributes: map[string]any{"region": "eastus", "sku": "S1v9"},
+			wantErr:    "unsupported app service plan sku: S1v9",
+		},
+		{
+			name:       "unknown os",
+			attributes: map[string]any{"region": "eastus", "sku": "S1", "os": "plan9"},
+			wantErr:    "unsupported os: plan9",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			plan, err := estimateAppServicePlanFromRequest(newEstimateCostRequest(t, "web/AppServicePlan", tc.attributes))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if plan.SkuName != tc.wantSKU || plan.Query.ProductName != tc.wantProduct || plan.Capacity != tc.wantCount {
+				t.Errorf("got sku=%q product=%q capacity=%d", plan.SkuName, plan.Query.ProductName, plan.Capacity)
+			}
+		})
+	}
+}
+
+func TestFindAppServicePlanPrice_ComparesCompactSKU(t *testing.T) {
+	t.Parallel()
+
+	items := []azureclient.PriceItem{
+		{SkuName: "P1 v2", RetailPrice: 0.2},
+		{SkuName: "P1 v3", UnitPrice: 0.155},
+	}
+
+	price, err := findAppServicePlanPrice(items, "P1v3")
+	if err != nil {
+		t.Fatalf("findAppServicePlanPrice() failed: %v", err)
+	}
+	if price.Hourly != 0.155 || price.Currency != defaultCurrency {
+		t.Errorf("got %v %s, want UnitPrice fallback and default currency", price.Hourly, price.Currency)
+	}
+}
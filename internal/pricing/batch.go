@@ -0,0 +1,207 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+// BatchCost prices many resources in one call. Every descriptor is planned
+// with the same attribute extraction its single-resource RPC uses, the
+// resulting pricing lookups are prefetched with one combined OData request
+// per region and service (see azureclient.CachedClient.Prefetch), and each
+// resource is then priced from the warm cache. Results are returned in
+// request order; failures are reported per resource and never fail the
+// batch. Invalid requests (too many resources, an unknown query type, or a
+// missing ACTUAL time range) fail with InvalidArgument before any lookup.
+func (c *Calculator) BatchCost(
+	ctx context.Context,
+	req *finfocusv1.BatchCostRequest,
+) (*finfocusv1.BatchCostResponse, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	// An empty resource list gets its response from validation.
+	if resp, err := pluginsdk.ValidateBatchCostRequest(req, pluginsdk.DefaultMaxBatchSize); err != nil || resp != nil {
+		if err != nil {
+			log.Warn().
+				Int("resource_count", len(req.GetResources())).
+				Str("result_status", "error").
+				Err(err).
+				Msg("BatchCost validation failed")
+		}
+		return resp, err
+	}
+
+	resources := req.GetResources()
+	queryType := pluginsdk.NormalizeCostQueryType(req.GetQueryType())
+	log.Info().
+		Int("resource_count", len(resources)).
+		Str("query_type", queryType.String()).
+		Bool("dry_run", req.GetDryRun()).
+		Msg("handling BatchCost request")
+
+	var queries []azureclient.PriceQuery
+	if !req.GetDryRun() {
+		for _, desc := range resources {
+			queries = append(queries, batchQueries(req, queryType, desc)...)
+		}
+	}
+	if c.cachedClient != nil && len(queries) > 0 {
+		if err := c.cachedClient.Prefetch(ctx, queries); err != nil {
+			log.Warn().
+				Int("query_count", len(queries)).
+				Err(err).
+				Msg("BatchCost prefetch failed; resources fall back to individual lookups")
+		}
+	}
+
+	results := make([]*finfocusv1.ResourceCostResult, len(resources))
+	errorCount := 0
+	for i, desc := range resources {
+		data, err := c.batchCostData(ctx, req, queryType, desc)
+		results[i] = &finfocusv1.ResourceCostResult{Resource: desc}
+		if err != nil {
+			errorCount++
+			results[i].Result = &finfocusv1.ResourceCostResult_Error{Error: batchResourceError(err)}
+			continue
+		}
+		results[i].Result = &finfocusv1.ResourceCostResult_CostData{CostData: data}
+	}
+
+	log.Info().
+		Int("resource_count", len(resources)).
+		Int("prefetch_query_count", len(queries)).
+		Int("error_count", errorCount).
+		Msg("BatchCost completed")
+
+	return pluginsdk.NewBatchCostResponse(pluginsdk.WithBatchResults(results)), nil
+}
+
+// batchQueries returns the pricing lookups pricing desc will perform, so
+// they can be prefetched together. Resources that fail validation
+// contribute nothing; their errors surface when they are priced.
+func batchQueries(
+	req *finfocusv1.BatchCostRequest,
+	queryType finfocusv1.CostQueryType,
+	desc *finfocusv1.ResourceDescriptor,
+) []azureclient.PriceQuery {
+	if desc == nil {
+		return nil
+	}
+
+	switch queryType {
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_ACTUAL:
+		if query, ok, err := actualQueryFromRequest(batchActualCostRequest(req, desc)); ok && err == nil {
+			return []azureclient.PriceQuery{query}
+		}
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_PROJECTED:
+		projectedReq := &finfocusv1.GetProjectedCostRequest{Resource: desc}
+		if query, ok, err := projectedQueryFromRequest(projectedReq); ok && err == nil {
+			return []azureclient.PriceQuery{query}
+		}
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_ESTIMATE,
+		finfocusv1.CostQueryType_COST_QUERY_TYPE_UNSPECIFIED:
+		if !strings.EqualFold(desc.GetProvider(), "azure") {
+			return nil
+		}
+		estimateReq, err := estimateRequestFromDescriptor(desc, nil)
+		if err != nil {
+			return nil
+		}
+		queries, _ := planEstimateQueries(routeEstimate(estimateReq.GetResourceType()), estimateReq)
+		return queries
+	}
+	return nil
+}
+
+// batchCostData prices one batch resource through the RPC matching the
+// query type. Estimates convert the descriptor the same way DryRun does and
+// send no EstimateDetailHeader.
+func (c *Calculator) batchCostData(
+	ctx context.Context,
+	req *finfocusv1.BatchCostRequest,
+	queryType finfocusv1.CostQueryType,
+	desc *finfocusv1.ResourceDescriptor,
+) (*finfocusv1.CostData, error) {
+	if desc == nil {
+		return nil, status.Error(codes.InvalidArgument, "resource descriptor is required")
+	}
+
+	if req.GetDryRun() {
+		resp, err := c.DryRun(ctx, &finfocusv1.DryRunRequest{Resource: desc})
+		if err != nil {
+			return nil, err
+		}
+		return &finfocusv1.CostData{Data: &finfocusv1.CostData_DryRunResult{DryRunResult: resp}}, nil
+	}
+
+	switch queryType {
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_ACTUAL:
+		resp, err := c.GetActualCost(ctx, batchActualCostRequest(req, desc))
+		if err != nil {
+			return nil, err
+		}
+		return &finfocusv1.CostData{Data: &finfocusv1.CostData_ActualCost{ActualCost: &finfocusv1.ActualCostData{
+			Results:       resp.GetResults(),
+			FallbackHint:  resp.GetFallbackHint(),
+			NextPageToken: resp.GetNextPageToken(),
+			TotalCount:    resp.GetTotalCount(),
+		}}}, nil
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_PROJECTED:
+		resp, err := c.GetProjectedCost(ctx, &finfocusv1.GetProjectedCostRequest{Resource: desc})
+		if err != nil {
+			return nil, err
+		}
+		return &finfocusv1.CostData{Data: &finfocusv1.CostData_ProjectedCost{ProjectedCost: resp}}, nil
+	case finfocusv1.CostQueryType_COST_QUERY_TYPE_ESTIMATE,
+		finfocusv1.CostQueryType_COST_QUERY_TYPE_UNSPECIFIED:
+	}
+
+	if !strings.EqualFold(desc.GetProvider(), "azure") {
+		return nil, status.Errorf(codes.Unimplemented, "unsupported provider: %s", desc.GetProvider())
+	}
+	estimateReq, err := estimateRequestFromDescriptor(desc, nil)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	// Estimate rather than EstimateCost: the detail header is per call, and
+	// the batch shares one.
+	estimate, err := c.Estimate(ctx, estimateReq)
+	if err != nil {
+		return nil, err
+	}
+	return &finfocusv1.CostData{Data: &finfocusv1.CostData_Estimate{Estimate: estimate.response()}}, nil
+}
+
+// batchActualCostRequest builds the GetActualCost request for a batch
+// resource, identifying it by ID or ARN like the SDK's fallback does.
+func batchActualCostRequest(
+	req *finfocusv1.BatchCostRequest,
+	desc *finfocusv1.ResourceDescriptor,
+) *finfocusv1.GetActualCostRequest {
+	resourceID := desc.GetId()
+	if resourceID == "" {
+		resourceID = desc.GetArn()
+	}
+	return &finfocusv1.GetActualCostRequest{
+		ResourceId: resourceID,
+		Start:      req.GetStart(),
+		End:        req.GetEnd(),
+		Tags:       desc.GetTags(),
+		Arn:        desc.GetArn(),
+	}
+}
+
+// batchResourceError converts a gRPC status error into a per-resource
+// error. Unimplemented marks the resource type as unsupported.
+func batchResourceError(err error) *finfocusv1.ResourceError {
+	st := status.Convert(err)
+	return pluginsdk.NewResourceError(st.Code(), st.Message(), st.Code() == codes.Unimplemented)
+}
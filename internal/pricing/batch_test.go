@@ -0,0 +1,227 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestBatchCost_EstimatesInOrderWithPerItemErrors(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newFilteringPriceServer(t, aksTestPriceItems(), &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	vm := func(sku string) *finfocusv1.ResourceDescriptor {
+		return &finfocusv1.ResourceDescriptor{
+			Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: sku,
+		}
+	}
+	resources := []*finfocusv1.ResourceDescriptor{
+		vm("Standard_D2s_v3"),
+		vm("Standard_D4s_v3"),
+		{Provider: "azure", ResourceType: "storage/ManagedDisk", Region: "eastus", Sku: "Premium_SSD_LRS",
+			Tags: map[string]string{"size_gb": "128"}},
+		vm("Standard_D2s_v3"),
+		{Provider: "azure", ResourceType: "compute/VirtualMachine", Sku: "Standard_D2s_v3"},
+		{Provider: "aws", ResourceType: "ec2/Instance", Region: "us-east-1", Sku: "t3.micro"},
+		{Provider: "azure", ResourceType: "network/LoadBalancer", Region: "eastus"},
+	}
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	resp, err := calc.BatchCost(context.Background(), &finfocusv1.BatchCostRequest{
+		Resources: resources,
+		QueryType: finfocusv1.CostQueryType_COST_QUERY_TYPE_ESTIMATE,
+	})
+	if err != nil {
+		t.Fatalf("BatchCost() failed: %v", err)
+	}
+	results := resp.GetResults()
+	if len(results) != len(resources) {
+		t.Fatalf("got %d results, want %d", len(results), len(resources))
+	}
+
+	wantCosts := []float64{0.096 * 730, 0.192 * 730, 19.71, 0.096 * 730}
+	for i, want := range wantCosts {
+		if results[i].GetError() != nil {
+			t.Fatalf("result %d: unexpected error %v", i, results[i].GetError())
+		}
+		if results[i].GetResource() != resources[i] {
+			t.Errorf("result %d: resource out of order", i)
+		}
+		if got := results[i].GetCostData().GetEstimate().GetCostMonthly(); math.Abs(got-want) > 0.000001 {
+			t.Errorf("result %d: CostMonthly = %.6f, want %.6f", i, got, want)
+		}
+	}
+
+	wantErrors := []struct {
+		code        codes.Code
+		unsupported bool
+	}{
+		{code: codes.InvalidArgument},
+		{code: codes.Unimplemented, unsupported: true},
+		{code: codes.Unimplemented, unsupported: true},
+	}
+	for i, want := range wantErrors {
+		resultErr := results[len(wantCosts)+i].GetError()
+		if resultErr == nil {
+			t.Fatalf("result %d: expected error", len(wantCosts)+i)
+		}
+		if codes.Code(resultErr.GetCode()) != want.code || resultErr.GetResourceTypeUnsupported() != want.unsupported {
+			t.Errorf("result %d: error = %v, want code %v unsupported %v",
+				len(wantCosts)+i, resultErr, want.code, want.unsupported)
+		}
+	}
+
+	// Both VM sizes share one combined request; the disk is its own group.
+	if got := calls.Load(); got != 2 {
+		t.Errorf("expected 2 upstream calls, got %d", got)
+	}
+}
+
+func TestBatchCost_ProjectedAndDryRun(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newFilteringPriceServer(t, aksTestPriceItems(), &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	resources := []*finfocusv1.ResourceDescriptor{
+		{Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: "Standard_D2s_v3"},
+		{Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: "Standard_D4s_v3"},
+	}
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	resp, err := calc.BatchCost(context.Background(), &finfocusv1.BatchCostRequest{
+		Resources: resources,
+		QueryType: finfocusv1.CostQueryType_COST_QUERY_TYPE_PROJECTED,
+	})
+	if err != nil {
+		t.Fatalf("BatchCost() failed: %v", err)
+	}
+	if got := resp.GetResults()[1].GetCostData().GetProjectedCost().GetUnitPrice(); got != 0.192 {
+		t.Errorf("projected UnitPrice = %v, want 0.192", got)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 combined upstream call, got %d", got)
+	}
+
+	resp, err = calc.BatchCost(context.Background(), &finfocusv1.BatchCostRequest{
+		Resources: resources,
+		DryRun:    true,
+	})
+	if err != nil {
+		t.Fatalf("BatchCost() dry run failed: %v", err)
+	}
+	if !resp.GetResults()[0].GetCostData().GetDryRunResult().GetResourceTypeSupported() {
+		t.Error("dry run should report the VM as supported")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("dry run must not call Azure, got %d calls", got)
+	}
+}
+
+func TestBatchCost_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	vm := &finfocusv1.ResourceDescriptor{
+		Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: "Standard_D2s_v3",
+	}
+	tooMany := make([]*finfocusv1.ResourceDescriptor, 101)
+	for i := range tooMany {
+		tooMany[i] = vm
+	}
+	tests := []struct {
+		name      string
+		req       *finfocusv1.BatchCostRequest
+		substring string
+	}{
+		{name: "nil request", req: nil, substring: "request is required"},
+		{
+			name:      "too many resources",
+			req:       &finfocusv1.BatchCostRequest{Resources: tooMany},
+			substring: "batch size 101 exceeds max_batch_size 100",
+		},
+		{
+			name: "query type",
+			req: &finfocusv1.BatchCostRequest{
+				Resources: []*finfocusv1.ResourceDescriptor{vm},
+				QueryType: finfocusv1.CostQueryType(99),
+			},
+			substring: "invalid query_type",
+		},
+		{
+			name: "actual without time range",
+			req: &finfocusv1.BatchCostRequest{
+				Resources: []*finfocusv1.ResourceDescriptor{vm},
+				QueryType: finfocusv1.CostQueryType_COST_QUERY_TYPE_ACTUAL,
+			},
+			substring: "start and end are required",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// No pricing client: validation must fail before any lookup.
+			_, err := NewCalculator(zerolog.Nop()).BatchCost(context.Background(), tc.req)
+			assertStatusCodeContains(t, err, codes.InvalidArgument, tc.substring)
+		})
+	}
+}
+
+func TestBatchCost_EmptyRequest(t *testing.T) {
+	t.Parallel()
+
+	resp, err := NewCalculator(zerolog.Nop()).BatchCost(context.Background(), &finfocusv1.BatchCostRequest{})
+	if err != nil {
+		t.Fatalf("BatchCost() failed: %v", err)
+	}
+	if len(resp.GetResults()) != 0 {
+		t.Errorf("got %d results, want none", len(resp.GetResults()))
+	}
+}
+
+func TestBatchCost_SendsNoEstimateDetailHeader(t *testing.T) {
+	t.Parallel()
+
+	server := newFilteringPriceServer(t, aksTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	vm := &finfocusv1.ResourceDescriptor{
+		Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: "Standard_D2s_v3",
+	}
+	stream := &headerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	resp, err := NewCalculator(zerolog.Nop(), cachedClient).BatchCost(ctx, &finfocusv1.BatchCostRequest{
+		Resources: []*finfocusv1.ResourceDescriptor{vm, vm, vm},
+	})
+	if err != nil {
+		t.Fatalf("BatchCost() failed: %v", err)
+	}
+	for i, result := range resp.GetResults() {
+		if result.GetError() != nil {
+			t.Fatalf("result %d: unexpected error %v", i, result.GetError())
+		}
+	}
+	if got := stream.header.Get(EstimateDetailHeader); len(got) != 0 {
+		t.Errorf("got %d %s headers, want none", len(got), EstimateDetailHeader)
+	}
+}
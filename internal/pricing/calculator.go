@@ -92,38 +92,156 @@ func (c *Calculator) Supports(
 }
 
 // EstimateCost estimates monthly cost from Azure Retail Prices data.
-// Supports VM and Managed Disk resource types via resource-type routing.
-// The request must contain the appropriate attributes for the resource type.
-// Returns InvalidArgument for missing required fields, Unimplemented for
-// unsupported resource types, and mapped gRPC status codes for Azure API
-// failures.
+// Supports VM, VM Scale Set, Managed Disk, Blob Storage, AKS managed cluster,
+// App Service Plan, and Function App resource types via resource-type
+// routing. The request must contain the appropriate attributes for the
+// resource type. Returns InvalidArgument for missing required fields,
+// Unimplemented for unsupported resource types, and mapped gRPC status codes
+// for Azure API failures. Detail the response has no fields for, such as the
+// cost range of autoscaling resources, is sent in the EstimateDetailHeader
+// response header.
 func (c *Calculator) EstimateCost(
 	ctx context.Context,
 	req *finfocusv1.EstimateCostRequest,
 ) (*finfocusv1.EstimateCostResponse, error) {
+	estimate, err := c.Estimate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err = sendEstimateDetail(ctx, estimate); err != nil {
+		log := logging.RequestLogger(ctx, c.logger)
+		log.Warn().
+			Str("resource_type", estimate.ResourceType).
+			Err(err).
+			Msg("EstimateCost detail header not sent")
+	}
+	return estimate.response(), nil
+}
+
+// Estimate computes the detailed cost estimate behind EstimateCost, including
+// the low/expected/high range for autoscaling resources. When the include_usd
+// attribute is true and the currency is not USD, the estimate is repeated in
+// USD and attached as CostEstimate.USD. Errors are gRPC status errors with
+// the same codes EstimateCost returns.
+func (c *Calculator) Estimate(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+) (*CostEstimate, error) {
 	log := logging.RequestLogger(ctx, c.logger)
 
 	resourceType := strings.TrimSpace(req.GetResourceType())
-	lowerType := strings.ToLower(resourceType)
 
 	log.Info().
 		Str("resource_type", resourceType).
 		Msg("handling EstimateCost request")
 
-	// Route by resource type: disk → VM → backward compat (empty) → reject
-	switch {
-	case isManagedDiskResourceType(lowerType):
+	attributes := estimateAttributes(req)
+	currency, err := currencyFromAttributes(attributes)
+	if err != nil {
+		return nil, estimateValidationError(log, resourceType, err)
+	}
+	includeUSD, err := includeUSDFromAttributes(attributes)
+	if err != nil {
+		return nil, estimateValidationError(log, resourceType, err)
+	}
+
+	estimate, err := c.estimate(ctx, req, resourceType)
+	if err != nil || !includeUSD || currency == defaultCurrency {
+		return estimate, err
+	}
+
+	usdReq, err := usdEstimateRequest(req, attributes)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if estimate.USD, err = c.estimate(ctx, usdReq, resourceType); err != nil {
+		return nil, err
+	}
+	return estimate, nil
+}
+
+// estimateValidationError logs and returns err as an InvalidArgument status.
+func estimateValidationError(log zerolog.Logger, resourceType string, err error) error {
+	err = status.Error(codes.InvalidArgument, err.Error())
+	log.Warn().
+		Str("resource_type", resourceType).
+		Str("result_status", "error").
+		Err(err).
+		Msg("EstimateCost validation failed")
+	return err
+}
+
+// estimate routes req to the pricing path for resourceType.
+func (c *Calculator) estimate(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	switch routeEstimate(resourceType) {
+	case routeManagedDisk:
 		return c.estimateDiskCost(ctx, req, resourceType)
-	case resourceType == "" || isVirtualMachineResourceType(lowerType):
+	case routeVirtualMachine:
 		return c.estimateVMCost(ctx, req, resourceType)
+	case routeVirtualMachineScaleSet:
+		return c.estimateScaleSetCost(ctx, req, resourceType)
+	case routeManagedCluster:
+		return c.estimateClusterCost(ctx, req, resourceType)
+	case routeAppServicePlan:
+		return c.estimateAppServicePlanCost(ctx, req, resourceType)
+	case routeFunctionApp:
+		return c.estimateFunctionAppCost(ctx, req, resourceType)
+	case routeStorage:
+		return c.estimateStorageCost(ctx, req, resourceType)
+	case routeUnsupported:
+		// Rejected below.
+	}
+
+	log := logging.RequestLogger(ctx, c.logger)
+	err := status.Errorf(codes.Unimplemented, "unsupported resource type: %s", resourceType)
+	log.Warn().
+		Str("resource_type", resourceType).
+		Str("result_status", "error").
+		Err(err).
+		Msg("EstimateCost validation failed")
+	return nil, err
+}
+
+// estimateRoute identifies the EstimateCost pricing path for a resource type.
+type estimateRoute int
+
+const (
+	routeUnsupported estimateRoute = iota
+	routeVirtualMachine
+	routeVirtualMachineScaleSet
+	routeManagedDisk
+	routeManagedCluster
+	routeAppServicePlan
+	routeFunctionApp
+	routeStorage
+)
+
+// routeEstimate resolves the pricing path for a resource type.
+// Route by resource type: disk → blob storage → cluster → app service plan →
+// function app → scale set → VM → backward compat (empty) → reject.
+func routeEstimate(resourceType string) estimateRoute {
+	lowerType := strings.ToLower(strings.TrimSpace(resourceType))
+	switch {
+	case isManagedDiskResourceType(lowerType):
+		return routeManagedDisk
+	case isBlobStorageResourceType(lowerType):
+		return routeStorage
+	case isManagedClusterResourceType(lowerType):
+		return routeManagedCluster
+	case isAppServicePlanResourceType(lowerType):
+		return routeAppServicePlan
+	case isFunctionAppResourceType(lowerType):
+		return routeFunctionApp
+	case isScaleSetResourceType(lowerType):
+		return routeVirtualMachineScaleSet
+	case lowerType == "" || isVirtualMachineResourceType(lowerType):
+		return routeVirtualMachine
 	default:
-		err := status.Errorf(codes.Unimplemented, "unsupported resource type: %s", resourceType)
-		log.Warn().
-			Str("resource_type", resourceType).
-			Str("result_status", "error").
-			Err(err).
-			Msg("EstimateCost validation failed")
-		return nil, err
+		return routeUnsupported
 	}
 }
 
@@ -132,10 +250,10 @@ func (c *Calculator) estimateVMCost(
 	ctx context.Context,
 	req *finfocusv1.EstimateCostRequest,
 	resourceType string,
-) (*finfocusv1.EstimateCostResponse, error) {
+) (*CostEstimate, error) {
 	log := logging.RequestLogger(ctx, c.logger)
 
-	query, err := estimateQueryFromRequest(req)
+	vm, err := estimateVMFromRequest(req)
 	if err != nil {
 		err = status.Error(codes.InvalidArgument, err.Error())
 		log.Warn().
@@ -146,6 +264,63 @@ func (c *Calculator) estimateVMCost(
 		return nil, err
 	}
 
+	unitPrice, err := c.lookupVMUnitPrice(ctx, vm, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	costMonthly := unitPrice.Hourly * pluginsdk.HoursPerMonth
+	licenseMonthly := unitPrice.LicenseHourly * pluginsdk.HoursPerMonth
+
+	var reservations []ReservationEstimate
+	var savingsPlans []SavingsPlanEstimate
+	if vm.IncludeReservations {
+		reservations, err = c.lookupReservationEstimates(ctx, vm, costMonthly, licenseMonthly, resourceType)
+		if err != nil {
+			return nil, err
+		}
+		savingsPlans = savingsPlanEstimates(unitPrice.SavingsPlans, costMonthly, licenseMonthly)
+	}
+
+	log.Info().
+		Str("region", vm.Query.ArmRegionName).
+		Str("sku", vm.Query.ArmSkuName).
+		Str("pricing_model", string(vm.Model)).
+		Str("os", vm.OS).
+		Bool("azure_hybrid_benefit", vm.HybridBenefit).
+		Str("resource_type", resourceType).
+		Float64("cost_monthly", costMonthly).
+		Float64("license_cost_monthly", licenseMonthly).
+		Int("reservation_terms", len(reservations)).
+		Int("savings_plan_terms", len(savingsPlans)).
+		Str("currency", unitPrice.Currency).
+		Str("result_status", "success").
+		Msg("EstimateCost completed")
+
+	return &CostEstimate{
+		ResourceType:       routeVirtualMachine.canonicalResourceType(),
+		Currency:           unitPrice.Currency,
+		CostMonthly:        costMonthly,
+		LicenseCostMonthly: licenseMonthly,
+		PricingCategory:    vm.Model.pricingCategory(),
+		LineItems:          unitPrice.lineItems(pluginsdk.HoursPerMonth),
+		Reservations:       reservations,
+		SavingsPlans:       savingsPlans,
+	}, nil
+}
+
+// lookupVMUnitPrice fetches the hourly price of a single VM instance in the
+// requested pricing model and OS through the CachedClient. Shared by the VM, scale
+// set, and AKS paths. Errors are gRPC status errors and are logged before
+// being returned.
+func (c *Calculator) lookupVMUnitPrice(
+	ctx context.Context,
+	vm vmPricing,
+	resourceType string,
+) (vmUnitPrice, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+	query := vm.Query
+
 	if c.cachedClient == nil {
 		unimplementedErr := status.Error(codes.Unimplemented, "not yet implemented")
 		log.Warn().
@@ -155,7 +330,7 @@ func (c *Calculator) estimateVMCost(
 			Str("result_status", "error").
 			Err(unimplementedErr).
 			Msg("EstimateCost unavailable")
-		return nil, unimplementedErr
+		return vmUnitPrice{}, unimplementedErr
 	}
 
 	result, err := c.cachedClient.GetPrices(ctx, query)
@@ -168,39 +343,25 @@ func (c *Calculator) estimateVMCost(
 			Str("result_status", "error").
 			Err(err).
 			Msg("EstimateCost pricing lookup failed")
-		return nil, err
+		return vmUnitPrice{}, err
 	}
 
-	unitPrice, currency, err := unitPriceAndCurrency(result.Items)
+	unitPrice, err := selectVMPrice(result.Items, vm)
 	if err != nil {
 		err = MapToGRPCStatus(err).Err()
 		log.Error().
 			Str("region", query.ArmRegionName).
 			Str("sku", query.ArmSkuName).
+			Str("pricing_model", string(vm.Model)).
+			Str("os", vm.OS).
 			Str("resource_type", resourceType).
 			Str("result_status", "error").
 			Err(err).
 			Msg("EstimateCost response mapping failed")
-		return nil, err
+		return vmUnitPrice{}, err
 	}
 
-	costMonthly := unitPrice * pluginsdk.HoursPerMonth
-
-	log.Info().
-		Str("region", query.ArmRegionName).
-		Str("sku", query.ArmSkuName).
-		Str("resource_type", resourceType).
-		Float64("cost_monthly", costMonthly).
-		Str("currency", currency).
-		Str("result_status", "success").
-		Msg("EstimateCost completed")
-
-	return pluginsdk.NewEstimateCostResponse(
-		pluginsdk.WithEstimateCost(currency, costMonthly),
-		pluginsdk.WithPricingCategory(
-			finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
-		),
-	), nil
+	return unitPrice, nil
 }
 
 // estimateDiskCost handles Managed Disk cost estimation.
@@ -209,7 +370,7 @@ func (c *Calculator) estimateDiskCost(
 	ctx context.Context,
 	req *finfocusv1.EstimateCostRequest,
 	resourceType string,
-) (*finfocusv1.EstimateCostResponse, error) {
+) (*CostEstimate, error) {
 	log := logging.RequestLogger(ctx, c.logger)
 
 	query, diskInfo, sizeGB, err := estimateDiskQueryFromRequest(req)
@@ -223,6 +384,43 @@ func (c *Calculator) estimateDiskCost(
 		return nil, err
 	}
 
+	price, err := c.lookupDiskMonthlyPrice(ctx, query, diskInfo, sizeGB, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().
+		Str("region", query.ArmRegionName).
+		Str("disk_type", diskInfo.ArmSkuName).
+		Float64("size_gb", sizeGB).
+		Str("tier", price.Tier).
+		Str("resource_type", resourceType).
+		Float64("cost_monthly", price.Monthly).
+		Str("currency", price.Currency).
+		Str("result_status", "success").
+		Msg("EstimateCost disk completed")
+
+	return &CostEstimate{
+		ResourceType:    routeManagedDisk.canonicalResourceType(),
+		Currency:        price.Currency,
+		CostMonthly:     price.Monthly,
+		PricingCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		LineItems:       []LineItem{price.lineItem(1)},
+	}, nil
+}
+
+// lookupDiskMonthlyPrice fetches the monthly price of the disk tier that fits
+// sizeGB through the CachedClient. Shared by the disk and AKS paths. Errors
+// are gRPC status errors and are logged before being returned.
+func (c *Calculator) lookupDiskMonthlyPrice(
+	ctx context.Context,
+	query azureclient.PriceQuery,
+	diskInfo diskTypeInfo,
+	sizeGB float64,
+	resourceType string,
+) (diskTierPrice, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
 	if c.cachedClient == nil {
 		unimplementedErr := status.Error(codes.Unimplemented, "not yet implemented")
 		log.Warn().
@@ -233,7 +431,7 @@ func (c *Calculator) estimateDiskCost(
 			Str("result_status", "error").
 			Err(unimplementedErr).
 			Msg("EstimateCost unavailable")
-		return nil, unimplementedErr
+		return diskTierPrice{}, unimplementedErr
 	}
 
 	result, err := c.cachedClient.GetPrices(ctx, query)
@@ -247,7 +445,7 @@ func (c *Calculator) estimateDiskCost(
 			Str("result_status", "error").
 			Err(err).
 			Msg("EstimateCost disk pricing lookup failed")
-		return nil, err
+		return diskTierPrice{}, err
 	}
 
 	tierName, err := tierForSize(diskInfo.TierPrefix, sizeGB)
@@ -262,10 +460,10 @@ func (c *Calculator) estimateDiskCost(
 			Str("result_status", "error").
 			Err(notFoundErr).
 			Msg("EstimateCost disk tier lookup failed")
-		return nil, notFoundErr
+		return diskTierPrice{}, notFoundErr
 	}
 
-	costMonthly, currency, err := selectDiskTierPrice(result.Items, tierName, diskInfo.Redundancy)
+	meter, err := findDiskTierMeter(result.Items, tierName, diskInfo.Redundancy)
 	if err != nil {
 		err = MapToGRPCStatus(err).Err()
 		log.Error().
@@ -277,26 +475,24 @@ func (c *Calculator) estimateDiskCost(
 			Str("result_status", "error").
 			Err(err).
 			Msg("EstimateCost disk tier price lookup failed")
-		return nil, err
+		return diskTierPrice{}, err
 	}
 
-	log.Info().
-		Str("region", query.ArmRegionName).
-		Str("disk_type", diskInfo.ArmSkuName).
-		Float64("size_gb", sizeGB).
-		Str("tier", tierName).
-		Str("resource_type", resourceType).
-		Float64("cost_monthly", costMonthly).
-		Str("currency", currency).
-		Str("result_status", "success").
-		Msg("EstimateCost disk completed")
-
-	return pluginsdk.NewEstimateCostResponse(
-		pluginsdk.WithEstimateCost(currency, costMonthly),
-		pluginsdk.WithPricingCategory(
-			finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
-		),
-	), nil
+	price, err := newDiskTierPrice(meter, tierName)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("disk_type", diskInfo.ArmSkuName).
+			Str("tier", tierName).
+			Str("unit_of_measure", meter.UnitOfMeasure).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost disk tier price lookup failed")
+		return diskTierPrice{}, err
+	}
+	return price, nil
 }
 
 // estimateDiskQueryFromRequest extracts and validates disk-specific attributes
@@ -305,18 +501,11 @@ func (c *Calculator) estimateDiskCost(
 func estimateDiskQueryFromRequest(
 	req *finfocusv1.EstimateCostRequest,
 ) (azureclient.PriceQuery, diskTypeInfo, float64, error) {
-	attributes := map[string]any{}
-	if req != nil && req.GetAttributes() != nil {
-		attributes = req.GetAttributes().AsMap()
-	}
+	attributes := estimateAttributes(req)
 
-	region := firstNonEmptyMapValue(attributes, "location", "region")
-	diskTypeStr := firstNonEmptyMapValue(attributes, "diskType", "disk_type", "sku")
-	sizeGBStr := firstNonEmptyMapValue(attributes, "sizeGb", "size_gb", "diskSizeGb")
-	currency := firstNonEmptyMapValue(attributes, "currencyCode", "currency")
-	if currency == "" {
-		currency = defaultCurrency
-	}
+	region := firstNonEmptyMapValue(attributes, regionAttributeKeys...)
+	diskTypeStr := firstNonEmptyMapValue(attributes, diskTypeAttributeKeys...)
+	sizeGBStr := firstNonEmptyMapValue(attributes, sizeGBAttributeKeys...)
 
 	// Validate required fields — report all missing in one error.
 	var missingFields []string
@@ -334,6 +523,15 @@ func estimateDiskQueryFromRequest(
 			fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
 	}
 
+	region, err := azureclient.NormalizeRegion(region)
+	if err != nil {
+		return azureclient.PriceQuery{}, diskTypeInfo{}, 0, err
+	}
+	currency, err := currencyFromAttributes(attributes)
+	if err != nil {
+		return azureclient.PriceQuery{}, diskTypeInfo{}, 0, err
+	}
+
 	// Parse and validate size_gb.
 	sizeGB, err := parseSizeGB(sizeGBStr)
 	if err != nil {
@@ -369,8 +567,11 @@ func parseSizeGB(value string) (float64, error) {
 	return sizeGB, nil
 }
 
-// GetActualCost is a stub that returns Unimplemented status.
-// Cost history retrieval is not yet implemented.
+// GetActualCost returns the hourly list price of the SKU in the request's
+// region and SKU tags as a one-hour cost record; the Retail Prices API has no
+// usage history. The FOCUS record carries the billing currency, and the
+// include_usd tag adds the USD price as the pricing currency. Requests
+// without region and SKU tags return Unimplemented.
 func (c *Calculator) GetActualCost(
 	ctx context.Context,
 	req *finfocusv1.GetActualCostRequest,
@@ -378,7 +579,14 @@ func (c *Calculator) GetActualCost(
 	log := logging.RequestLogger(ctx, c.logger)
 	log.Info().Msg("handling GetActualCost request")
 
-	query, ok := actualQueryFromRequest(req)
+	query, ok, err := actualQueryFromRequest(req)
+	if err != nil {
+		return nil, MapToGRPCStatus(err).Err()
+	}
+	includeUSD, err := includeUSDFromTags(req.GetTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 	if !ok || c.cachedClient == nil {
 		return nil, status.Error(codes.Unimplemented, "not yet implemented")
 	}
@@ -388,17 +596,43 @@ func (c *Calculator) GetActualCost(
 		return nil, MapToGRPCStatus(err).Err()
 	}
 
-	unitPrice, _, err := unitPriceAndCurrency(cachedResult.Items)
+	criteria, err := meterCriteriaFromTags(req.GetTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	criteria.ArmSkuName = query.ArmSkuName
+	unitPrice, currency, err := unitPriceAndCurrency(cachedResult.Items, criteria)
 	if err != nil {
 		return nil, MapToGRPCStatus(err).Err()
 	}
 
+	record := &finfocusv1.FocusCostRecord{
+		BillingCurrency: currency,
+		ListUnitPrice:   unitPrice,
+		ListCost:        unitPrice,
+		PricingQuantity: 1,
+		PricingUnit:     "Hours",
+	}
+	if includeUSD && currency != defaultCurrency {
+		usdResult, usdErr := c.cachedClient.GetPrices(ctx, usdQuery(query))
+		if usdErr != nil {
+			return nil, MapToGRPCStatus(usdErr).Err()
+		}
+		usdPrice, _, usdErr := unitPriceAndCurrency(usdResult.Items, criteria)
+		if usdErr != nil {
+			return nil, MapToGRPCStatus(usdErr).Err()
+		}
+		record.PricingCurrency = defaultCurrency
+		record.PricingCurrencyListUnitPrice = usdPrice
+	}
+
 	result := &finfocusv1.ActualCostResult{
 		Timestamp:   timestamppb.Now(),
 		Cost:        unitPrice,
 		UsageAmount: 1,
 		UsageUnit:   "hour",
-		Source:      "azure-retail-prices",
+		Source:      pricingSource,
+		FocusRecord: record,
 	}
 	pluginsdk.ApplyActualCostResultOptions(
 		result,
@@ -420,7 +654,10 @@ func (c *Calculator) GetProjectedCost(
 	log := logging.RequestLogger(ctx, c.logger)
 	log.Info().Msg("handling GetProjectedCost request")
 
-	query, ok := projectedQueryFromRequest(req)
+	query, ok, err := projectedQueryFromRequest(req)
+	if err != nil {
+		return nil, MapToGRPCStatus(err).Err()
+	}
 	if !ok || c.cachedClient == nil {
 		return nil, status.Error(codes.Unimplemented, "not yet implemented")
 	}
@@ -430,7 +667,12 @@ func (c *Calculator) GetProjectedCost(
 		return nil, MapToGRPCStatus(err).Err()
 	}
 
-	unitPrice, currency, err := unitPriceAndCurrency(cachedResult.Items)
+	criteria, err := meterCriteriaFromTags(req.GetResource().GetTags())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	criteria.ArmSkuName = query.ArmSkuName
+	unitPrice, currency, err := unitPriceAndCurrency(cachedResult.Items, criteria)
 	if err != nil {
 		return nil, MapToGRPCStatus(err).Err()
 	}
@@ -440,34 +682,101 @@ func (c *Calculator) GetProjectedCost(
 			unitPrice,
 			currency,
 			unitPrice*pluginsdk.HoursPerMonth,
-			"azure-retail-prices",
+			pricingSource,
 		),
 		pluginsdk.WithProjectedCostExpiresAt(cachedResult.ExpiresAt),
 	), nil
 }
 
-// GetPricingSpec is a stub that returns Unimplemented status.
-// Pricing schema is not yet implemented.
+// GetPricingSpec returns the machine-readable pricing specification for the
+// requested resource type: required and optional attributes with their
+// accepted alias keys, the billing unit, and the pricing formula. The spec is
+// built from the static resource catalog and never calls the Azure API.
+// Returns InvalidArgument when no resource is given or its currency tag is
+// unsupported, and Unimplemented for non-Azure providers or unsupported
+// resource types.
 func (c *Calculator) GetPricingSpec(
 	ctx context.Context,
-	_ *finfocusv1.GetPricingSpecRequest,
+	req *finfocusv1.GetPricingSpecRequest,
 ) (*finfocusv1.GetPricingSpecResponse, error) {
 	log := logging.RequestLogger(ctx, c.logger)
 	log.Info().Msg("handling GetPricingSpec request")
 
-	return nil, status.Error(codes.Unimplemented, "not yet implemented")
+	desc := req.GetResource()
+	if desc == nil {
+		return nil, MapToGRPCStatus(
+			fmt.Errorf("%w: resource descriptor is required", ErrMissingRequiredFields),
+		).Err()
+	}
+	if !strings.EqualFold(desc.GetProvider(), "azure") {
+		return nil, MapToGRPCStatus(
+			fmt.Errorf("unsupported provider: %s: %w", desc.GetProvider(), ErrUnsupportedResourceType),
+		).Err()
+	}
+
+	key, ok := lookupResourceTypeKey(desc.GetResourceType())
+	if !ok {
+		err := MapToGRPCStatus(
+			fmt.Errorf("unsupported resource type: %s: %w", desc.GetResourceType(), ErrUnsupportedResourceType),
+		).Err()
+		log.Debug().
+			Str("resource_type", desc.GetResourceType()).
+			Err(err).
+			Msg("GetPricingSpec resource type not supported")
+		return nil, err
+	}
+
+	spec, err := buildPricingSpec(key, desc)
+	if err != nil {
+		return nil, MapToGRPCStatus(err).Err()
+	}
+	return &finfocusv1.GetPricingSpecResponse{Spec: spec}, nil
 }
 
-// DryRun is a stub that returns Unimplemented status.
-// Field mapping is not yet implemented.
+// DryRun previews how a resource descriptor would be priced without calling
+// the Azure Retail Prices API. The descriptor's region, SKU, and tags are
+// converted to EstimateCost attributes (simulation parameters override them)
+// and run through the same routing and attribute extraction as EstimateCost.
+// Validation problems are reported as configuration errors, and the resolved
+// OData filter, cache key, and cache state of every lookup are reported on
+// the extended_columns field mapping.
 func (c *Calculator) DryRun(
 	ctx context.Context,
-	_ *finfocusv1.DryRunRequest,
+	req *finfocusv1.DryRunRequest,
 ) (*finfocusv1.DryRunResponse, error) {
 	log := logging.RequestLogger(ctx, c.logger)
 	log.Info().Msg("handling DryRun request")
 
-	return nil, status.Error(codes.Unimplemented, "not yet implemented")
+	desc := req.GetResource()
+	if desc == nil {
+		return nil, status.Error(codes.InvalidArgument, "resource descriptor is required")
+	}
+	if !strings.EqualFold(desc.GetProvider(), "azure") {
+		return unsupportedDryRunResponse(), nil
+	}
+
+	estimateReq, err := estimateRequestFromDescriptor(desc, req.GetSimulationParameters())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	preview := c.PreviewEstimate(estimateReq)
+	for _, query := range preview.Queries {
+		log.Debug().
+			Str("resource_type", desc.GetResourceType()).
+			Str("filter", query.Filter).
+			Str("cache_key", query.CacheKey).
+			Bool("cached", query.Cached).
+			Msg("DryRun resolved pricing query")
+	}
+	log.Info().
+		Str("resource_type", desc.GetResourceType()).
+		Bool("resource_type_supported", preview.Supported).
+		Int("query_count", len(preview.Queries)).
+		Int("problem_count", len(preview.Problems)).
+		Msg("DryRun completed")
+
+	return dryRunResponse(preview), nil
 }
 
 // estimateQueryFromRequest extracts an Azure pricing query from EstimateCost
@@ -476,20 +785,63 @@ func (c *Calculator) DryRun(
 // Returns an error in the format "missing required field(s): ..." when
 // required fields are missing.
 func estimateQueryFromRequest(req *finfocusv1.EstimateCostRequest) (azureclient.PriceQuery, error) {
-	attributes := map[string]any{}
-	if req != nil && req.GetAttributes() != nil {
-		attributes = req.GetAttributes().AsMap()
+	query, missingFields := vmQueryFromAttributes(estimateAttributes(req))
+	if len(missingFields) > 0 {
+		return azureclient.PriceQuery{}, fmt.Errorf(
+			"missing required field(s): %s",
+			strings.Join(missingFields, ", "),
+		)
+	}
+	if err := normalizeQuery(&query); err != nil {
+		return azureclient.PriceQuery{}, err
 	}
 
-	query := azureclient.PriceQuery{
-		ArmRegionName: firstNonEmptyMapValue(attributes, "location", "region"),
-		ArmSkuName:    firstNonEmptyMapValue(attributes, "vmSize", "sku", "armSkuName"),
-		ServiceName:   firstNonEmptyMapValue(attributes, "serviceName"),
-		ProductName:   firstNonEmptyMapValue(attributes, "productName"),
-		CurrencyCode:  firstNonEmptyMapValue(attributes, "currencyCode", "currency"),
+	return query, nil
+}
+
+// estimateVMFromRequest extracts and validates the VM pricing query, pricing
+// model, OS, Azure Hybrid Benefit, and reservation comparison flags from an
+// EstimateCostRequest.
+func estimateVMFromRequest(req *finfocusv1.EstimateCostRequest) (vmPricing, error) {
+	query, err := estimateQueryFromRequest(req)
+	if err != nil {
+		return vmPricing{}, err
 	}
-	if query.CurrencyCode == "" {
-		query.CurrencyCode = defaultCurrency
+	attributes := estimateAttributes(req)
+	model, err := pricingModelFromAttributes(attributes)
+	if err != nil {
+		return vmPricing{}, err
+	}
+	osName, hybridBenefit, err := vmOSFromAttributes(attributes)
+	if err != nil {
+		return vmPricing{}, err
+	}
+	includeReservations, err := includeReservationsFromAttributes(attributes, model)
+	if err != nil {
+		return vmPricing{}, err
+	}
+	if includeReservations {
+		// Only this API version returns the meter's savings plan prices.
+		query.APIVersion = azureclient.SavingsPlanAPIVersion
+	}
+	return vmPricing{
+		Query:               query,
+		Model:               model,
+		OS:                  osName,
+		HybridBenefit:       hybridBenefit,
+		IncludeReservations: includeReservations,
+	}, nil
+}
+
+// vmQueryFromAttributes builds a VM pricing query from EstimateCost
+// attributes and returns the names of any missing required fields.
+func vmQueryFromAttributes(attributes map[string]any) (azureclient.PriceQuery, []string) {
+	query := azureclient.PriceQuery{
+		ArmRegionName: firstNonEmptyMapValue(attributes, regionAttributeKeys...),
+		ArmSkuName:    firstNonEmptyMapValue(attributes, vmSizeAttributeKeys...),
+		ServiceName:   firstNonEmptyMapValue(attributes, serviceNameAttributeKeys...),
+		ProductName:   firstNonEmptyMapValue(attributes, productNameAttributeKeys...),
+		CurrencyCode:  firstNonEmptyMapValue(attributes, currencyAttributeKeys...),
 	}
 	if query.ServiceName == "" {
 		query.ServiceName = defaultServiceName
@@ -501,19 +853,25 @@ func estimateQueryFromRequest(req *finfocusv1.EstimateCostRequest) (azureclient.
 	if query.ArmSkuName == "" {
 		missingFields = append(missingFields, "sku")
 	}
-	if len(missingFields) > 0 {
-		return azureclient.PriceQuery{}, fmt.Errorf(
-			"missing required field(s): %s",
-			strings.Join(missingFields, ", "),
-		)
-	}
 
-	return query, nil
+	return query, missingFields
 }
 
-func actualQueryFromRequest(req *finfocusv1.GetActualCostRequest) (azureclient.PriceQuery, bool) {
+// estimateAttributes returns the request attributes as a map, or an empty map
+// when the request or its attributes are nil.
+func estimateAttributes(req *finfocusv1.EstimateCostRequest) map[string]any {
+	if req == nil || req.GetAttributes() == nil {
+		return map[string]any{}
+	}
+	return req.GetAttributes().AsMap()
+}
+
+// actualQueryFromRequest builds the pricing query of a GetActualCost request
+// from its tags. ok is false when the region or SKU is missing; an unknown
+// region is an error.
+func actualQueryFromRequest(req *finfocusv1.GetActualCostRequest) (azureclient.PriceQuery, bool, error) {
 	if req == nil {
-		return azureclient.PriceQuery{}, false
+		return azureclient.PriceQuery{}, false, nil
 	}
 
 	tags := req.GetTags()
@@ -522,66 +880,76 @@ func actualQueryFromRequest(req *finfocusv1.GetActualCostRequest) (azureclient.P
 		ArmSkuName:    firstNonEmptyTag(tags, "sku", "vmSize", "armSkuName"),
 		ServiceName:   firstNonEmptyTag(tags, "service", "serviceName"),
 		ProductName:   firstNonEmptyTag(tags, "product", "productName"),
-		CurrencyCode:  firstNonEmptyTag(tags, "currency", "currencyCode"),
-	}
-	if query.CurrencyCode == "" {
-		query.CurrencyCode = defaultCurrency
+		CurrencyCode:  firstNonEmptyTag(tags, currencyAttributeKeys...),
 	}
 	if query.ServiceName == "" {
 		query.ServiceName = defaultServiceName
 	}
 	if query.ArmRegionName == "" || query.ArmSkuName == "" {
-		return azureclient.PriceQuery{}, false
+		return azureclient.PriceQuery{}, false, nil
 	}
 
-	return query, true
+	if err := normalizeQuery(&query); err != nil {
+		return azureclient.PriceQuery{}, false, err
+	}
+
+	return query, true, nil
 }
 
-func projectedQueryFromRequest(req *finfocusv1.GetProjectedCostRequest) (azureclient.PriceQuery, bool) {
+// projectedQueryFromRequest builds the pricing query of a GetProjectedCost
+// request from its resource. ok is false for non-Azure resources and when
+// the region or SKU is missing; an unknown region is an error.
+func projectedQueryFromRequest(req *finfocusv1.GetProjectedCostRequest) (azureclient.PriceQuery, bool, error) {
 	if req == nil || req.GetResource() == nil {
-		return azureclient.PriceQuery{}, false
+		return azureclient.PriceQuery{}, false, nil
 	}
 	resource := req.GetResource()
 	if !strings.EqualFold(resource.GetProvider(), "azure") {
-		return azureclient.PriceQuery{}, false
+		return azureclient.PriceQuery{}, false, nil
 	}
 
 	query := azureclient.PriceQuery{
 		ArmRegionName: resource.GetRegion(),
 		ArmSkuName:    resource.GetSku(),
-		CurrencyCode:  firstNonEmptyTag(resource.GetTags(), "currency", "currencyCode"),
+		CurrencyCode:  firstNonEmptyTag(resource.GetTags(), currencyAttributeKeys...),
 		ServiceName:   firstNonEmptyTag(resource.GetTags(), "service", "serviceName"),
 		ProductName:   firstNonEmptyTag(resource.GetTags(), "product", "productName"),
 	}
-	if query.CurrencyCode == "" {
-		query.CurrencyCode = defaultCurrency
-	}
 	if query.ServiceName == "" {
 		query.ServiceName = defaultServiceName
 	}
 	if query.ArmRegionName == "" || query.ArmSkuName == "" {
-		return azureclient.PriceQuery{}, false
+		return azureclient.PriceQuery{}, false, nil
 	}
 
-	return query, true
+	if err := normalizeQuery(&query); err != nil {
+		return azureclient.PriceQuery{}, false, err
+	}
+
+	return query, true, nil
 }
 
 // isVirtualMachineResourceType checks whether the lowercased resource type
 // refers to compute/virtualmachine as a full segment (not a prefix of e.g.
 // "compute/virtualmachinescaleset").
 func isVirtualMachineResourceType(lower string) bool {
-	const segment = "compute/virtualmachine"
-	idx := strings.Index(lower, segment)
-	if idx < 0 {
-		return false
-	}
-	end := idx + len(segment)
-	if end == len(lower) {
-		return true
-	}
-	// Next char must be a segment separator, not a continuation letter/digit.
-	next := lower[end]
-	return next == ':' || next == '/' || next == ' '
+	return hasResourceTypeSegment(lower, "compute/virtualmachine")
+}
+
+// normalizeQuery replaces the query region with its canonical armRegionName
+// and the currency with its ISO 4217 code (default USD). Returns an error
+// wrapping azureclient.ErrUnknownRegion or azureclient.ErrUnsupportedCurrency.
+func normalizeQuery(query *azureclient.PriceQuery) error {
+	region, err := azureclient.NormalizeRegion(query.ArmRegionName)
+	if err != nil {
+		return err
+	}
+	currency, err := azureclient.NormalizeCurrency(query.CurrencyCode)
+	if err != nil {
+		return err
+	}
+	query.ArmRegionName, query.CurrencyCode = region, currency
+	return nil
 }
 
 func firstNonEmptyTag(tags map[string]string, keys ...string) string {
@@ -605,20 +973,17 @@ func firstNonEmptyMapValue(values map[string]any, keys ...string) string {
 	return ""
 }
 
-func unitPriceAndCurrency(items []azureclient.PriceItem) (float64, string, error) {
-	if len(items) == 0 {
-		return 0, "", azureclient.ErrNotFound
-	}
-
-	item := items[0]
-	price := item.RetailPrice
-	if price == 0 {
-		price = item.UnitPrice
+// unitPriceAndCurrency selects the meter matching criteria and returns its
+// hourly price, normalized from the meter's unit of measure, and currency.
+// Meters without a unit of measure are assumed to be priced per hour.
+func unitPriceAndCurrency(items []azureclient.PriceItem, criteria meterCriteria) (float64, string, error) {
+	item, err := selectMeter(items, criteria)
+	if err != nil {
+		return 0, "", err
 	}
-	currency := item.CurrencyCode
-	if strings.TrimSpace(currency) == "" {
-		currency = defaultCurrency
+	price, err := newTimePrice(item, periodHour)
+	if err != nil {
+		return 0, "", err
 	}
-
-	return price, currency, nil
+	return price.Hourly, price.Currency, nil
 }
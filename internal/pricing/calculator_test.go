@@ -554,6 +554,25 @@ func TestEstimateQueryFromRequest_MissingBoth_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestEstimateQueryFromRequest_NormalizesRegion(t *testing.T) {
+	t.Parallel()
+
+	for _, region := range []string{"East US", "east-us", "EASTUS", "US East"} {
+		req := newEstimateCostRequest(t, "", map[string]any{
+			"location": region,
+			"vmSize":   "Standard_B1s",
+		})
+
+		query, err := estimateQueryFromRequest(req)
+		if err != nil {
+			t.Fatalf("estimateQueryFromRequest(%q) returned error: %v", region, err)
+		}
+		if query.ArmRegionName != "eastus" {
+			t.Errorf("estimateQueryFromRequest(%q) region = %q, want eastus", region, query.ArmRegionName)
+		}
+	}
+}
+
 func TestEstimateQueryFromRequest_AliasHandling(t *testing.T) {
 	t.Parallel()
 
@@ -736,19 +755,6 @@ func TestEstimateCost_UnsupportedResourceType_ReturnsUnimplemented(t *testing.T)
 	assertStatusCodeContains(t, err, codes.Unimplemented, "unsupported resource type")
 }
 
-func TestEstimateCost_VirtualMachineScaleSet_ReturnsUnimplemented(t *testing.T) {
-	t.Parallel()
-
-	calc := NewCalculator(zerolog.Nop())
-	req := newEstimateCostRequest(t, "azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet", map[string]any{
-		"location": "eastus",
-		"vmSize":   "Standard_B1s",
-	})
-
-	_, err := calc.EstimateCost(context.Background(), req)
-	assertStatusCodeContains(t, err, codes.Unimplemented, "unsupported resource type")
-}
-
 func TestEstimateCost_EmptyResourceType_Succeeds(t *testing.T) {
 	t.Parallel()
 
@@ -793,6 +799,33 @@ func TestEstimateCost_MissingRegion_ReturnsInvalidArgument(t *testing.T) {
 	assertStatusCodeContains(t, err, codes.InvalidArgument, "region")
 }
 
+func TestEstimateCost_UnknownRegion_ReturnsInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]map[string]any{
+		"azure:compute/virtualMachine:VirtualMachine": {"location": "eastsu", "vmSize": "Standard_B1s"},
+		"azure:storage/managedDisk:ManagedDisk":       {"location": "eastsu", "disk_type": "Premium_LRS", "size_gb": 128},
+		"azure:web/appServicePlan:AppServicePlan":     {"location": "eastsu", "sku": "P1v3"},
+		"azure:storage/blobStorage:BlobStorage":       {"location": "eastsu", "capacity_gb": 100, "redundancy": "LRS"},
+	}
+
+	calc := NewCalculator(zerolog.Nop())
+	for resourceType, attributes := range tests {
+		_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, resourceType, attributes))
+		assertStatusCodeContains(t, err, codes.InvalidArgument, `did you mean "eastus"?`)
+	}
+}
+
+func TestGetProjectedCost_UnknownRegion_ReturnsInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	_, err := calc.GetProjectedCost(context.Background(), &finfocusv1.GetProjectedCostRequest{
+		Resource: &finfocusv1.ResourceDescriptor{Provider: "azure", Region: "Atlantis North", Sku: "Standard_B1s"},
+	})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "unknown region")
+}
+
 func TestEstimateCost_MissingSKU_ReturnsInvalidArgument(t *testing.T) {
 	t.Parallel()
 
@@ -834,7 +867,7 @@ func TestEstimateCost_NotFoundSKU_ReturnsNotFound(t *testing.T) {
 	assertStatusCodeContains(t, err, codes.NotFound)
 }
 
-func TestEstimateCost_MultipleItems_UsesFirstItem(t *testing.T) {
+func TestEstimateCost_MultipleItems_AmbiguousMeter(t *testing.T) {
 	t.Parallel()
 
 	server := newPriceServer(t, []azureclient.PriceItem{
@@ -842,6 +875,7 @@ func TestEstimateCost_MultipleItems_UsesFirstItem(t *testing.T) {
 			ArmRegionName: "eastus",
 			ArmSkuName:    "Standard_B1s",
 			ServiceName:   "Virtual Machines",
+			MeterID:       "meter-a",
 			CurrencyCode:  "USD",
 			RetailPrice:   0.0200,
 		},
@@ -849,6 +883,7 @@ func TestEstimateCost_MultipleItems_UsesFirstItem(t *testing.T) {
 			ArmRegionName: "eastus",
 			ArmSkuName:    "Standard_B1s",
 			ServiceName:   "Virtual Machines",
+			MeterID:       "meter-b",
 			CurrencyCode:  "USD",
 			RetailPrice:   0.9999,
 		},
@@ -856,6 +891,7 @@ func TestEstimateCost_MultipleItems_UsesFirstItem(t *testing.T) {
 			ArmRegionName: "eastus",
 			ArmSkuName:    "Standard_B1s",
 			ServiceName:   "Virtual Machines",
+			MeterID:       "meter-c",
 			CurrencyCode:  "USD",
 			RetailPrice:   1.2345,
 		},
@@ -871,15 +907,9 @@ func TestEstimateCost_MultipleItems_UsesFirstItem(t *testing.T) {
 		"vmSize":   "Standard_B1s",
 	})
 
-	resp, err := calc.EstimateCost(context.Background(), req)
-	if err != nil {
-		t.Fatalf("EstimateCost() failed: %v", err)
-	}
-
-	want := 0.0200 * 730.0
-	if math.Abs(resp.GetCostMonthly()-want) > 0.000001 {
-		t.Fatalf("expected first-item monthly cost %.6f, got %.6f", want, resp.GetCostMonthly())
-	}
+	_, err := calc.EstimateCost(context.Background(), req)
+	assertStatusCodeContains(t, err, codes.FailedPrecondition,
+		"3 standard Linux meters match", "meter-a", "meter-b", "meter-c", "product_name")
 }
 
 func TestEstimateCost_RepeatedQuery_UsesCacheOnSecondCall(t *testing.T) {
@@ -1012,50 +1042,6 @@ func TestGetProjectedCostReturnsUnimplemented(t *testing.T) {
 	}
 }
 
-// TestGetPricingSpecReturnsUnimplemented verifies GetPricingSpec returns Unimplemented status.
-func TestGetPricingSpecReturnsUnimplemented(t *testing.T) {
-	t.Parallel()
-
-	logger := zerolog.Nop()
-	calc := NewCalculator(logger)
-
-	_, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{})
-	if err == nil {
-		t.Fatal("expected Unimplemented error, got nil")
-	}
-
-	st, ok := status.FromError(err)
-	if !ok {
-		t.Fatalf("expected gRPC status error, got: %v", err)
-	}
-
-	if st.Code() != codes.Unimplemented {
-		t.Errorf("expected Unimplemented code, got: %v", st.Code())
-	}
-}
-
-// TestDryRunReturnsUnimplemented verifies DryRun returns Unimplemented status.
-func TestDryRunReturnsUnimplemented(t *testing.T) {
-	t.Parallel()
-
-	logger := zerolog.Nop()
-	calc := NewCalculator(logger)
-
-	_, err := calc.DryRun(context.Background(), &finfocusv1.DryRunRequest{})
-	if err == nil {
-		t.Fatal("expected Unimplemented error, got nil")
-	}
-
-	st, ok := status.FromError(err)
-	if !ok {
-		t.Fatalf("expected gRPC status error, got: %v", err)
-	}
-
-	if st.Code() != codes.Unimplemented {
-		t.Errorf("expected Unimplemented code, got: %v", st.Code())
-	}
-}
-
 func TestGetProjectedCostSetsExpiresAtFromCache(t *testing.T) {
 	t.Parallel()
 
@@ -1732,7 +1718,7 @@ func TestUnitPriceAndCurrencyFallbacks(t *testing.T) {
 		{
 			UnitPrice: 0.031,
 		},
-	})
+	}, meterCriteria{Model: pricingModelStandard})
 	if err != nil {
 		t.Fatalf("unitPriceAndCurrency() failed: %v", err)
 	}
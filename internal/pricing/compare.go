@@ -0,0 +1,274 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+// RegionComparisonRequest selects the SKU whose regional prices are compared.
+type RegionComparisonRequest struct {
+	// ServiceName is the Azure service (default "Virtual Machines").
+	ServiceName string
+	// ArmSkuName is the SKU to price (e.g., "Standard_D2s_v3"). Required.
+	ArmSkuName string
+	// Region is the reference region the differences are relative to. Any
+	// form azureclient.LookupRegion accepts. Required.
+	Region string
+	// CurrencyCode is the ISO 4217 currency of the prices (default "USD");
+	// see azureclient.SupportedCurrencies.
+	CurrencyCode string
+	// OS selects Linux or Windows meters for Virtual Machines (default
+	// Linux). Ignored for other services.
+	OS string
+}
+
+// RegionComparison is a SKU's standard price in every region that offers it.
+type RegionComparison struct {
+	ServiceName string
+	ArmSkuName  string
+	// Region is the canonical armRegionName of the reference region.
+	Region   string
+	Currency string
+	// Prices are sorted cheapest first, ties by region name.
+	Prices []RegionPrice
+}
+
+// RegionPrice is a SKU's price in one region.
+type RegionPrice struct {
+	// Region is the armRegionName (e.g., "eastus").
+	Region string
+	// Location is the Retail Prices location (e.g., "US East").
+	Location string
+	// UnitPrice is the published price of one UnitOfMeasure pack.
+	UnitPrice     float64
+	UnitOfMeasure string
+	// NormalizedPrice is the price of one NormalizedUnit: "1 hour" for
+	// time-based meters such as "100 Hours", otherwise one unit of the
+	// meter's dimension (e.g., "1 gb/month"). Regions are ranked and
+	// compared by it; regions whose NormalizedUnit differs from the
+	// reference region's are left out.
+	NormalizedPrice float64
+	NormalizedUnit  string
+	// DifferencePercent is how much more (positive) or less (negative) the
+	// region costs than the reference region.
+	DifferencePercent float64
+}
+
+// CompareRegions prices req's SKU in every region with a single region-less
+// query and reports each region's difference from the reference region.
+// Prices are compared per hour (or per unit of usage meters), so regions
+// that publish a different pack size rank correctly. Regions whose meters
+// are ambiguous are left out. Errors are gRPC status errors: InvalidArgument
+// for a missing SKU or an unknown region, NotFound when the SKU has no price
+// in the reference region, and mapped codes for Azure API failures.
+func (c *Calculator) CompareRegions(ctx context.Context, req RegionComparisonRequest) (*RegionComparison, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	query, criteria, err := regionComparisonQuery(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("region", req.Region).
+			Str("sku", req.ArmSkuName).
+			Str("result_status", "error").
+			Err(err).
+			Msg("CompareRegions validation failed")
+		return nil, err
+	}
+
+	if c.cachedClient == nil {
+		err = status.Error(codes.Unimplemented, "not yet implemented")
+		log.Warn().
+			Str("sku", query.ArmSkuName).
+			Str("result_status", "error").
+			Err(err).
+			Msg("CompareRegions unavailable")
+		return nil, err
+	}
+
+	result, err := c.cachedClient.GetAllRegionPrices(ctx, query)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("service", query.ServiceName).
+			Str("sku", query.ArmSkuName).
+			Str("result_status", "error").
+			Err(err).
+			Msg("CompareRegions pricing lookup failed")
+		return nil, err
+	}
+
+	prices := regionPrices(result.Items, criteria, func(region string, err error) {
+		log.Debug().Str("region", region).Err(err).Msg("CompareRegions skipped region")
+	})
+
+	reference := -1
+	for i, price := range prices {
+		if price.Region == query.ArmRegionName {
+			reference = i
+			break
+		}
+	}
+	if reference < 0 {
+		err = status.Errorf(codes.NotFound,
+			"no %s price for %s in region %s", criteria, query.ArmSkuName, query.ArmRegionName)
+		log.Warn().
+			Str("region", query.ArmRegionName).
+			Str("sku", query.ArmSkuName).
+			Int("region_count", len(prices)).
+			Str("result_status", "error").
+			Err(err).
+			Msg("CompareRegions reference region not priced")
+		return nil, err
+	}
+
+	referencePrice := prices[reference].NormalizedPrice
+	referenceUnit := prices[reference].NormalizedUnit
+	sameUnit := prices[:0]
+	for _, price := range prices {
+		if price.NormalizedUnit != referenceUnit {
+			log.Debug().
+				Str("region", price.Region).
+				Str("unit_of_measure", price.UnitOfMeasure).
+				Msg("CompareRegions skipped region with a different unit of measure")
+			continue
+		}
+		if referencePrice != 0 {
+			price.DifferencePercent = (price.NormalizedPrice - referencePrice) / referencePrice * 100
+		}
+		sameUnit = append(sameUnit, price)
+	}
+	prices = sameUnit
+	sort.SliceStable(prices, func(i, j int) bool {
+		if prices[i].NormalizedPrice != prices[j].NormalizedPrice {
+			return prices[i].NormalizedPrice < prices[j].NormalizedPrice
+		}
+		return prices[i].Region < prices[j].Region
+	})
+
+	log.Info().
+		Str("region", query.ArmRegionName).
+		Str("service", query.ServiceName).
+		Str("sku", query.ArmSkuName).
+		Int("region_count", len(prices)).
+		Str("cheapest_region", prices[0].Region).
+		Str("result_status", "success").
+		Msg("CompareRegions completed")
+
+	return &RegionComparison{
+		ServiceName: query.ServiceName,
+		ArmSkuName:  query.ArmSkuName,
+		Region:      query.ArmRegionName,
+		Currency:    query.CurrencyCode,
+		Prices:      prices,
+	}, nil
+}
+
+// regionComparisonQuery validates req and resolves its defaults. The query
+// keeps the normalized reference region; the all-region fetch ignores it.
+func regionComparisonQuery(req RegionComparisonRequest) (azureclient.PriceQuery, meterCriteria, error) {
+	query := azureclient.PriceQuery{
+		ServiceName:   strings.TrimSpace(req.ServiceName),
+		ArmSkuName:    strings.TrimSpace(req.ArmSkuName),
+		ArmRegionName: strings.TrimSpace(req.Region),
+		CurrencyCode:  req.CurrencyCode,
+	}
+	if query.ServiceName == "" {
+		query.ServiceName = defaultServiceName
+	}
+
+	var missingFields []string
+	if query.ArmSkuName == "" {
+		missingFields = append(missingFields, "sku")
+	}
+	if query.ArmRegionName == "" {
+		missingFields = append(missingFields, "region")
+	}
+	if len(missingFields) > 0 {
+		err := fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+		return azureclient.PriceQuery{}, meterCriteria{}, err
+	}
+	if err := normalizeQuery(&query); err != nil {
+		return azureclient.PriceQuery{}, meterCriteria{}, err
+	}
+
+	criteria := meterCriteria{Model: pricingModelStandard, ArmSkuName: query.ArmSkuName}
+	if strings.EqualFold(query.ServiceName, defaultServiceName) {
+		osName, _, err := vmOSFromAttributes(map[string]any{"os": req.OS})
+		if err != nil {
+			return azureclient.PriceQuery{}, meterCriteria{}, err
+		}
+		criteria.OS = osName
+	}
+	return query, criteria, nil
+}
+
+// regionPrices selects the meter matching criteria in each region of items
+// and normalizes its price by the meter's unit of measure. Regions without a
+// single matching price or with an unparsable unit are reported to skip and
+// left out.
+func regionPrices(
+	items []azureclient.PriceItem,
+	criteria meterCriteria,
+	skip func(region string, err error),
+) []RegionPrice {
+	byRegion := make(map[string][]azureclient.PriceItem)
+	var regions []string
+	for _, item := range items {
+		region := strings.ToLower(strings.TrimSpace(item.ArmRegionName))
+		if region == "" {
+			continue
+		}
+		if _, ok := byRegion[region]; !ok {
+			regions = append(regions, region)
+		}
+		byRegion[region] = append(byRegion[region], item)
+	}
+
+	prices := make([]RegionPrice, 0, len(regions))
+	for _, region := range regions {
+		item, err := selectMeter(byRegion[region], criteria)
+		if err != nil {
+			skip(region, err)
+			continue
+		}
+		normalizedPrice, normalizedUnit, err := normalizedRegionPrice(item)
+		if err != nil {
+			skip(region, err)
+			continue
+		}
+		price, _ := itemPriceAndCurrency(item)
+		prices = append(prices, RegionPrice{
+			Region:          region,
+			Location:        item.Location,
+			UnitPrice:       price,
+			UnitOfMeasure:   item.UnitOfMeasure,
+			NormalizedPrice: normalizedPrice,
+			NormalizedUnit:  normalizedUnit.String(),
+		})
+	}
+	return prices
+}
+
+// normalizedRegionPrice returns the price of one hour of a time-based meter,
+// or of one unit of a usage meter, with the unit it is the price of. Meters
+// without a unit of measure are assumed to be hourly, like VM meters.
+func normalizedRegionPrice(item azureclient.PriceItem) (float64, unitOfMeasure, error) {
+	unit, err := meterUnit(item, periodHour)
+	if err != nil {
+		return 0, unitOfMeasure{}, err
+	}
+	price, _ := itemPriceAndCurrency(item)
+	if packHours, hoursErr := unit.packHours(); hoursErr == nil {
+		return price / packHours, unitOfMeasure{Multiplier: 1, Period: periodHour}, nil
+	}
+	return price / unit.Multiplier, unitOfMeasure{Dimension: unit.Dimension, Multiplier: 1, Period: unit.Period}, nil
+}
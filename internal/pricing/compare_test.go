@@ -0,0 +1,243 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func regionComparisonItems() []azureclient.PriceItem {
+	vm := func(region, location, product string, price float64) azureclient.PriceItem {
+		return azureclient.PriceItem{
+			ArmRegionName: region, Location: location, ArmSkuName: "Standard_D2s_v3",
+			ProductName: product, ServiceName: "Virtual Machines", CurrencyCode: "USD",
+			UnitOfMeasure: "1 Hour", RetailPrice: price, IsPrimaryMeterRegion: true,
+		}
+	}
+	spot := vm("centralindia", "IN Central", "Virtual Machines DSv3 Series", 0.01)
+	spot.MeterName = "D2s v3 Spot"
+	return []azureclient.PriceItem{
+		vm("eastus", "US East", "Virtual Machines DSv3 Series", 0.096),
+		vm("eastus", "US East", "Virtual Machines DSv3 Series Windows", 0.188),
+		vm("westeurope", "EU West", "Virtual Machines DSv3 Series", 0.12),
+		vm("centralindia", "IN Central", "Virtual Machines DSv3 Series", 0.072),
+		spot,
+		vm("westus2", "US West 2", "Virtual Machines DSv3 Series", 0.096),
+	}
+}
+
+func TestCompareRegions(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, regionComparisonItems(), nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	got, err := calc.CompareRegions(context.Background(), RegionComparisonRequest{
+		ArmSkuName: "Standard_D2s_v3",
+		Region:     "East US",
+	})
+	if err != nil {
+		t.Fatalf("CompareRegions() error = %v", err)
+	}
+
+	if got.Region != "eastus" || got.ServiceName != "Virtual Machines" || got.Currency != "USD" {
+		t.Errorf("comparison = %+v, want normalized reference region and defaults", got)
+	}
+	want := []struct {
+		region  string
+		price   float64
+		percent float64
+	}{
+		{"centralindia", 0.072, -25},
+		{"eastus", 0.096, 0},
+		{"westus2", 0.096, 0},
+		{"westeurope", 0.12, 25},
+	}
+	if len(got.Prices) != len(want) {
+		t.Fatalf("got %d prices, want %d: %+v", len(got.Prices), len(want), got.Prices)
+	}
+	for i, w := range want {
+		p := got.Prices[i]
+		if p.Region != w.region || p.UnitPrice != w.price || math.Abs(p.DifferencePercent-w.percent) > 1e-9 {
+			t.Errorf("Prices[%d] = %+v, want region %s price %g diff %g%%", i, p, w.region, w.price, w.percent)
+		}
+	}
+	if got.Prices[0].Location != "IN Central" || got.Prices[0].UnitOfMeasure != "1 Hour" {
+		t.Errorf("Prices[0] = %+v, want location and unit of measure", got.Prices[0])
+	}
+}
+
+func TestCompareRegions_NormalizesPackSizes(t *testing.T) {
+	t.Parallel()
+
+	items := regionComparisonItems()
+	// 10 instance-hours for 0.9 is 0.09 an hour: cheaper than eastus, dearer
+	// than centralindia, though its published price is the highest.
+	items[2].UnitOfMeasure, items[2].RetailPrice = "10 Hours", 0.9
+	items[5].UnitOfMeasure, items[5].RetailPrice = "1 GB/Month", 0.001
+	server := newPriceServer(t, items, nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	got, err := calc.CompareRegions(context.Background(), RegionComparisonRequest{
+		ArmSkuName: "Standard_D2s_v3",
+		Region:     "eastus",
+	})
+	if err != nil {
+		t.Fatalf("CompareRegions() error = %v", err)
+	}
+
+	// westus2 prices per GB, so it cannot be compared and is left out.
+	want := []struct {
+		region  string
+		hourly  float64
+		percent float64
+	}{
+		{"centralindia", 0.072, -25},
+		{"westeurope", 0.09, -6.25},
+		{"eastus", 0.096, 0},
+	}
+	if len(got.Prices) != len(want) {
+		t.Fatalf("got %d prices, want %d: %+v", len(got.Prices), len(want), got.Prices)
+	}
+	for i, w := range want {
+		p := got.Prices[i]
+		if p.Region != w.region || math.Abs(p.NormalizedPrice-w.hourly) > 1e-9 || p.NormalizedUnit != "1 hour" ||
+			math.Abs(p.DifferencePercent-w.percent) > 1e-9 {
+			t.Errorf("Prices[%d] = %+v, want region %s at %g/hour diff %g%%", i, p, w.region, w.hourly, w.percent)
+		}
+	}
+	if got.Prices[1].UnitPrice != 0.9 || got.Prices[1].UnitOfMeasure != "10 Hours" {
+		t.Errorf("Prices[1] = %+v, want the published 10 Hours price", got.Prices[1])
+	}
+}
+
+func TestCompareRegions_Windows(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, regionComparisonItems(), nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	got, err := calc.CompareRegions(context.Background(), RegionComparisonRequest{
+		ArmSkuName: "Standard_D2s_v3",
+		Region:     "eastus",
+		OS:         "windows",
+	})
+	if err != nil {
+		t.Fatalf("CompareRegions() error = %v", err)
+	}
+	if len(got.Prices) != 1 || got.Prices[0].UnitPrice != 0.188 {
+		t.Errorf("Prices = %+v, want only the Windows meter", got.Prices)
+	}
+}
+
+func TestCompareRegions_SingleRegionlessQuery(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	var filter atomic.Value
+	inner := newPriceServer(t, regionComparisonItems(), &requests)
+	defer inner.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter.Store(r.URL.Query().Get("$filter"))
+		http.Redirect(w, r, inner.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	req := RegionComparisonRequest{ArmSkuName: "Standard_D2s_v3", Region: "westeurope"}
+	for range 2 {
+		if _, err := calc.CompareRegions(context.Background(), req); err != nil {
+			t.Fatalf("CompareRegions() error = %v", err)
+		}
+	}
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("upstream requests = %d, want 1", got)
+	}
+	got, _ := filter.Load().(string)
+	if strings.Contains(got, "armRegionName") || !strings.Contains(got, "armSkuName eq 'Standard_D2s_v3'") ||
+		!strings.Contains(got, "serviceName eq 'Virtual Machines'") {
+		t.Errorf("filter = %q, want SKU and service without region", got)
+	}
+}
+
+func TestCompareRegions_Errors(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, regionComparisonItems(), nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	tests := []struct {
+		name     string
+		calc     *Calculator
+		req      RegionComparisonRequest
+		wantCode codes.Code
+		wantMsg  []string
+	}{
+		{
+			name:     "missing_fields",
+			calc:     NewCalculator(zerolog.Nop(), cachedClient),
+			req:      RegionComparisonRequest{},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{"missing required field(s): sku, region"},
+		},
+		{
+			name:     "unknown_region",
+			calc:     NewCalculator(zerolog.Nop(), cachedClient),
+			req:      RegionComparisonRequest{ArmSkuName: "Standard_D2s_v3", Region: "eastuss"},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{"unknown region", "eastus"},
+		},
+		{
+			name:     "unsupported_os",
+			calc:     NewCalculator(zerolog.Nop(), cachedClient),
+			req:      RegionComparisonRequest{ArmSkuName: "Standard_D2s_v3", Region: "eastus", OS: "bsd"},
+			wantCode: codes.InvalidArgument,
+			wantMsg:  []string{"unsupported os"},
+		},
+		{
+			name:     "reference_region_not_priced",
+			calc:     NewCalculator(zerolog.Nop(), cachedClient),
+			req:      RegionComparisonRequest{ArmSkuName: "Standard_D2s_v3", Region: "japaneast"},
+			wantCode: codes.NotFound,
+			wantMsg:  []string{"japaneast"},
+		},
+		{
+			name:     "no_client",
+			calc:     NewCalculator(zerolog.Nop()),
+			req:      RegionComparisonRequest{ArmSkuName: "Standard_D2s_v3", Region: "eastus"},
+			wantCode: codes.Unimplemented,
+			wantMsg:  []string{"not yet implemented"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.calc.CompareRegions(context.Background(), tt.req)
+			assertStatusCodeContains(t, err, tt.wantCode, tt.wantMsg...)
+		})
+	}
+}
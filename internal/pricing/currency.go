@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// currencyFromAttributes resolves the requested currency: an ISO 4217 code
+// the Retail Prices API supports, defaulting to USD.
+func currencyFromAttributes(attributes map[string]any) (string, error) {
+	return azureclient.NormalizeCurrency(firstNonEmptyMapValue(attributes, currencyAttributeKeys...))
+}
+
+// currencyFromTags resolves the requested currency from resource tags.
+func currencyFromTags(tags map[string]string) (string, error) {
+	return azureclient.NormalizeCurrency(firstNonEmptyTag(tags, currencyAttributeKeys...))
+}
+
+// includeUSDFromAttributes resolves the flag requesting USD amounts next to
+// the requested currency. It has no effect when the currency is USD.
+func includeUSDFromAttributes(attributes map[string]any) (bool, error) {
+	value := firstNonEmptyMapValue(attributes, includeUSDAttributeKeys...)
+	if value == "" {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("include_usd must be true or false: %s", value)
+	}
+	return include, nil
+}
+
+// includeUSDFromTags resolves the include_usd flag from resource tags.
+func includeUSDFromTags(tags map[string]string) (bool, error) {
+	attributes := make(map[string]any, len(includeUSDAttributeKeys))
+	for _, key := range includeUSDAttributeKeys {
+		if value, ok := tags[key]; ok {
+			attributes[key] = value
+		}
+	}
+	return includeUSDFromAttributes(attributes)
+}
+
+// usdQuery returns the USD variant of query.
+func usdQuery(query azureclient.PriceQuery) azureclient.PriceQuery {
+	query.CurrencyCode = defaultCurrency
+	return query
+}
+
+// usdEstimateRequest returns a copy of req whose attributes request USD
+// amounts only.
+func usdEstimateRequest(
+	req *finfocusv1.EstimateCostRequest,
+	attributes map[string]any,
+) (*finfocusv1.EstimateCostRequest, error) {
+	usdAttributes := maps.Clone(attributes)
+	for _, key := range currencyAttributeKeys {
+		delete(usdAttributes, key)
+	}
+	for _, key := range includeUSDAttributeKeys {
+		delete(usdAttributes, key)
+	}
+	usdAttributes[currencyAttributeKeys[0]] = defaultCurrency
+
+	fields, err := structpb.NewStruct(usdAttributes)
+	if err != nil {
+		return nil, fmt.Errorf("building USD estimate attributes: %w", err)
+	}
+	usdReq, _ := proto.Clone(req).(*finfocusv1.EstimateCostRequest)
+	usdReq.Attributes = fields
+	return usdReq, nil
+}
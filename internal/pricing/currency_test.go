@@ -0,0 +1,239 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// eurRate converts the test server's USD prices to EUR.
+const eurRate = 0.9
+
+// newCurrencyPriceServer serves a Standard_B1s meter priced at usdPrice,
+// converted to EUR when the request asks for currencyCode='EUR'.
+func newCurrencyPriceServer(t *testing.T, usdPrice float64) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		item := azureclient.PriceItem{
+			ArmRegionName: "westeurope",
+			ArmSkuName:    "Standard_B1s",
+			ServiceName:   "Virtual Machines",
+			UnitOfMeasure: "1 Hour",
+			CurrencyCode:  "USD",
+			RetailPrice:   usdPrice,
+		}
+		if strings.Trim(r.URL.Query().Get("currencyCode"), "'") == "EUR" {
+			item.CurrencyCode, item.RetailPrice = "EUR", usdPrice*eurRate
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		resp := azureclient.PriceResponse{Items: []azureclient.PriceItem{item}, Count: 1}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+}
+
+func TestEstimate_Currency(t *testing.T) {
+	t.Parallel()
+
+	server := newCurrencyPriceServer(t, 0.02)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name         string
+		attributes   map[string]any
+		wantCurrency string
+		wantMonthly  float64
+		wantUSD      bool
+	}{
+		{name: "default_usd", wantCurrency: "USD", wantMonthly: 0.02 * 730},
+		{name: "eur", attributes: map[string]any{"currency": "eur"}, wantCurrency: "EUR", wantMonthly: 0.02 * eurRate * 730},
+		{
+			name:         "eur_with_usd",
+			attributes:   map[string]any{"currencyCode": "EUR", "include_usd": true},
+			wantCurrency: "EUR", wantMonthly: 0.02 * eurRate * 730, wantUSD: true,
+		},
+		{
+			name:         "usd_ignores_include_usd",
+			attributes:   map[string]any{"currency": "USD", "includeUsd": "true"},
+			wantCurrency: "USD", wantMonthly: 0.02 * 730,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := map[string]any{"location": "westeurope", "vmSize": "Standard_B1s"}
+			for key, value := range tt.attributes {
+				attributes[key] = value
+			}
+
+			estimate, err := calc.Estimate(context.Background(),
+				newEstimateCostRequest(t, "azure:compute/virtualMachine:VirtualMachine", attributes))
+			if err != nil {
+				t.Fatalf("Estimate() error = %v", err)
+			}
+			if estimate.Currency != tt.wantCurrency || math.Abs(estimate.CostMonthly-tt.wantMonthly) > 1e-9 {
+				t.Errorf("estimate = %s %v, want %s %v",
+					estimate.Currency, estimate.CostMonthly, tt.wantCurrency, tt.wantMonthly)
+			}
+			if !tt.wantUSD {
+				if estimate.USD != nil {
+					t.Errorf("USD = %+v, want nil", estimate.USD)
+				}
+				return
+			}
+			if estimate.USD == nil {
+				t.Fatal("USD = nil, want the USD estimate")
+			}
+			if estimate.USD.Currency != "USD" || math.Abs(estimate.USD.CostMonthly-0.02*730) > 1e-9 {
+				t.Errorf("USD estimate = %s %v, want USD %v", estimate.USD.Currency, estimate.USD.CostMonthly, 0.02*730)
+			}
+		})
+	}
+}
+
+func TestGetActualCost_FocusRecordCurrencies(t *testing.T) {
+	t.Parallel()
+
+	server := newCurrencyPriceServer(t, 0.02)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	resp, err := calc.GetActualCost(context.Background(), &finfocusv1.GetActualCostRequest{
+		Tags: map[string]string{
+			"region": "westeurope", "sku": "Standard_B1s", "currency": "EUR", "include_usd": "true",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetActualCost() error = %v", err)
+	}
+	record := resp.GetResults()[0].GetFocusRecord()
+	if record.GetBillingCurrency() != "EUR" || math.Abs(record.GetListUnitPrice()-0.02*eurRate) > 1e-12 {
+		t.Errorf("billing = %s %v, want EUR %v", record.GetBillingCurrency(), record.GetListUnitPrice(), 0.02*eurRate)
+	}
+	if record.GetPricingCurrency() != "USD" || record.GetPricingCurrencyListUnitPrice() != 0.02 {
+		t.Errorf("pricing = %s %v, want USD 0.02",
+			record.GetPricingCurrency(), record.GetPricingCurrencyListUnitPrice())
+	}
+}
+
+func TestUnsupportedCurrency_ReturnsInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	ctx := context.Background()
+	tags := map[string]string{"region": "eastus", "sku": "Standard_B1s", "currency": "XYZ"}
+
+	estimateTypes := []string{
+		"azure:compute/virtualMachine:VirtualMachine",
+		"azure:compute/managedDisk:ManagedDisk",
+		"azure:storage/blobContainer:BlobContainer",
+		"azure:containerservice/managedCluster:ManagedCluster",
+	}
+	for _, resourceType := range estimateTypes {
+		_, err := calc.EstimateCost(ctx, newEstimateCostRequest(t, resourceType, map[string]any{
+			"location": "eastus", "vmSize": "Standard_B1s", "currency": "XYZ",
+		}))
+		assertStatusCodeContains(t, err, codes.InvalidArgument, "unsupported currency")
+	}
+
+	_, err := calc.EstimateCost(ctx, newEstimateCostRequest(t, "", map[string]any{
+		"location": "eastus", "vmSize": "Standard_B1s", "include_usd": "maybe",
+	}))
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "include_usd")
+
+	_, err = calc.GetActualCost(ctx, &finfocusv1.GetActualCostRequest{Tags: tags})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "unsupported currency")
+
+	desc := &finfocusv1.ResourceDescriptor{
+		Provider: "azure", ResourceType: "compute/VirtualMachine", Region: "eastus", Sku: "Standard_B1s", Tags: tags,
+	}
+	_, err = calc.GetProjectedCost(ctx, &finfocusv1.GetProjectedCostRequest{Resource: desc})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "unsupported currency")
+
+	_, err = calc.GetPricingSpec(ctx, &finfocusv1.GetPricingSpecRequest{Resource: desc})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "unsupported currency")
+
+	_, err = calc.CompareRegions(ctx, RegionComparisonRequest{
+		ArmSkuName: "Standard_B1s", Region: "eastus", CurrencyCode: "XYZ",
+	})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "unsupported currency")
+}
+
+func TestEstimateCost_SendsUSDDetail(t *testing.T) {
+	t.Parallel()
+
+	server := newCurrencyPriceServer(t, 0.02)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	detail := estimateDetailFromCall(t, calc, map[string]any{
+		"region": "westeurope", "sku": "Standard_B1s", "currency": "EUR", "include_usd": "true",
+	}, "compute/VirtualMachine")
+	if detail.Currency != "EUR" || math.Abs(detail.CostMonthly-0.02*eurRate*730) > 1e-9 {
+		t.Errorf("detail = %s %v, want EUR %v", detail.Currency, detail.CostMonthly, 0.02*eurRate*730)
+	}
+	if detail.USD == nil {
+		t.Fatal("USD = nil, want the USD detail")
+	}
+	if detail.USD.Currency != "USD" || math.Abs(detail.USD.CostMonthly-0.02*730) > 1e-9 || detail.USD.USD != nil {
+		t.Errorf("USD detail = %+v, want USD %v", detail.USD, 0.02*730)
+	}
+}
+
+func TestPreviewEstimate_ListsUSDQueries(t *testing.T) {
+	t.Parallel()
+
+	server := newCurrencyPriceServer(t, 0.02)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	req := newEstimateCostRequest(t, "compute/VirtualMachine", map[string]any{
+		"region": "westeurope", "sku": "Standard_B1s", "currency": "EUR", "include_usd": "true",
+	})
+	preview := calc.PreviewEstimate(req)
+	if len(preview.Queries) != 2 || len(preview.Problems) != 0 {
+		t.Fatalf("expected the EUR and USD queries, got %+v", preview)
+	}
+	if preview.Queries[0].Query.CurrencyCode != "EUR" || preview.Queries[1].Query.CurrencyCode != "USD" {
+		t.Errorf("query currencies = %s, %s, want EUR, USD",
+			preview.Queries[0].Query.CurrencyCode, preview.Queries[1].Query.CurrencyCode)
+	}
+
+	if _, err := calc.EstimateCost(context.Background(), req); err != nil {
+		t.Fatalf("EstimateCost() error = %v", err)
+	}
+	for _, query := range calc.PreviewEstimate(req).Queries {
+		if !query.Cached {
+			t.Errorf("query %s was previewed but not fetched by EstimateCost", query.CacheKey)
+		}
+	}
+
+	preview = calc.PreviewEstimate(newEstimateCostRequest(t, "compute/VirtualMachine", map[string]any{
+		"region": "westeurope", "sku": "Standard_B1s", "include_usd": "maybe",
+	}))
+	if len(preview.Problems) != 1 || !strings.Contains(preview.Problems[0], "include_usd") {
+		t.Errorf("Problems = %v, want the include_usd error", preview.Problems)
+	}
+}
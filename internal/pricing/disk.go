@@ -5,6 +5,8 @@ import (
 	"math"
 	"strings"
 
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+
 	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
 )
 
@@ -85,23 +87,16 @@ func tierForSize(prefix string, sizeGB float64) (string, error) {
 // storage/manageddisk as a segment (case-insensitive), consistent with the
 // isVirtualMachineResourceType pattern.
 func isManagedDiskResourceType(lower string) bool {
-	const segment = "storage/manageddisk"
-	idx := strings.Index(lower, segment)
-	if idx < 0 {
-		return false
-	}
-	end := idx + len(segment)
-	if end == len(lower) {
-		return true
-	}
-	next := lower[end]
-	return next == ':' || next == '/' || next == ' '
+	return hasResourceTypeSegment(lower, "storage/manageddisk")
 }
 
-// selectDiskTierPrice filters Azure price items by the target tier's meter name
-// and returns the retail price and currency. For ZRS disk types, the meter name
-// includes a " ZRS" suffix (e.g., "P10 ZRS").
-func selectDiskTierPrice(items []azureclient.PriceItem, tierName string, redundancy string) (float64, string, error) {
+// findDiskTierMeter returns the price item of a disk tier meter. For ZRS disk
+// types, the meter name includes a " ZRS" suffix (e.g., "P10 ZRS").
+func findDiskTierMeter(
+	items []azureclient.PriceItem,
+	tierName string,
+	redundancy string,
+) (azureclient.PriceItem, error) {
 	meterName := tierName
 	if redundancy == "ZRS" {
 		meterName = tierName + " ZRS"
@@ -109,17 +104,43 @@ func selectDiskTierPrice(items []azureclient.PriceItem, tierName string, redunda
 
 	for _, item := range items {
 		if item.MeterName == meterName {
-			price := item.RetailPrice
-			if price == 0 {
-				price = item.UnitPrice
-			}
-			currency := item.CurrencyCode
-			if strings.TrimSpace(currency) == "" {
-				currency = defaultCurrency
-			}
-			return price, currency, nil
+			return item, nil
 		}
 	}
 
-	return 0, "", fmt.Errorf("no pricing found for disk tier %s: %w", meterName, azureclient.ErrNotFound)
+	return azureclient.PriceItem{}, fmt.Errorf("no pricing found for disk tier %s: %w",
+		meterName, azureclient.ErrNotFound)
+}
+
+// diskTierPrice is the monthly price of a provisioned disk tier.
+type diskTierPrice struct {
+	Monthly  float64
+	Currency string
+	Tier     string
+	// PackHours is the hours the meter's published price covers (730 for
+	// "1/Month").
+	PackHours float64
+	Meter     azureclient.PriceItem
+}
+
+// newDiskTierPrice normalizes a disk tier meter to its monthly price. Meters
+// without a unit of measure are assumed to be priced per month.
+func newDiskTierPrice(meter azureclient.PriceItem, tierName string) (diskTierPrice, error) {
+	price, err := newTimePrice(meter, periodMonth)
+	if err != nil {
+		return diskTierPrice{}, err
+	}
+	return diskTierPrice{
+		Monthly:   price.Monthly(),
+		Currency:  price.Currency,
+		Tier:      tierName,
+		PackHours: price.PackHours,
+		Meter:     meter,
+	}, nil
+}
+
+// lineItem returns the charge for count disks of the tier for a month.
+func (p diskTierPrice) lineItem(count float64) LineItem {
+	packMonths := p.PackHours / pluginsdk.HoursPerMonth
+	return newLineItem("disk "+p.Tier, p.Meter, p.Monthly*packMonths, count/packMonths)
 }
@@ -192,7 +192,7 @@ func TestIsManagedDiskResourceType(t *testing.T) {
 	}
 }
 
-func TestSelectDiskTierPrice(t *testing.T) {
+func TestFindDiskTierMeter(t *testing.T) {
 	t.Parallel()
 
 	items := []azureclient.PriceItem{
@@ -218,7 +218,7 @@ func TestSelectDiskTierPrice(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			price, _, err := selectDiskTierPrice(items, tc.tierName, tc.redundancy)
+			meter, err := findDiskTierMeter(items, tc.tierName, tc.redundancy)
 			if tc.wantErr {
 				if err == nil {
 					t.Fatalf("expected error, got nil")
@@ -228,8 +228,12 @@ func TestSelectDiskTierPrice(t *testing.T) {
 			if err != nil {
 				t.Fatalf("unexpected error: %v", err)
 			}
-			if price != tc.wantPrice {
-				t.Errorf("price = %.2f, want %.2f", price, tc.wantPrice)
+			price, err := newDiskTierPrice(meter, tc.tierName)
+			if err != nil {
+				t.Fatalf("newDiskTierPrice() error = %v", err)
+			}
+			if price.Monthly != tc.wantPrice {
+				t.Errorf("price = %.2f, want %.2f", price.Monthly, tc.wantPrice)
 			}
 		})
 	}
@@ -0,0 +1,301 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// QueryPreview describes a single Azure Retail Prices lookup that an
+// EstimateCost request would perform.
+type QueryPreview struct {
+	// Query is the resolved pricing query.
+	Query azureclient.PriceQuery
+	// Filter is the exact OData $filter expression sent to the API.
+	Filter string
+	// CacheKey is the key the result is cached under.
+	CacheKey string
+	// Cached reports whether a fresh result is already cached.
+	Cached bool
+}
+
+// EstimatePreview is the outcome of planning an EstimateCost request without
+// calling the Azure Retail Prices API.
+type EstimatePreview struct {
+	// ResourceType is the canonical resource type of the selected pricing
+	// path (e.g., "compute/VirtualMachine"), or empty when unsupported.
+	ResourceType string
+	// Supported reports whether EstimateCost routes the resource type.
+	Supported bool
+	// Queries are the pricing lookups EstimateCost would perform.
+	Queries []QueryPreview
+	// Problems lists validation errors that would make EstimateCost fail.
+	Problems []string
+}
+
+// dryRunSupportedFields are the FOCUS fields populated for priced resources.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var dryRunSupportedFields = []string{
+	"billing_currency",
+	"list_cost",
+	"list_unit_price",
+	"pricing_category",
+	"pricing_unit",
+	"region_id",
+	"service_name",
+	"sku_id",
+}
+
+// PreviewEstimate runs the EstimateCost routing, attribute extraction, and
+// filter building for req and reports the result without calling Azure.
+func (c *Calculator) PreviewEstimate(req *finfocusv1.EstimateCostRequest) *EstimatePreview {
+	resourceType := strings.TrimSpace(req.GetResourceType())
+	route := routeEstimate(resourceType)
+	if route == routeUnsupported {
+		return &EstimatePreview{
+			Problems: []string{fmt.Sprintf("unsupported resource type: %s", resourceType)},
+		}
+	}
+
+	preview := &EstimatePreview{
+		ResourceType: route.canonicalResourceType(),
+		Supported:    true,
+	}
+	queries, err := planEstimateQueries(route, req)
+	if err == nil {
+		queries, err = withUSDQueries(route, req, queries)
+	}
+	if err != nil {
+		preview.Problems = append(preview.Problems, err.Error())
+	}
+	for _, query := range queries {
+		preview.Queries = append(preview.Queries, QueryPreview{
+			Query:    query,
+			Filter:   c.previewFilter(query),
+			CacheKey: azureclient.CacheKey(query),
+			Cached:   c.cachedClient != nil && c.cachedClient.Cached(query),
+		})
+	}
+	return preview
+}
+
+// previewFilter returns the $filter EstimateCost would send for query. With a
+// cached client it is planned the way a cache miss fetches, so superset
+// services report their region+service filter.
+func (c *Calculator) previewFilter(query azureclient.PriceQuery) string {
+	if c.cachedClient == nil {
+		return azureclient.FilterQuery(query)
+	}
+	return c.cachedClient.Filter(query)
+}
+
+// canonicalResourceType returns the display form of the route's resource type.
+func (r estimateRoute) canonicalResourceType() string {
+	switch r {
+	case routeVirtualMachine:
+		return canonicalResourceTypes["compute/virtualmachine"]
+	case routeVirtualMachineScaleSet:
+		return canonicalResourceTypes["compute/virtualmachinescaleset"]
+	case routeManagedDisk:
+		return canonicalResourceTypes["storage/manageddisk"]
+	case routeManagedCluster:
+		return canonicalResourceTypes["containerservice/managedcluster"]
+	case routeAppServicePlan:
+		return canonicalResourceTypes["web/appserviceplan"]
+	case routeFunctionApp:
+		return canonicalResourceTypes["web/functionapp"]
+	case routeStorage:
+		return canonicalResourceTypes["storage/blobstorage"]
+	case routeUnsupported:
+	}
+	return ""
+}
+
+// planEstimateQueries resolves the pricing queries EstimateCost would issue
+// for req using the same attribute extraction as the estimate paths. Queries
+// that could be resolved are returned alongside any validation error so a
+// preview can show both.
+func planEstimateQueries(route estimateRoute, req *finfocusv1.EstimateCostRequest) ([]azureclient.PriceQuery, error) {
+	switch route {
+	case routeVirtualMachine:
+		vm, err := estimateVMFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		if vm.IncludeReservations {
+			return []azureclient.PriceQuery{vm.Query, reservationQuery(vm.Query)}, nil
+		}
+		return []azureclient.PriceQuery{vm.Query}, nil
+	case routeVirtualMachineScaleSet:
+		vm, _, err := estimateScaleSetFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return []azureclient.PriceQuery{vm.Query}, nil
+	case routeManagedDisk:
+		query, diskInfo, sizeGB, err := estimateDiskQueryFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		if _, tierErr := tierForSize(diskInfo.TierPrefix, sizeGB); tierErr != nil {
+			return []azureclient.PriceQuery{query}, tierErr
+		}
+		return []azureclient.PriceQuery{query}, nil
+	case routeManagedCluster:
+		cluster, err := estimateClusterFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return clusterQueries(cluster), nil
+	case routeAppServicePlan:
+		plan, err := estimateAppServicePlanFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return []azureclient.PriceQuery{plan.Query}, nil
+	case routeFunctionApp:
+		usage, err := estimateFunctionUsageFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return []azureclient.PriceQuery{usage.Query}, nil
+	case routeStorage:
+		storage, err := estimateStorageFromRequest(req)
+		if err != nil {
+			return nil, err
+		}
+		return []azureclient.PriceQuery{storage.Query}, nil
+	case routeUnsupported:
+	}
+	return nil, fmt.Errorf("unsupported resource type: %s", req.GetResourceType())
+}
+
+// withUSDQueries appends the lookups of the USD estimate that Estimate
+// repeats when include_usd is true and the requested currency is not USD.
+func withUSDQueries(
+	route estimateRoute,
+	req *finfocusv1.EstimateCostRequest,
+	queries []azureclient.PriceQuery,
+) ([]azureclient.PriceQuery, error) {
+	attributes := estimateAttributes(req)
+	includeUSD, err := includeUSDFromAttributes(attributes)
+	if err != nil {
+		return queries, err
+	}
+	currency, err := currencyFromAttributes(attributes)
+	if err != nil || !includeUSD || currency == defaultCurrency {
+		return queries, err
+	}
+
+	usdReq, err := usdEstimateRequest(req, attributes)
+	if err != nil {
+		return queries, err
+	}
+	usdQueries, err := planEstimateQueries(route, usdReq)
+	return append(queries, usdQueries...), err
+}
+
+// estimateRequestFromDescriptor converts a ResourceDescriptor into the
+// EstimateCostRequest EstimateCost would receive for it. Tags become
+// attributes, the primary region and SKU fields replace any tag aliases, and
+// overrides (e.g., DryRun simulation parameters) take precedence over both.
+func estimateRequestFromDescriptor(
+	desc *finfocusv1.ResourceDescriptor,
+	overrides map[string]string,
+) (*finfocusv1.EstimateCostRequest, error) {
+	attributes := make(map[string]any, len(desc.GetTags())+len(overrides)+2)
+	for key, value := range desc.GetTags() {
+		attributes[key] = value
+	}
+	if region := strings.TrimSpace(desc.GetRegion()); region != "" {
+		deleteKeys(attributes, regionAttributeKeys)
+		attributes["region"] = region
+	}
+	if sku := strings.TrimSpace(desc.GetSku()); sku != "" {
+		deleteKeys(attributes, vmSizeAttributeKeys)
+		deleteKeys(attributes, diskTypeAttributeKeys)
+		attributes["sku"] = sku
+	}
+	for key, value := range overrides {
+		attributes[key] = value
+	}
+
+	attrs, err := structpb.NewStruct(attributes)
+	if err != nil {
+		return nil, fmt.Errorf("converting descriptor attributes: %w", err)
+	}
+	return &finfocusv1.EstimateCostRequest{
+		ResourceType: desc.GetResourceType(),
+		Attributes:   attrs,
+	}, nil
+}
+
+func deleteKeys(values map[string]any, keys []string) {
+	for _, key := range keys {
+		delete(values, key)
+	}
+}
+
+// dryRunResponse renders an EstimatePreview as a DryRunResponse.
+func dryRunResponse(preview *EstimatePreview) *finfocusv1.DryRunResponse {
+	if !preview.Supported {
+		return unsupportedDryRunResponse()
+	}
+
+	mappings := pluginsdk.AllFieldsWithStatus(finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_UNSUPPORTED)
+	for _, field := range dryRunSupportedFields {
+		mappings = pluginsdk.SetFieldStatus(mappings, field,
+			finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_SUPPORTED)
+	}
+	if len(preview.Queries) > 0 {
+		query := preview.Queries[0].Query
+		mappings = pluginsdk.SetFieldStatus(mappings, "region_id",
+			finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_SUPPORTED,
+			pluginsdk.WithConditionDescription("armRegionName: "+query.ArmRegionName))
+		mappings = pluginsdk.SetFieldStatus(mappings, "service_name",
+			finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_SUPPORTED,
+			pluginsdk.WithConditionDescription("serviceName: "+query.ServiceName))
+		mappings = pluginsdk.SetFieldStatus(mappings, "billing_currency",
+			finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_SUPPORTED,
+			pluginsdk.WithConditionDescription("currencyCode: "+query.CurrencyCode))
+		mappings = pluginsdk.SetFieldStatus(mappings, "extended_columns",
+			finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_DYNAMIC,
+			pluginsdk.WithConditionDescription(describeQueryPreviews(preview.Queries)),
+			pluginsdk.WithExpectedType("map"))
+	}
+
+	return pluginsdk.NewDryRunResponse(
+		pluginsdk.WithFieldMappings(mappings),
+		pluginsdk.WithResourceTypeSupported(true),
+		pluginsdk.WithConfigurationValid(len(preview.Problems) == 0),
+		pluginsdk.WithConfigurationErrors(preview.Problems),
+	)
+}
+
+// unsupportedDryRunResponse reports a resource that this plugin cannot price.
+func unsupportedDryRunResponse() *finfocusv1.DryRunResponse {
+	return pluginsdk.NewDryRunResponse(
+		pluginsdk.WithFieldMappings(
+			pluginsdk.AllFieldsWithStatus(finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_UNSUPPORTED),
+		),
+		pluginsdk.WithResourceTypeSupported(false),
+		pluginsdk.WithConfigurationValid(true),
+	)
+}
+
+// describeQueryPreviews formats query previews as "filter=...; cache_key=...;
+// cached=..." entries separated by " | ".
+func describeQueryPreviews(previews []QueryPreview) string {
+	parts := make([]string, 0, len(previews))
+	for _, preview := range previews {
+		parts = append(parts, fmt.Sprintf("filter=%s; cache_key=%s; cached=%t",
+			preview.Filter, preview.CacheKey, preview.Cached))
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,261 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestDryRun_VirtualMachine_ReportsFilterAndCacheState(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD", RetailPrice: 0.0104},
+	}, &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	dryRunReq := &finfocusv1.DryRunRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "azure:compute/virtualMachine:VirtualMachine",
+			Sku:          "Standard_B1s",
+			Region:       "eastus",
+		},
+	}
+
+	resp, err := calc.DryRun(context.Background(), dryRunReq)
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	if !resp.GetResourceTypeSupported() || !resp.GetConfigurationValid() {
+		t.Fatalf("expected supported and valid, got %+v", resp)
+	}
+	if got := calls.Load(); got != 0 {
+		t.Fatalf("DryRun must not call Azure, got %d upstream calls", got)
+	}
+
+	extended := findFieldMapping(t, resp, "extended_columns").GetConditionDescription()
+	wantFilter := "filter=armRegionName eq 'eastus' and armSkuName eq 'Standard_B1s' and " +
+		"currencyCode eq 'USD' and priceType eq 'Consumption' and serviceName eq 'Virtual Machines'"
+	if !strings.Contains(extended, wantFilter) {
+		t.Errorf("extended_columns = %q, want filter %q", extended, wantFilter)
+	}
+	if !strings.Contains(extended, "cache_key=eastus|standard_b1s||virtual machines|usd") {
+		t.Errorf("extended_columns = %q, want cache key", extended)
+	}
+	if !strings.Contains(extended, "cached=false") {
+		t.Errorf("extended_columns = %q, want cached=false before EstimateCost", extended)
+	}
+
+	_, err = calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+		"azure:compute/virtualMachine:VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_B1s"},
+	))
+	if err != nil {
+		t.Fatalf("EstimateCost() failed: %v", err)
+	}
+
+	resp, err = calc.DryRun(context.Background(), dryRunReq)
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	extended = findFieldMapping(t, resp, "extended_columns").GetConditionDescription()
+	if !strings.Contains(extended, "cached=true") {
+		t.Errorf("extended_columns = %q, want cached=true after EstimateCost", extended)
+	}
+}
+
+func TestDryRun_ValidationProblems(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.DryRun(context.Background(), &finfocusv1.DryRunRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "storage/ManagedDisk",
+			Sku:          "Premium_SSD_LRS",
+			Region:       "eastus",
+		},
+	})
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	if !resp.GetResourceTypeSupported() {
+		t.Fatal("expected managed disk to be supported")
+	}
+	if resp.GetConfigurationValid() {
+		t.Fatal("expected configuration_valid=false for missing size_gb")
+	}
+	if len(resp.GetConfigurationErrors()) != 1 ||
+		!strings.Contains(resp.GetConfigurationErrors()[0], "size_gb") {
+		t.Errorf("configuration_errors = %v, want missing size_gb", resp.GetConfigurationErrors())
+	}
+}
+
+func TestDryRun_SimulationParametersOverrideDescriptor(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.DryRun(context.Background(), &finfocusv1.DryRunRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "storage/ManagedDisk",
+			Sku:          "Premium_SSD_LRS",
+			Region:       "eastus",
+			Tags:         map[string]string{"size_gb": "128"},
+		},
+		SimulationParameters: map[string]string{"region": "westeurope"},
+	})
+	if err != nil {
+		t.Fatalf("DryRun() failed: %v", err)
+	}
+	if !resp.GetConfigurationValid() {
+		t.Fatalf("expected valid configuration, got errors %v", resp.GetConfigurationErrors())
+	}
+	region := findFieldMapping(t, resp, "region_id").GetConditionDescription()
+	if region != "armRegionName: westeurope" {
+		t.Errorf("region_id description = %q, want simulated region", region)
+	}
+	extended := findFieldMapping(t, resp, "extended_columns").GetConditionDescription()
+	if !strings.Contains(extended, "armSkuName eq 'Premium_LRS'") {
+		t.Errorf("extended_columns = %q, want normalized disk SKU", extended)
+	}
+}
+
+func TestDryRun_Unsupported(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resource *finfocusv1.ResourceDescriptor
+	}{
+		{
+			name:     "unsupported resource type",
+			resource: &finfocusv1.ResourceDescriptor{Provider: "azure", ResourceType: "network/LoadBalancer"},
+		},
+		{
+			name:     "non-azure provider",
+			resource: &finfocusv1.ResourceDescriptor{Provider: "aws", ResourceType: "compute/VirtualMachine"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			resp, err := calc.DryRun(context.Background(), &finfocusv1.DryRunRequest{Resource: tc.resource})
+			if err != nil {
+				t.Fatalf("DryRun() failed: %v", err)
+			}
+			if resp.GetResourceTypeSupported() {
+				t.Error("expected resource_type_supported=false")
+			}
+			for _, mapping := range resp.GetFieldMappings() {
+				if mapping.GetSupportStatus() != finfocusv1.FieldSupportStatus_FIELD_SUPPORT_STATUS_UNSUPPORTED {
+					t.Fatalf("field %q should be UNSUPPORTED, got %v", mapping.GetFieldName(), mapping.GetSupportStatus())
+				}
+			}
+		})
+	}
+}
+
+func TestDryRun_NilResource_ReturnsInvalidArgument(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	_, err := calc.DryRun(context.Background(), &finfocusv1.DryRunRequest{})
+	assertStatusCodeContains(t, err, codes.InvalidArgument, "resource")
+}
+
+func TestPreviewEstimate_MatchesEstimateRouting(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+
+	preview := calc.PreviewEstimate(newEstimateCostRequest(t, "", map[string]any{
+		"location": "eastus",
+		"vmSize":   "Standard_B1s",
+	}))
+	if preview.ResourceType != "compute/VirtualMachine" || len(preview.Queries) != 1 {
+		t.Fatalf("expected backward-compatible VM route, got %+v", preview)
+	}
+
+	preview = calc.PreviewEstimate(newEstimateCostRequest(t, "compute/VirtualMachine", map[string]any{
+		"location":            "eastus",
+		"vmSize":              "Standard_B1s",
+		"includeReservations": true,
+	}))
+	if len(preview.Queries) != 2 || !strings.Contains(preview.Queries[1].Filter, "priceType eq 'Reservation'") {
+		t.Fatalf("expected consumption and reservation queries, got %+v", preview.Queries)
+	}
+
+	preview = calc.PreviewEstimate(newEstimateCostRequest(t,
+		"azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet", nil))
+	if preview.ResourceType != "compute/VirtualMachineScaleSet" || len(preview.Problems) != 1 {
+		t.Fatalf("expected scale set route with a validation problem, got %+v", preview)
+	}
+
+	preview = calc.PreviewEstimate(newEstimateCostRequest(t, "network/LoadBalancer", nil))
+	if preview.Supported {
+		t.Fatalf("expected load balancer to be unsupported, got %+v", preview)
+	}
+}
+
+func findFieldMapping(t *testing.T, resp *finfocusv1.DryRunResponse, name string) *finfocusv1.FieldMapping {
+	t.Helper()
+
+	for _, mapping := range resp.GetFieldMappings() {
+		if mapping.GetFieldName() == name {
+			return mapping
+		}
+	}
+	t.Fatalf("field mapping %q not found", name)
+	return nil
+}
+
+func TestPreviewEstimate_SupersetServiceReportsSliceFilter(t *testing.T) {
+	t.Parallel()
+
+	client, err := azureclient.NewClient(azureclient.DefaultConfig())
+	if err != nil {
+		t.Fatalf("NewClient() failed: %v", err)
+	}
+	cacheConfig := azureclient.DefaultCacheConfig()
+	cacheConfig.SupersetServices = []string{"Managed Disks"}
+	cacheConfig.Logger = zerolog.Nop()
+	cachedClient, err := azureclient.NewCachedClient(client, cacheConfig)
+	if err != nil {
+		t.Fatalf("NewCachedClient() failed: %v", err)
+	}
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	preview := calc.PreviewEstimate(newEstimateCostRequest(t, "storage/ManagedDisk", map[string]any{
+		"region":  "eastus",
+		"sku":     "Premium_SSD_LRS",
+		"size_gb": 128,
+	}))
+	if len(preview.Queries) != 1 {
+		t.Fatalf("expected one disk query, got %+v", preview)
+	}
+	filter := preview.Queries[0].Filter
+	if strings.Contains(filter, "armSkuName") || strings.Contains(filter, "productName") {
+		t.Errorf("Filter = %q, want the region+service slice filter", filter)
+	}
+	if filter != cachedClient.Filter(preview.Queries[0].Query) {
+		t.Errorf("Filter = %q, want the cached client's planned filter", filter)
+	}
+}
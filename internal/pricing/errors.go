@@ -52,6 +52,14 @@ func MapToGRPCStatus(err error) *status.Status {
 		code = codes.Unimplemented
 	case errors.Is(err, ErrMissingRequiredFields):
 		code = codes.InvalidArgument
+	case errors.Is(err, azureclient.ErrUnknownRegion):
+		code = codes.InvalidArgument
+	case errors.Is(err, azureclient.ErrUnsupportedCurrency):
+		code = codes.InvalidArgument
+	case errors.Is(err, ErrAmbiguousMeter):
+		code = codes.FailedPrecondition
+	case errors.Is(err, ErrUnsupportedUnitOfMeasure):
+		code = codes.FailedPrecondition
 	}
 
 	return status.New(code, err.Error())
@@ -77,6 +77,26 @@ func TestMapToGRPCStatus(t *testing.T) {
 			err:          ErrMissingRequiredFields,
 			expectedCode: codes.InvalidArgument,
 		},
+		{
+			name:         "ErrUnknownRegion maps to InvalidArgument",
+			err:          azureclient.ErrUnknownRegion,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "ErrUnsupportedCurrency maps to InvalidArgument",
+			err:          azureclient.ErrUnsupportedCurrency,
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "ErrAmbiguousMeter maps to FailedPrecondition",
+			err:          ErrAmbiguousMeter,
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name:         "ErrUnsupportedUnitOfMeasure maps to FailedPrecondition",
+			err:          ErrUnsupportedUnitOfMeasure,
+			expectedCode: codes.FailedPrecondition,
+		},
 		{
 			name:         "unknown error maps to Internal",
 			err:          errors.New("unknown"),
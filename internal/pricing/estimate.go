@@ -0,0 +1,181 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// CostEstimate is the detailed result of an EstimateCost calculation.
+// EstimateCostResponse only carries the currency, expected monthly cost, and
+// pricing category; EstimateCost sends the remaining detail in the
+// EstimateDetailHeader response header, and in-process callers can use
+// Calculator.Estimate.
+type CostEstimate struct {
+	// ResourceType is the canonical resource type that was priced
+	// (e.g., "compute/VirtualMachine").
+	ResourceType string
+	// Currency is the ISO 4217 currency code of all amounts.
+	Currency string
+	// CostMonthly is the expected monthly cost reported by EstimateCost.
+	CostMonthly float64
+	// LicenseCostMonthly is the Windows license component included in
+	// CostMonthly. Zero for Linux and with Azure Hybrid Benefit.
+	LicenseCostMonthly float64
+	// Range is the low/expected/high monthly cost for resources whose cost
+	// varies with autoscaling. Nil for single-point estimates.
+	Range *CostRange
+	// PricingCategory is the FOCUS pricing category of the estimate.
+	PricingCategory finfocusv1.FocusPricingCategory
+	// LineItems are the Azure meter charges that add up to CostMonthly.
+	LineItems []LineItem
+	// Reservations compares reserved instance terms against CostMonthly,
+	// shortest term first. Only populated when reservation comparison is
+	// requested.
+	Reservations []ReservationEstimate
+	// SavingsPlans compares savings plan terms against CostMonthly, shortest
+	// term first. Populated alongside Reservations.
+	SavingsPlans []SavingsPlanEstimate
+	// USD is the same estimate in US dollars. Only populated when the
+	// include_usd attribute is set and Currency is not USD.
+	USD *CostEstimate
+}
+
+// CostRange is a low/expected/high monthly cost range.
+type CostRange struct {
+	Low      float64 `json:"low"`
+	Expected float64 `json:"expected"`
+	High     float64 `json:"high"`
+}
+
+// LineItem is one Azure meter charge behind an estimate. Quantity is in the
+// meter's UnitOfMeasure, so CostMonthly is UnitPrice * Quantity.
+type LineItem struct {
+	// Description names the charge (e.g., "compute", "license", "disk P10").
+	Description        string  `json:"description"`
+	MeterID            string  `json:"meterId,omitempty"`
+	MeterName          string  `json:"meterName,omitempty"`
+	UnitOfMeasure      string  `json:"unitOfMeasure,omitempty"`
+	UnitPrice          float64 `json:"unitPrice"`
+	Quantity           float64 `json:"quantity"`
+	CostMonthly        float64 `json:"costMonthly"`
+	EffectiveStartDate string  `json:"effectiveStartDate,omitempty"`
+}
+
+// newLineItem builds a line item for quantity units of a meter at unitPrice.
+// unitPrice is passed separately because it may be a component of the
+// meter's price (e.g., the license share of a Windows rate).
+func newLineItem(description string, meter azureclient.PriceItem, unitPrice, quantity float64) LineItem {
+	return LineItem{
+		Description:        description,
+		MeterID:            meter.MeterID,
+		MeterName:          meter.MeterName,
+		UnitOfMeasure:      meter.UnitOfMeasure,
+		UnitPrice:          unitPrice,
+		Quantity:           quantity,
+		CostMonthly:        unitPrice * quantity,
+		EffectiveStartDate: meter.EffectiveStartDate,
+	}
+}
+
+// lineItemsTotal sums the monthly cost of line items.
+func lineItemsTotal(lineItems []LineItem) float64 {
+	var total float64
+	for _, lineItem := range lineItems {
+		total += lineItem.CostMonthly
+	}
+	return total
+}
+
+// ReservationEstimate is the monthly cost of a reserved instance term.
+type ReservationEstimate struct {
+	// Term is the reservation term as published by Azure (e.g., "1 Year").
+	Term string `json:"term"`
+	// CostMonthly is the term price spread evenly over the term's months.
+	CostMonthly float64 `json:"costMonthly"`
+	// SavingsPercent is the saving against the pay-as-you-go CostMonthly.
+	SavingsPercent float64 `json:"savingsPercent"`
+}
+
+// SavingsPlanEstimate is the monthly cost of a compute savings plan term.
+type SavingsPlanEstimate struct {
+	// Term is the savings plan term as published by Azure (e.g., "3 Years").
+	Term string `json:"term"`
+	// CostMonthly is the hourly savings plan rate over a month.
+	CostMonthly float64 `json:"costMonthly"`
+	// SavingsPercent is the saving against the pay-as-you-go CostMonthly.
+	SavingsPercent float64 `json:"savingsPercent"`
+}
+
+// response renders the estimate as an EstimateCostResponse.
+func (e *CostEstimate) response() *finfocusv1.EstimateCostResponse {
+	return pluginsdk.NewEstimateCostResponse(
+		pluginsdk.WithEstimateCost(e.Currency, e.CostMonthly),
+		pluginsdk.WithPricingCategory(e.PricingCategory),
+	)
+}
+
+// EstimateDetailHeader is the gRPC response header of EstimateCost that
+// carries the JSON-encoded EstimateDetail of the estimate. Hosts read it with
+// the grpc.Header call option.
+const EstimateDetailHeader = "finfocus-estimate-detail-bin"
+
+// EstimateDetail is the JSON document of the EstimateDetailHeader header: the
+// parts of a CostEstimate that EstimateCostResponse has no fields for.
+type EstimateDetail struct {
+	ResourceType string  `json:"resourceType"`
+	Currency     string  `json:"currency"`
+	CostMonthly  float64 `json:"costMonthly"`
+	// LicenseCostMonthly is the Windows license component of CostMonthly.
+	LicenseCostMonthly float64 `json:"licenseCostMonthly,omitempty"`
+	// Range is the low/expected/high monthly cost of autoscaling resources.
+	Range *CostRange `json:"range,omitempty"`
+	// LineItems are the meter charges that add up to CostMonthly.
+	LineItems []LineItem `json:"lineItems,omitempty"`
+	// Reservations and SavingsPlans compare commitment terms against
+	// CostMonthly when the reservation comparison is requested.
+	Reservations []ReservationEstimate `json:"reservations,omitempty"`
+	SavingsPlans []SavingsPlanEstimate `json:"savingsPlans,omitempty"`
+	// USD is the detail of the same estimate in US dollars when include_usd
+	// is set and Currency is not USD.
+	USD *EstimateDetail `json:"usd,omitempty"`
+}
+
+// detail returns the EstimateDetail of the estimate.
+func (e *CostEstimate) detail() EstimateDetail {
+	detail := EstimateDetail{
+		ResourceType:       e.ResourceType,
+		Currency:           e.Currency,
+		CostMonthly:        e.CostMonthly,
+		LicenseCostMonthly: e.LicenseCostMonthly,
+		Range:              e.Range,
+		LineItems:          e.LineItems,
+		Reservations:       e.Reservations,
+		SavingsPlans:       e.SavingsPlans,
+	}
+	if e.USD != nil {
+		usd := e.USD.detail()
+		detail.USD = &usd
+	}
+	return detail
+}
+
+// sendEstimateDetail sets the EstimateDetailHeader response header of the
+// gRPC call in ctx. Outside a gRPC call (e.g., in-process use) it does
+// nothing.
+func sendEstimateDetail(ctx context.Context, estimate *CostEstimate) error {
+	if grpc.ServerTransportStreamFromContext(ctx) == nil {
+		return nil
+	}
+	data, err := json.Marshal(estimate.detail())
+	if err != nil {
+		return err
+	}
+	return grpc.SetHeader(ctx, metadata.Pairs(EstimateDetailHeader, string(data)))
+}
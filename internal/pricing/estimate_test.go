@@ -0,0 +1,143 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// headerStream records the response headers a handler sets, standing in for
+// the gRPC server transport stream.
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "/finfocus.v1.CostSourceService/EstimateCost" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *headerStream) SetTrailer(metadata.MD) error { return nil }
+
+// estimateDetailFromCall runs EstimateCost as a gRPC call and decodes the
+// EstimateDetailHeader it sends.
+func estimateDetailFromCall(
+	t *testing.T,
+	calc *Calculator,
+	attributes map[string]any,
+	resourceType string,
+) EstimateDetail {
+	t.Helper()
+
+	stream := &headerStream{}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+	if _, err := calc.EstimateCost(ctx, newEstimateCostRequest(t, resourceType, attributes)); err != nil {
+		t.Fatalf("EstimateCost() error = %v", err)
+	}
+
+	values := stream.header.Get(EstimateDetailHeader)
+	if len(values) != 1 {
+		t.Fatalf("got %d %s headers, want 1", len(values), EstimateDetailHeader)
+	}
+	var detail EstimateDetail
+	if err := json.Unmarshal([]byte(values[0]), &detail); err != nil {
+		t.Fatalf("decode %s: %v", EstimateDetailHeader, err)
+	}
+	return detail
+}
+
+func TestEstimateCost_SendsEstimateDetail(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_D2s_v3", CurrencyCode: "USD", RetailPrice: 0.096},
+	}, nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	detail := estimateDetailFromCall(t, calc, map[string]any{
+		"location": "eastus", "sku": "Standard_D2s_v3", "capacity": 3, "minCapacity": 2, "maxCapacity": 10,
+	}, "azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet")
+
+	instanceMonthly := 0.096 * 730
+	if detail.ResourceType != "compute/VirtualMachineScaleSet" || detail.Currency != "USD" ||
+		math.Abs(detail.CostMonthly-3*instanceMonthly) > 1e-9 {
+		t.Errorf("detail = %+v", detail)
+	}
+	if detail.Range == nil || math.Abs(detail.Range.Low-2*instanceMonthly) > 1e-9 ||
+		math.Abs(detail.Range.High-10*instanceMonthly) > 1e-9 {
+		t.Errorf("Range = %+v, want 2 to 10 instances", detail.Range)
+	}
+}
+
+func TestEstimateCost_WithoutGRPCStreamSendsNoDetail(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD", RetailPrice: 0.0104},
+	}, nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	req := newEstimateCostRequest(t, "", map[string]any{"location": "eastus", "vmSize": "Standard_B1s"})
+	if _, err := calc.EstimateCost(context.Background(), req); err != nil {
+		t.Fatalf("EstimateCost() error = %v", err)
+	}
+}
+
+func TestEstimateCost_SendsLicenseCost(t *testing.T) {
+	t.Parallel()
+
+	server := newSavingsPlanTestServer(t)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	detail := estimateDetailFromCall(t, calc,
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": "Windows"}, "compute/VirtualMachine")
+	if math.Abs(detail.CostMonthly-0.18*730) > 1e-9 || math.Abs(detail.LicenseCostMonthly-0.08*730) > 1e-9 {
+		t.Errorf("detail = %+v, want 0.18/hour with a 0.08/hour license", detail)
+	}
+}
+
+func TestEstimateCost_SendsLineItems(t *testing.T) {
+	t.Parallel()
+
+	server := newSavingsPlanTestServer(t)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	detail := estimateDetailFromCall(t, calc,
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": "Windows"}, "compute/VirtualMachine")
+	if len(detail.LineItems) != 2 {
+		t.Fatalf("LineItems = %+v, want compute and license", detail.LineItems)
+	}
+	for i, want := range []struct {
+		description string
+		unitPrice   float64
+	}{{"compute", 0.1}, {"license", 0.08}} {
+		got := detail.LineItems[i]
+		if got.Description != want.description || math.Abs(got.UnitPrice-want.unitPrice) > 1e-9 ||
+			got.Quantity != 730 || math.Abs(got.CostMonthly-want.unitPrice*730) > 1e-9 {
+			t.Errorf("LineItems[%d] = %+v, want %s at %v/hour", i, got, want.description, want.unitPrice)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+const (
+	// functionsServiceName is the Retail Prices serviceName of Azure Functions.
+	functionsServiceName = "Functions"
+	// functionsExecutionTimeMeter is billed per GB-second of execution time.
+	functionsExecutionTimeMeter = "Standard Execution Time"
+	// functionsExecutionsMeter is billed per block of executions.
+	functionsExecutionsMeter = "Standard Total Executions"
+)
+
+// Function App attribute aliases, in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	executionsAttributeKeys = []string{"executions", "monthlyExecutions", "executions_per_month"}
+	gbSecondsAttributeKeys  = []string{"gbSeconds", "gb_seconds", "executionTimeGbSeconds"}
+	planAttributeKeys       = []string{"plan", "planType", "plan_type"}
+)
+
+// consumptionPlanNames are the accepted (lowercased) names of the Functions
+// Consumption plan.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var consumptionPlanNames = map[string]bool{
+	"consumption": true,
+	"dynamic":     true,
+	"y1":          true,
+}
+
+// functionUsage is a validated Function App usage description.
+type functionUsage struct {
+	Query      azureclient.PriceQuery
+	Executions float64
+	GBSeconds  float64
+}
+
+// meterCharge is the graduated cost of one usage meter.
+type meterCharge struct {
+	Cost     float64
+	Currency string
+	// FreeUnits is the usage covered by the free grant, in the caller's
+	// quantity unit (e.g., executions or GB-seconds).
+	FreeUnits float64
+	// LineItems has one entry per tier the usage reaches, with quantities
+	// in billing units.
+	LineItems []LineItem
+}
+
+// estimateFunctionAppCost handles Consumption plan Function App estimation:
+// executions and GB-seconds are priced through their graduated meters, whose
+// zero-priced first tier is the monthly free grant.
+func (c *Calculator) estimateFunctionAppCost(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	usage, err := estimateFunctionUsageFromRequest(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost function app validation failed")
+		return nil, err
+	}
+
+	if c.cachedClient == nil {
+		unimplementedErr := status.Error(codes.Unimplemented, "not yet implemented")
+		log.Warn().
+			Str("region", usage.Query.ArmRegionName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(unimplementedErr).
+			Msg("EstimateCost unavailable")
+		return nil, unimplementedErr
+	}
+
+	result, err := c.cachedClient.GetPrices(ctx, usage.Query)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", usage.Query.ArmRegionName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost function app pricing lookup failed")
+		return nil, err
+	}
+
+	executions, execErr := graduatedMeterCharge(result.Items, functionsExecutionsMeter, usage.Executions)
+	duration, durationErr := graduatedMeterCharge(result.Items, functionsExecutionTimeMeter, usage.GBSeconds)
+	if err = errors.Join(execErr, durationErr); err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", usage.Query.ArmRegionName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost function app meter lookup failed")
+		return nil, err
+	}
+
+	costMonthly := executions.Cost + duration.Cost
+
+	log.Info().
+		Str("region", usage.Query.ArmRegionName).
+		Str("resource_type", resourceType).
+		Float64("executions", usage.Executions).
+		Float64("gb_seconds", usage.GBSeconds).
+		Float64("free_executions", executions.FreeUnits).
+		Float64("free_gb_seconds", duration.FreeUnits).
+		Float64("cost_monthly", costMonthly).
+		Str("currency", duration.Currency).
+		Str("result_status", "success").
+		Msg("EstimateCost function app completed")
+
+	lineItems := make([]LineItem, 0, len(executions.LineItems)+len(duration.LineItems))
+	lineItems = append(lineItems, executions.LineItems...)
+	lineItems = append(lineItems, duration.LineItems...)
+
+	return &CostEstimate{
+		ResourceType:    routeFunctionApp.canonicalResourceType(),
+		Currency:        duration.Currency,
+		CostMonthly:     costMonthly,
+		PricingCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		LineItems:       lineItems,
+	}, nil
+}
+
+// estimateFunctionUsageFromRequest extracts and validates Function App
+// attributes. Only the Consumption plan is priced here; dedicated plans are
+// priced as web/AppServicePlan.
+func estimateFunctionUsageFromRequest(req *finfocusv1.EstimateCostRequest) (functionUsage, error) {
+	attributes := estimateAttributes(req)
+
+	if plan := firstNonEmptyMapValue(attributes, planAttributeKeys...); plan != "" &&
+		!consumptionPlanNames[strings.ToLower(plan)] {
+		return functionUsage{}, fmt.Errorf("unsupported function app plan: %s "+
+			"(only Consumption is supported; price dedicated plans as web/AppServicePlan)", plan)
+	}
+
+	region := firstNonEmptyMapValue(attributes, regionAttributeKeys...)
+	executionsStr := firstNonEmptyMapValue(attributes, executionsAttributeKeys...)
+	gbSecondsStr := firstNonEmptyMapValue(attributes, gbSecondsAttributeKeys...)
+	var missingFields []string
+	if region == "" {
+		missingFields = append(missingFields, "region")
+	}
+	if executionsStr == "" {
+		missingFields = append(missingFields, "executions")
+	}
+	if gbSecondsStr == "" {
+		missingFields = append(missingFields, "gb_seconds")
+	}
+	if len(missingFields) > 0 {
+		return functionUsage{}, fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+	}
+
+	region, err := azureclient.NormalizeRegion(region)
+	if err != nil {
+		return functionUsage{}, err
+	}
+	executions, err := parseUsageQuantity("executions", executionsStr)
+	if err != nil {
+		return functionUsage{}, err
+	}
+	gbSeconds, err := parseUsageQuantity("gb_seconds", gbSecondsStr)
+	if err != nil {
+		return functionUsage{}, err
+	}
+
+	currency, err := currencyFromAttributes(attributes)
+	if err != nil {
+		return functionUsage{}, err
+	}
+	return functionUsage{
+		Query: azureclient.PriceQuery{
+			ArmRegionName: region,
+			ServiceName:   functionsServiceName,
+			CurrencyCode:  currency,
+		},
+		Executions: executions,
+		GBSeconds:  gbSeconds,
+	}, nil
+}
+
+// parseUsageQuantity parses a non-negative usage quantity attribute value.
+func parseUsageQuantity(name, value string) (float64, error) {
+	quantity, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(quantity) || math.IsInf(quantity, 0) {
+		return 0, fmt.Errorf("%s must be a valid number: %s", name, value)
+	}
+	if quantity < 0 {
+		return 0, fmt.Errorf("%s must not be negative", name)
+	}
+	return quantity, nil
+}
+
+// graduatedMeterCharge prices quantity against the tiers of a usage meter.
+// quantity is converted to billing units by dividing by the meter's pack size
+// (e.g., "10" executions or "10K Transactions") and priced by tieredRate.
+// Usage below the first paid tier is the free grant.
+func graduatedMeterCharge(items []azureclient.PriceItem, meterName string, quantity float64) (meterCharge, error) {
+	var tiers []azureclient.PriceItem
+	for _, item := range items {
+		if strings.EqualFold(item.MeterName, meterName) {
+			tiers = append(tiers, item)
+		}
+	}
+	if len(tiers) == 0 {
+		return meterCharge{}, fmt.Errorf("no pricing found for meter %s: %w", meterName, azureclient.ErrNotFound)
+	}
+
+	unit, err := meterUnit(tiers[0], periodNone)
+	if err != nil {
+		return meterCharge{}, fmt.Errorf("meter %s: %w", meterName, err)
+	}
+	packSize, err := unit.usagePackSize()
+	if err != nil {
+		return meterCharge{}, fmt.Errorf("meter %s: %w", meterName, err)
+	}
+	charge, err := tieredRate(tiers, quantity/packSize)
+	if err != nil {
+		return meterCharge{}, fmt.Errorf("meter %s: %w", meterName, err)
+	}
+	return meterCharge{
+		Cost:      charge.Cost,
+		Currency:  charge.Currency,
+		FreeUnits: charge.FreeUnits * packSize,
+		LineItems: charge.lineItems(),
+	}, nil
+}
+
+// isFunctionAppResourceType checks whether the lowercased resource type
+// refers to web/functionapp as a full segment.
+func isFunctionAppResourceType(lower string) bool {
+	return hasResourceTypeSegment(lower, "web/functionapp")
+}
@@ -0,0 +1,153 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// functionsTestPriceItems mirrors the Consumption plan meters: a zero-priced
+// free grant tier followed by a paid tier, with executions billed per 10.
+func functionsTestPriceItems() []azureclient.PriceItem {
+	return []azureclient.PriceItem{
+		{
+			ServiceName: functionsServiceName, MeterName: functionsExecutionTimeMeter,
+			UnitOfMeasure: "1 GB Second", TierMinimumUnits: 0, RetailPrice: 0, CurrencyCode: "USD",
+		},
+		{
+			ServiceName: functionsServiceName, MeterName: functionsExecutionTimeMeter,
+			UnitOfMeasure: "1 GB Second", TierMinimumUnits: 400000, RetailPrice: 0.000016, CurrencyCode: "USD",
+		},
+		{
+			ServiceName: functionsServiceName, MeterName: functionsExecutionsMeter,
+			UnitOfMeasure: "10", TierMinimumUnits: 0, RetailPrice: 0, CurrencyCode: "USD",
+		},
+		{
+			ServiceName: functionsServiceName, MeterName: functionsExecutionsMeter,
+			UnitOfMeasure: "10", TierMinimumUnits: 100000, RetailPrice: 0.000002, CurrencyCode: "USD",
+		},
+	}
+}
+
+func TestEstimateCost_FunctionApp_AppliesFreeGrant(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, functionsTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name       string
+		executions float64
+		gbSeconds  float64
+		want       float64
+	}{
+		{name: "within free grant", executions: 500000, gbSeconds: 100000, want: 0},
+		{
+			name:       "above free grant",
+			executions: 3000000,
+			gbSeconds:  1000000,
+			// (3M - 1M) / 10 * 0.000002 + (1M - 400K) * 0.000016
+			want: 200000*0.000002 + 600000*0.000016,
+		},
+	}
+
+	for _, tc := range tests {
+		resp, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+			"web/FunctionApp",
+			map[string]any{"location": "eastus", "executions": tc.executions, "gbSeconds": tc.gbSeconds},
+		))
+		if err != nil {
+			t.Fatalf("%s: EstimateCost() failed: %v", tc.name, err)
+		}
+		if math.Abs(resp.GetCostMonthly()-tc.want) > 0.000001 {
+			t.Errorf("%s: CostMonthly = %.6f, want %.6f", tc.name, resp.GetCostMonthly(), tc.want)
+		}
+	}
+}
+
+func TestEstimateCost_FunctionApp_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		substrings []string
+	}{
+		{
+			name:       "missing fields",
+			attributes: map[string]any{},
+			substrings: []string{"missing required field(s): region, executions, gb_seconds"},
+		},
+		{
+			name:       "negative executions",
+			attributes: map[string]any{"region": "eastus", "executions": -1, "gb_seconds": 0},
+			substrings: []string{"executions must not be negative"},
+		},
+		{
+			name:       "non-numeric gb seconds",
+			attributes: map[string]any{"region": "eastus", "executions": 1, "gb_seconds": "lots"},
+			substrings: []string{"gb_seconds must be a valid number: lots"},
+		},
+		{
+			name:       "premium plan",
+			attributes: map[string]any{"region": "eastus", "plan": "EP1", "executions": 1, "gb_seconds": 1},
+			substrings: []string{"unsupported function app plan: EP1", "web/AppServicePlan"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, "web/FunctionApp", tc.attributes))
+			assertStatusCodeContains(t, err, codes.InvalidArgument, tc.substrings...)
+		})
+	}
+}
+
+func TestGraduatedMeterCharge(t *testing.T) {
+	t.Parallel()
+
+	items := functionsTestPriceItems()
+
+	charge, err := graduatedMeterCharge(items, functionsExecutionsMeter, 2000000)
+	if err != nil {
+		t.Fatalf("graduatedMeterCharge() failed: %v", err)
+	}
+	if charge.FreeUnits != 1000000 {
+		t.Errorf("FreeUnits = %v, want 1000000 executions", charge.FreeUnits)
+	}
+	if want := 100000 * 0.000002; math.Abs(charge.Cost-want) > 1e-12 {
+		t.Errorf("Cost = %v, want %v", charge.Cost, want)
+	}
+	// Quantities are in billing units of 10 executions.
+	if len(charge.LineItems) != 2 || charge.LineItems[0].Description != "free grant" ||
+		charge.LineItems[0].Quantity != 100000 || charge.LineItems[1].Quantity != 100000 {
+		t.Errorf("LineItems = %+v, want free grant and one paid tier of 100000 units", charge.LineItems)
+	}
+
+	// A grant implied only by the first paid tier's minimum.
+	paidOnly := []azureclient.PriceItem{items[1]}
+	charge, err = graduatedMeterCharge(paidOnly, functionsExecutionTimeMeter, 500000)
+	if err != nil {
+		t.Fatalf("graduatedMeterCharge() failed: %v", err)
+	}
+	if charge.FreeUnits != 400000 || math.Abs(charge.Cost-100000*0.000016) > 1e-12 {
+		t.Errorf("got %+v, want 400000 free GB-seconds", charge)
+	}
+
+	if _, err = graduatedMeterCharge(items, "Always Ready", 1); err == nil {
+		t.Error("expected error for missing meter")
+	}
+}
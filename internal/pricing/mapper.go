@@ -11,25 +11,33 @@ import (
 )
 
 // defaultCurrency is the currency code used when no preference is specified.
-const defaultCurrency = "USD"
+const defaultCurrency = azureclient.DefaultCurrency
 
 // resourceTypeToService maps normalized (lowercased) resource type identifiers
 // to their corresponding Azure service names for the Retail Prices API.
 //
 //nolint:gochecknoglobals // Static lookup table; immutable after init.
 var resourceTypeToService = map[string]string{
-	"compute/virtualmachine": "Virtual Machines",
-	"storage/manageddisk":    "Managed Disks",
-	"storage/blobstorage":    "Storage",
+	"compute/virtualmachine":          "Virtual Machines",
+	"compute/virtualmachinescaleset":  "Virtual Machines",
+	"storage/manageddisk":             "Managed Disks",
+	"storage/blobstorage":             "Storage",
+	"containerservice/managedcluster": "Azure Kubernetes Service",
+	"web/appserviceplan":              "Azure App Service",
+	"web/functionapp":                 "Functions",
 }
 
 // canonicalResourceTypes maps normalized keys back to their display form.
 //
 //nolint:gochecknoglobals // Static lookup table; immutable after init.
 var canonicalResourceTypes = map[string]string{
-	"compute/virtualmachine": "compute/VirtualMachine",
-	"storage/manageddisk":    "storage/ManagedDisk",
-	"storage/blobstorage":    "storage/BlobStorage",
+	"compute/virtualmachine":          "compute/VirtualMachine",
+	"compute/virtualmachinescaleset":  "compute/VirtualMachineScaleSet",
+	"storage/manageddisk":             "storage/ManagedDisk",
+	"storage/blobstorage":             "storage/BlobStorage",
+	"containerservice/managedcluster": "containerservice/ManagedCluster",
+	"web/appserviceplan":              "web/AppServicePlan",
+	"web/functionapp":                 "web/FunctionApp",
 }
 
 // MapDescriptorToQuery translates a finfocus ResourceDescriptor into an
@@ -38,11 +46,16 @@ var canonicalResourceTypes = map[string]string{
 // Validation is performed before mapping:
 //   - Provider must be "azure" (case-insensitive)
 //   - ResourceType must match a supported type (case-insensitive)
-//   - Region must be resolvable (primary field or Tags["region"])
+//   - Region must be resolvable (primary field or Tags["region"]) and name a
+//     known Azure region; display names and aliases such as "East US" or
+//     "east-us" are normalized to the armRegionName
 //   - SKU must be resolvable (primary field or Tags["sku"])
 //
 // Returns ErrUnsupportedResourceType for unknown providers or resource types.
 // Returns ErrMissingRequiredFields naming all missing fields in a single error.
+// Returns azureclient.ErrUnknownRegion for unknown regions.
+// Returns azureclient.ErrUnsupportedCurrency when Tags["currency"] (or
+// Tags["currencyCode"]) is not a currency the Retail Prices API supports.
 // Returns a valid *PriceQuery with CurrencyCode defaulted to "USD" on success.
 func MapDescriptorToQuery(desc *finfocusv1.ResourceDescriptor) (*azureclient.PriceQuery, error) {
 	if desc == nil {
@@ -77,11 +90,20 @@ func MapDescriptorToQuery(desc *finfocusv1.ResourceDescriptor) (*azureclient.Pri
 		return nil, fmt.Errorf("%w: %s", ErrMissingRequiredFields, strings.Join(missing, ", "))
 	}
 
+	region, err := azureclient.NormalizeRegion(region)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := currencyFromTags(desc.GetTags())
+	if err != nil {
+		return nil, err
+	}
+
 	return &azureclient.PriceQuery{
 		ArmRegionName: region,
 		ArmSkuName:    sku,
 		ServiceName:   serviceName,
-		CurrencyCode:  defaultCurrency,
+		CurrencyCode:  currency,
 	}, nil
 }
 
@@ -110,3 +132,21 @@ func resolveField(primary, tagKey string, tags map[string]string) string {
 	}
 	return ""
 }
+
+// hasResourceTypeSegment checks whether the lowercased resource type contains
+// segment as a full segment: the match must end the string or be followed by
+// a separator (':', '/', ' '), so "compute/virtualmachine" does not match
+// "compute/virtualmachinescaleset".
+func hasResourceTypeSegment(lower, segment string) bool {
+	idx := strings.Index(lower, segment)
+	if idx < 0 {
+		return false
+	}
+	end := idx + len(segment)
+	if end == len(lower) {
+		return true
+	}
+	// Next char must be a segment separator, not a continuation letter/digit.
+	next := lower[end]
+	return next == ':' || next == '/' || next == ' '
+}
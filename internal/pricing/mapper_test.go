@@ -6,10 +6,42 @@ import (
 	"testing"
 
 	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
 )
 
 // --- US1: Map VM Resource to Pricing Query ---
 
+func TestMapDescriptorToQuery_NormalizesRegion(t *testing.T) {
+	t.Parallel()
+
+	query, err := MapDescriptorToQuery(&finfocusv1.ResourceDescriptor{
+		Provider:     "azure",
+		ResourceType: "compute/VirtualMachine",
+		Sku:          "Standard_B1s",
+		Region:       "West Europe",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if query.ArmRegionName != "westeurope" {
+		t.Errorf("ArmRegionName = %q, want westeurope", query.ArmRegionName)
+	}
+
+	_, err = MapDescriptorToQuery(&finfocusv1.ResourceDescriptor{
+		Provider:     "azure",
+		ResourceType: "compute/VirtualMachine",
+		Sku:          "Standard_B1s",
+		Region:       "westeuorpe",
+	})
+	if !errors.Is(err, azureclient.ErrUnknownRegion) {
+		t.Fatalf("expected ErrUnknownRegion, got %v", err)
+	}
+	if !strings.Contains(err.Error(), `did you mean "westeurope"?`) {
+		t.Errorf("expected did-you-mean suggestion, got %q", err.Error())
+	}
+}
+
 func TestMapDescriptorToQuery_ValidVM(t *testing.T) {
 	t.Parallel()
 
@@ -60,6 +92,20 @@ func TestMapDescriptorToQuery_ValidVM(t *testing.T) {
 			wantService:  "Virtual Machines",
 			wantCurrency: "USD",
 		},
+		{
+			name: "currency tag normalized",
+			desc: &finfocusv1.ResourceDescriptor{
+				Provider:     "azure",
+				ResourceType: "compute/VirtualMachine",
+				Sku:          "Standard_B1s",
+				Region:       "westeurope",
+				Tags:         map[string]string{"currency": " eur "},
+			},
+			wantRegion:   "westeurope",
+			wantSKU:      "Standard_B1s",
+			wantService:  "Virtual Machines",
+			wantCurrency: "EUR",
+		},
 	}
 
 	for _, tt := range tests {
@@ -389,8 +435,12 @@ func TestSupportedResourceTypes(t *testing.T) {
 	types := SupportedResourceTypes()
 	expected := []string{
 		"compute/VirtualMachine",
+		"compute/VirtualMachineScaleSet",
+		"containerservice/ManagedCluster",
 		"storage/BlobStorage",
 		"storage/ManagedDisk",
+		"web/AppServicePlan",
+		"web/FunctionApp",
 	}
 	if len(types) != len(expected) {
 		t.Fatalf("expected %d types, got %d: %v", len(expected), len(types), types)
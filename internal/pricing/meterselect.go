@@ -0,0 +1,169 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// ErrAmbiguousMeter is returned when several price items remain after meter
+// selection and they disagree on price.
+// Maps to gRPC codes.FailedPrecondition via MapToGRPCStatus.
+var ErrAmbiguousMeter = errors.New("ambiguous meter selection")
+
+// maxListedCandidates caps how many candidates an ambiguity error lists.
+const maxListedCandidates = 5
+
+// meterCriteria describes which meter a price lookup is looking for.
+type meterCriteria struct {
+	// Model is the meter family; Spot and Low Priority meters are excluded
+	// unless requested.
+	Model pricingModel
+	// OS restricts VM meters to osLinux or osWindows. Empty matches both.
+	OS string
+	// ArmSkuName restricts items to the queried SKU. Empty matches all.
+	ArmSkuName string
+}
+
+// String renders the criteria for error messages, e.g. "standard Linux".
+func (m meterCriteria) String() string {
+	if m.OS == "" {
+		return string(m.Model)
+	}
+	return string(m.Model) + " " + m.OS
+}
+
+// meterCriteriaFromTags resolves meter criteria from resource tags using the
+// EstimateCost pricing model and OS attributes. Defaults to standard Linux
+// meters; Azure Hybrid Benefit selects the Linux compute rate.
+func meterCriteriaFromTags(tags map[string]string) (meterCriteria, error) {
+	attributes := make(map[string]any, len(tags))
+	for key, value := range tags {
+		attributes[key] = value
+	}
+
+	model, err := pricingModelFromAttributes(attributes)
+	if err != nil {
+		return meterCriteria{}, err
+	}
+	osName, hybridBenefit, err := vmOSFromAttributes(attributes)
+	if err != nil {
+		return meterCriteria{}, err
+	}
+	if hybridBenefit {
+		osName = osLinux
+	}
+	return meterCriteria{Model: model, OS: osName}, nil
+}
+
+// selectMeter picks the price item for criteria using explicit ranking rules
+// instead of API order:
+//
+//  1. Keep items of the requested SKU, pricing model, and OS; DevTest rows
+//     are always excluded.
+//  2. Prefer items from the primary meter region.
+//  3. Prefer the latest EffectiveStartDate.
+//
+// Remaining candidates with the same price are equivalent and the one with
+// the lowest MeterID is returned. Candidates that still disagree on price
+// yield ErrAmbiguousMeter listing them; no candidates yield
+// azureclient.ErrNotFound.
+func selectMeter(items []azureclient.PriceItem, criteria meterCriteria) (azureclient.PriceItem, error) {
+	var candidates []azureclient.PriceItem
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.Type), "devtest") ||
+			(criteria.ArmSkuName != "" && item.ArmSkuName != "" &&
+				!strings.EqualFold(item.ArmSkuName, criteria.ArmSkuName)) ||
+			itemPricingModel(item) != criteria.Model ||
+			(criteria.OS != "" && itemOS(item) != criteria.OS) {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+	if len(candidates) == 0 {
+		return azureclient.PriceItem{}, fmt.Errorf("no %s meter found: %w", criteria, azureclient.ErrNotFound)
+	}
+
+	candidates = preferPrimaryMeterRegion(candidates)
+	candidates = preferLatestEffectiveStart(candidates)
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].MeterID < candidates[j].MeterID })
+	price, currency := itemPriceAndCurrency(candidates[0])
+	for _, candidate := range candidates[1:] {
+		otherPrice, otherCurrency := itemPriceAndCurrency(candidate)
+		if otherPrice != price || otherCurrency != currency {
+			return azureclient.PriceItem{}, ambiguousMeterError(criteria, candidates)
+		}
+	}
+	return candidates[0], nil
+}
+
+// preferPrimaryMeterRegion keeps only primary meter region items when there
+// are any.
+func preferPrimaryMeterRegion(items []azureclient.PriceItem) []azureclient.PriceItem {
+	var primary []azureclient.PriceItem
+	for _, item := range items {
+		if item.IsPrimaryMeterRegion {
+			primary = append(primary, item)
+		}
+	}
+	if len(primary) == 0 {
+		return items
+	}
+	return primary
+}
+
+// preferLatestEffectiveStart keeps only the items with the latest
+// EffectiveStartDate. Missing or unparsable dates sort as oldest.
+func preferLatestEffectiveStart(items []azureclient.PriceItem) []azureclient.PriceItem {
+	var latest time.Time
+	starts := make([]time.Time, len(items))
+	for i, item := range items {
+		starts[i], _ = time.Parse(time.RFC3339, strings.TrimSpace(item.EffectiveStartDate))
+		if starts[i].After(latest) {
+			latest = starts[i]
+		}
+	}
+
+	var kept []azureclient.PriceItem
+	for i, item := range items {
+		if starts[i].Equal(latest) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// ambiguousMeterError lists the candidates that could not be told apart.
+func ambiguousMeterError(criteria meterCriteria, candidates []azureclient.PriceItem) error {
+	descriptions := make([]string, 0, maxListedCandidates)
+	for i, candidate := range candidates {
+		if i == maxListedCandidates {
+			descriptions = append(descriptions, fmt.Sprintf("and %d more", len(candidates)-i))
+			break
+		}
+		price, currency := itemPriceAndCurrency(candidate)
+		descriptions = append(descriptions, fmt.Sprintf("%q (product %q, meter %s) at %g %s",
+			candidate.MeterName, candidate.ProductName, candidate.MeterID, price, currency))
+	}
+	return fmt.Errorf("%w: %d %s meters match (%s); narrow the query with product_name",
+		ErrAmbiguousMeter, len(candidates), criteria, strings.Join(descriptions, "; "))
+}
+
+// itemPriceAndCurrency returns the price of an item, falling back to
+// UnitPrice when RetailPrice is zero, and its currency (default USD).
+func itemPriceAndCurrency(item azureclient.PriceItem) (float64, string) {
+	price := item.RetailPrice
+	if price == 0 {
+		price = item.UnitPrice
+	}
+	currency := item.CurrencyCode
+	if strings.TrimSpace(currency) == "" {
+		currency = defaultCurrency
+	}
+	return price, currency
+}
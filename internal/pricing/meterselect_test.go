@@ -0,0 +1,123 @@
+package pricing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestSelectMeter_RankingRules(t *testing.T) {
+	t.Parallel()
+
+	standard := meterCriteria{Model: pricingModelStandard, OS: osLinux}
+
+	tests := []struct {
+		name     string
+		items    []azureclient.PriceItem
+		criteria meterCriteria
+		wantID   string
+	}{
+		{
+			name: "excludes spot, low priority, windows, and devtest",
+			items: []azureclient.PriceItem{
+				{MeterID: "spot", SkuName: "D2s v3 Spot", RetailPrice: 0.02},
+				{MeterID: "low", MeterName: "D2s v3 Low Priority", RetailPrice: 0.03},
+				{MeterID: "windows", ProductName: "Virtual Machines DSv3 Series Windows", RetailPrice: 0.188},
+				{MeterID: "devtest", Type: "DevTestConsumption", RetailPrice: 0.05},
+				{MeterID: "linux", ProductName: "Virtual Machines DSv3 Series", RetailPrice: 0.096},
+			},
+			criteria: standard,
+			wantID:   "linux",
+		},
+		{
+			name: "prefers primary meter region",
+			items: []azureclient.PriceItem{
+				{MeterID: "secondary", RetailPrice: 0.2},
+				{MeterID: "primary", IsPrimaryMeterRegion: true, RetailPrice: 0.1},
+			},
+			criteria: standard,
+			wantID:   "primary",
+		},
+		{
+			name: "prefers latest effective start date",
+			items: []azureclient.PriceItem{
+				{MeterID: "new", EffectiveStartDate: "2024-03-01T00:00:00Z", RetailPrice: 0.11},
+				{MeterID: "old", EffectiveStartDate: "2021-01-01T00:00:00Z", RetailPrice: 0.1},
+				{MeterID: "undated", RetailPrice: 0.09},
+			},
+			criteria: standard,
+			wantID:   "new",
+		},
+		{
+			name: "equal prices pick lowest meter id",
+			items: []azureclient.PriceItem{
+				{MeterID: "b", RetailPrice: 0.1},
+				{MeterID: "a", UnitPrice: 0.1},
+			},
+			criteria: standard,
+			wantID:   "a",
+		},
+		{
+			name: "skips other skus",
+			items: []azureclient.PriceItem{
+				{MeterID: "disk", ArmSkuName: "Premium_LRS", RetailPrice: 19.71},
+				{MeterID: "vm", ArmSkuName: "Standard_B1s", RetailPrice: 0.02},
+			},
+			criteria: meterCriteria{Model: pricingModelStandard, OS: osLinux, ArmSkuName: "standard_b1s"},
+			wantID:   "vm",
+		},
+		{
+			name: "spot requested",
+			items: []azureclient.PriceItem{
+				{MeterID: "regular", SkuName: "D2s v3", RetailPrice: 0.096},
+				{MeterID: "spot", SkuName: "D2s v3 Spot", RetailPrice: 0.02},
+			},
+			criteria: meterCriteria{Model: pricingModelSpot},
+			wantID:   "spot",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			item, err := selectMeter(tc.items, tc.criteria)
+			if err != nil {
+				t.Fatalf("selectMeter() failed: %v", err)
+			}
+			if item.MeterID != tc.wantID {
+				t.Errorf("selected meter %q, want %q", item.MeterID, tc.wantID)
+			}
+		})
+	}
+}
+
+func TestSelectMeter_Errors(t *testing.T) {
+	t.Parallel()
+
+	criteria := meterCriteria{Model: pricingModelStandard, OS: osLinux}
+
+	_, err := selectMeter([]azureclient.PriceItem{{SkuName: "D2s v3 Spot"}}, criteria)
+	if !errors.Is(err, azureclient.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+
+	var items []azureclient.PriceItem
+	for _, id := range []string{"g", "f", "e", "d", "c", "b", "a"} {
+		items = append(items, azureclient.PriceItem{MeterID: id, MeterName: "meter " + id, RetailPrice: float64(len(items))})
+	}
+	_, err = selectMeter(items, criteria)
+	if !errors.Is(err, ErrAmbiguousMeter) {
+		t.Fatalf("error = %v, want ErrAmbiguousMeter", err)
+	}
+	for _, want := range []string{"7 standard Linux meters match", `"meter a"`, "meter e", "and 2 more"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not contain %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), `"meter f"`) {
+		t.Errorf("error %q lists more than %d candidates", err, maxListedCandidates)
+	}
+}
@@ -0,0 +1,104 @@
+package pricing
+
+import (
+	"fmt"
+	"strings"
+
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// pricingModel selects which VM meter family prices an instance.
+type pricingModel string
+
+const (
+	pricingModelStandard    pricingModel = "standard"
+	pricingModelSpot        pricingModel = "spot"
+	pricingModelLowPriority pricingModel = "low_priority"
+)
+
+// pricingModelAttributeKeys are the pricing model attribute aliases, in lookup
+// precedence order. "priority" is the ARM property (Regular, Spot, Low).
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var pricingModelAttributeKeys = []string{"pricingModel", "pricing_model", "priority"}
+
+// pricingModelNames maps accepted (lowercased) pricing model values to their
+// pricing model.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var pricingModelNames = map[string]pricingModel{
+	"standard":     pricingModelStandard,
+	"regular":      pricingModelStandard,
+	"spot":         pricingModelSpot,
+	"low_priority": pricingModelLowPriority,
+	"lowpriority":  pricingModelLowPriority,
+	"low":          pricingModelLowPriority,
+}
+
+// supportedPricingModelNames lists the canonical pricing model values.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var supportedPricingModelNames = []string{
+	string(pricingModelStandard),
+	string(pricingModelSpot),
+	string(pricingModelLowPriority),
+}
+
+// vmPricing is a validated VM pricing description: the per-instance query
+// and the meter family to select from its results.
+type vmPricing struct {
+	Query azureclient.PriceQuery
+	Model pricingModel
+	// OS is the instance operating system (osLinux or osWindows).
+	OS string
+	// HybridBenefit prices a Windows instance at the Linux compute rate.
+	HybridBenefit bool
+	// IncludeReservations requests a reserved instance comparison.
+	IncludeReservations bool
+}
+
+// pricingModelFromAttributes resolves the pricing model attribute. Defaults to
+// standard (pay-as-you-go) pricing.
+func pricingModelFromAttributes(attributes map[string]any) (pricingModel, error) {
+	return parsePricingModel(firstNonEmptyMapValue(attributes, pricingModelAttributeKeys...))
+}
+
+// parsePricingModel resolves a pricing model value. An empty value selects
+// standard (pay-as-you-go) pricing.
+func parsePricingModel(value string) (pricingModel, error) {
+	if value == "" {
+		return pricingModelStandard, nil
+	}
+	model, ok := pricingModelNames[strings.ToLower(strings.ReplaceAll(value, " ", "_"))]
+	if !ok {
+		return "", fmt.Errorf("unsupported pricing_model: %s (supported: %s)",
+			value, strings.Join(supportedPricingModelNames, ", "))
+	}
+	return model, nil
+}
+
+// pricingCategory returns the FOCUS pricing category of the pricing model.
+// Spot prices float with spare capacity, so they are reported as dynamic.
+func (m pricingModel) pricingCategory() finfocusv1.FocusPricingCategory {
+	if m == pricingModelSpot {
+		return finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_DYNAMIC
+	}
+	return finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD
+}
+
+// itemPricingModel classifies a VM price item by the " Spot" or
+// " Low Priority" suffix Azure appends to its SkuName and MeterName.
+func itemPricingModel(item azureclient.PriceItem) pricingModel {
+	for _, name := range []string{item.SkuName, item.MeterName} {
+		lower := strings.ToLower(strings.TrimSpace(name))
+		switch {
+		case strings.HasSuffix(lower, " spot"):
+			return pricingModelSpot
+		case strings.HasSuffix(lower, " low priority"):
+			return pricingModelLowPriority
+		}
+	}
+	return pricingModelStandard
+}
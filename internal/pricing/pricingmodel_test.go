@@ -0,0 +1,138 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// pricingModelTestPriceItems lists the Spot and Low Priority meters first, so
+// taking the first item would pick the wrong price.
+func pricingModelTestPriceItems() []azureclient.PriceItem {
+	return []azureclient.PriceItem{
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3 Spot", MeterName: "D2s v3 Spot",
+			RetailPrice: 0.0192, CurrencyCode: "USD",
+		},
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3 Low Priority", MeterName: "D2s v3 Low Priority",
+			RetailPrice: 0.0192 * 2, CurrencyCode: "USD",
+		},
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", MeterName: "D2s v3",
+			RetailPrice: 0.096, CurrencyCode: "USD",
+		},
+	}
+}
+
+func TestEstimate_PricingModel(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, pricingModelTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name         string
+		resourceType string
+		attributes   map[string]any
+		wantHourly   float64
+		wantCategory finfocusv1.FocusPricingCategory
+	}{
+		{
+			name:         "default is standard",
+			resourceType: "compute/VirtualMachine",
+			attributes:   map[string]any{"region": "eastus", "sku": "Standard_D2s_v3"},
+			wantHourly:   0.096,
+			wantCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		},
+		{
+			name:         "spot is dynamic",
+			resourceType: "compute/VirtualMachine",
+			attributes:   map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "pricing_model": "spot"},
+			wantHourly:   0.0192,
+			wantCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_DYNAMIC,
+		},
+		{
+			name:         "arm low priority",
+			resourceType: "compute/VirtualMachine",
+			attributes:   map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "priority": "Low"},
+			wantHourly:   0.0384,
+			wantCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		},
+		{
+			name:         "spot scale set",
+			resourceType: "compute/VirtualMachineScaleSet",
+			attributes: map[string]any{
+				"region": "eastus", "sku": "Standard_D2s_v3", "capacity": 1, "pricingModel": "Spot",
+			},
+			wantHourly:   0.0192,
+			wantCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_DYNAMIC,
+		},
+	}
+
+	for _, tc := range tests {
+		estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, tc.resourceType, tc.attributes))
+		if err != nil {
+			t.Fatalf("%s: Estimate() failed: %v", tc.name, err)
+		}
+		if want := tc.wantHourly * 730; math.Abs(estimate.CostMonthly-want) > 0.000001 {
+			t.Errorf("%s: CostMonthly = %.6f, want %.6f", tc.name, estimate.CostMonthly, want)
+		}
+		if estimate.PricingCategory != tc.wantCategory {
+			t.Errorf("%s: PricingCategory = %v, want %v", tc.name, estimate.PricingCategory, tc.wantCategory)
+		}
+	}
+}
+
+func TestEstimateCost_PricingModel_NoMatchingMeter(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, pricingModelTestPriceItems()[2:], nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "pricing_model": "spot"}))
+	assertStatusCodeContains(t, err, codes.NotFound, "no spot Linux pricing found for sku Standard_D2s_v3")
+}
+
+func TestPricingModelFromAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]pricingModel{
+		"":             pricingModelStandard,
+		"Regular":      pricingModelStandard,
+		"SPOT":         pricingModelSpot,
+		"low_priority": pricingModelLowPriority,
+		"Low Priority": pricingModelLowPriority,
+	}
+	for input, want := range tests {
+		got, err := pricingModelFromAttributes(map[string]any{"pricing_model": input})
+		if err != nil {
+			t.Fatalf("pricingModelFromAttributes(%q) failed: %v", input, err)
+		}
+		if got != want {
+			t.Errorf("pricingModelFromAttributes(%q) = %q, want %q", input, got, want)
+		}
+	}
+
+	_, err := pricingModelFromAttributes(map[string]any{"pricing_model": "reserved"})
+	if err == nil || !strings.Contains(err.Error(), "unsupported pricing_model: reserved") {
+		t.Errorf("error = %v, want unsupported pricing_model", err)
+	}
+}
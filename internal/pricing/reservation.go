@@ -0,0 +1,168 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+// reservationPriceType is the Retail Prices priceType of reserved instances.
+const reservationPriceType = "Reservation"
+
+// includeReservationsAttributeKeys are the reservation comparison attribute
+// aliases, in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var includeReservationsAttributeKeys = []string{"includeReservations", "include_reservations"}
+
+// reservationTermMonths maps lowercased Retail Prices reservation and savings
+// plan terms to their length in months. Reservation retailPrice is the total
+// for the term.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var reservationTermMonths = map[string]float64{
+	"1 year":  12,
+	"3 years": 36,
+}
+
+// includeReservationsFromAttributes resolves the reservation comparison flag.
+// Reservations only apply to standard pricing, so the flag is rejected for
+// spot and low priority estimates.
+func includeReservationsFromAttributes(attributes map[string]any, model pricingModel) (bool, error) {
+	value := firstNonEmptyMapValue(attributes, includeReservationsAttributeKeys...)
+	if value == "" {
+		return false, nil
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("include_reservations must be true or false: %s", value)
+	}
+	if include && model != pricingModelStandard {
+		return false, fmt.Errorf("include_reservations requires the standard pricing_model, got %s", model)
+	}
+	return include, nil
+}
+
+// reservationQuery returns the Reservation price type variant of a VM query.
+// Reservation prices need no savings plan API version.
+func reservationQuery(query azureclient.PriceQuery) azureclient.PriceQuery {
+	query.PriceType = reservationPriceType
+	query.APIVersion = ""
+	return query
+}
+
+// lookupReservationEstimates fetches the reserved instance prices of a VM and
+// compares each term against the pay-as-you-go monthly cost. Reservations
+// cover compute only, so licenseMonthly is added to every term. A SKU without
+// reservation prices yields no estimates rather than an error, but a term
+// with conflicting prices fails like an ambiguous pay-as-you-go meter. Errors
+// are gRPC status errors and are logged before being returned.
+func (c *Calculator) lookupReservationEstimates(
+	ctx context.Context,
+	vm vmPricing,
+	payAsYouGoMonthly float64,
+	licenseMonthly float64,
+	resourceType string,
+) ([]ReservationEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+	query := reservationQuery(vm.Query)
+
+	var items []azureclient.PriceItem
+	result, err := c.cachedClient.GetPrices(ctx, query)
+	switch {
+	case errors.Is(err, azureclient.ErrNotFound):
+		// No reservation offering for this SKU; reported as no estimates.
+	case err != nil:
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("sku", query.ArmSkuName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost reservation pricing lookup failed")
+		return nil, err
+	default:
+		items = result.Items
+	}
+
+	reservations, err := reservationEstimates(items, query.ArmSkuName, payAsYouGoMonthly, licenseMonthly)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", query.ArmRegionName).
+			Str("sku", query.ArmSkuName).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost reservation response mapping failed")
+		return nil, err
+	}
+	if len(reservations) == 0 {
+		log.Warn().
+			Str("region", query.ArmRegionName).
+			Str("sku", query.ArmSkuName).
+			Str("resource_type", resourceType).
+			Msg("EstimateCost found no reservation pricing")
+	}
+	return reservations, nil
+}
+
+// reservationEstimates normalizes reservation term prices to monthly costs,
+// adds licenseMonthly, and computes the saving against payAsYouGoMonthly.
+// The compute (non-Windows) price of each known term is chosen by
+// selectMeter, so primary meter region and latest EffectiveStartDate rows
+// win and prices that still conflict yield ErrAmbiguousMeter. Terms are
+// ordered shortest first.
+func reservationEstimates(
+	items []azureclient.PriceItem,
+	armSkuName string,
+	payAsYouGoMonthly float64,
+	licenseMonthly float64,
+) ([]ReservationEstimate, error) {
+	byTerm := make(map[string][]azureclient.PriceItem)
+	for _, item := range items {
+		if !strings.EqualFold(item.Type, reservationPriceType) {
+			continue
+		}
+		term := strings.ToLower(strings.TrimSpace(item.ReservationTerm))
+		if _, ok := reservationTermMonths[term]; ok {
+			byTerm[term] = append(byTerm[term], item)
+		}
+	}
+
+	criteria := meterCriteria{Model: pricingModelStandard, OS: osLinux, ArmSkuName: armSkuName}
+	reservations := make([]ReservationEstimate, 0, len(byTerm))
+	for term, termItems := range byTerm {
+		item, err := selectMeter(termItems, criteria)
+		if errors.Is(err, azureclient.ErrNotFound) {
+			// Only Windows or other-SKU rows for this term.
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reservation term %s: %w", strings.TrimSpace(termItems[0].ReservationTerm), err)
+		}
+
+		price, _ := itemPriceAndCurrency(item)
+		reservation := ReservationEstimate{
+			Term:        strings.TrimSpace(item.ReservationTerm),
+			CostMonthly: price/reservationTermMonths[term] + licenseMonthly,
+		}
+		if payAsYouGoMonthly > 0 {
+			reservation.SavingsPercent = (payAsYouGoMonthly - reservation.CostMonthly) / payAsYouGoMonthly * 100
+		}
+		reservations = append(reservations, reservation)
+	}
+
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservationTermMonths[strings.ToLower(reservations[i].Term)] <
+			reservationTermMonths[strings.ToLower(reservations[j].Term)]
+	})
+	return reservations, nil
+}
@@ -0,0 +1,213 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// newPriceTypeServer serves the reservation items for Reservation price type
+// filters and the consumption items otherwise.
+func newPriceTypeServer(t *testing.T, consumption, reservation []azureclient.PriceItem) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		items := consumption
+		if strings.Contains(r.URL.Query().Get("$filter"), "priceType eq 'Reservation'") {
+			items = reservation
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(azureclient.PriceResponse{Items: items, Count: len(items)}); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+}
+
+func TestEstimate_IncludeReservations(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceTypeServer(t,
+		[]azureclient.PriceItem{
+			{ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", Type: "Consumption", RetailPrice: 0.1, CurrencyCode: "USD"},
+		},
+		[]azureclient.PriceItem{
+			{ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "3 Years", RetailPrice: 1314},
+			{ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "1 Year", RetailPrice: 525.6},
+		},
+	)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "include_reservations": true}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	if want := 0.1 * 730; math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Errorf("CostMonthly = %.6f, want pay-as-you-go %.6f", estimate.CostMonthly, want)
+	}
+	if estimate.PricingCategory != finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD {
+		t.Errorf("PricingCategory = %v, want STANDARD", estimate.PricingCategory)
+	}
+
+	want := []ReservationEstimate{
+		{Term: "1 Year", CostMonthly: 43.8, SavingsPercent: 40},
+		{Term: "3 Years", CostMonthly: 36.5, SavingsPercent: 50},
+	}
+	if len(estimate.Reservations) != len(want) {
+		t.Fatalf("Reservations = %+v, want %+v", estimate.Reservations, want)
+	}
+	for i, got := range estimate.Reservations {
+		if got.Term != want[i].Term ||
+			math.Abs(got.CostMonthly-want[i].CostMonthly) > 0.000001 ||
+			math.Abs(got.SavingsPercent-want[i].SavingsPercent) > 0.000001 {
+			t.Errorf("Reservations[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestEstimate_IncludeReservations_NoReservationPricing(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceTypeServer(t,
+		[]azureclient.PriceItem{{ArmSkuName: "Standard_B1s", SkuName: "B1s", RetailPrice: 0.0104, CurrencyCode: "USD"}},
+		nil,
+	)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_B1s", "includeReservations": "true"}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+	if len(estimate.Reservations) != 0 {
+		t.Errorf("Reservations = %+v, want none", estimate.Reservations)
+	}
+}
+
+func TestEstimateCost_IncludeReservations_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		substring  string
+	}{
+		{
+			name:       "not a boolean",
+			attributes: map[string]any{"region": "eastus", "sku": "Standard_B1s", "include_reservations": "yes please"},
+			substring:  "include_reservations must be true or false: yes please",
+		},
+		{
+			name: "spot",
+			attributes: map[string]any{
+				"region": "eastus", "sku": "Standard_B1s", "include_reservations": true, "pricing_model": "spot",
+			},
+			substring: "include_reservations requires the standard pricing_model, got spot",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			_, err := calc.EstimateCost(context.Background(),
+				newEstimateCostRequest(t, "compute/VirtualMachine", tc.attributes))
+			assertStatusCodeContains(t, err, codes.InvalidArgument, tc.substring)
+		})
+	}
+}
+
+func TestReservationEstimates_SkipsUnknownTermsAndWindowsMeters(t *testing.T) {
+	t.Parallel()
+
+	items := []azureclient.PriceItem{
+		{Type: "Consumption", RetailPrice: 1},
+		{Type: "Reservation", ReservationTerm: "5 Years", RetailPrice: 100},
+		{Type: "Reservation", ReservationTerm: "1 Year", UnitPrice: 120},
+		{Type: "Reservation", ReservationTerm: "1 Year", RetailPrice: 120, MeterID: "b"},
+		{Type: "Reservation", ReservationTerm: "3 Years", ProductName: "Virtual Machines DSv3 Series Windows"},
+	}
+
+	got, err := reservationEstimates(items, "Standard_D2s_v3", 0, 0)
+	if err != nil {
+		t.Fatalf("reservationEstimates() error = %v", err)
+	}
+	if len(got) != 1 || got[0].Term != "1 Year" || got[0].CostMonthly != 10 || got[0].SavingsPercent != 0 {
+		t.Errorf("reservationEstimates() = %+v, want one 1 Year term at 10/month", got)
+	}
+}
+
+func TestReservationEstimates_RanksMeters(t *testing.T) {
+	t.Parallel()
+
+	reservation := func(price float64, primary bool, start string) azureclient.PriceItem {
+		return azureclient.PriceItem{
+			ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "1 Year",
+			RetailPrice: price, IsPrimaryMeterRegion: primary, EffectiveStartDate: start,
+		}
+	}
+
+	got, err := reservationEstimates([]azureclient.PriceItem{
+		reservation(999, false, "2025-01-01T00:00:00Z"),
+		reservation(480, true, "2023-01-01T00:00:00Z"),
+		reservation(360, true, "2024-01-01T00:00:00Z"),
+	}, "Standard_D2s_v3", 0, 0)
+	if err != nil {
+		t.Fatalf("reservationEstimates() error = %v", err)
+	}
+	if len(got) != 1 || got[0].CostMonthly != 30 {
+		t.Errorf("reservationEstimates() = %+v, want the latest primary price at 30/month", got)
+	}
+
+	_, err = reservationEstimates([]azureclient.PriceItem{
+		reservation(360, true, "2024-01-01T00:00:00Z"),
+		reservation(480, true, "2024-01-01T00:00:00Z"),
+	}, "Standard_D2s_v3", 0, 0)
+	if !errors.Is(err, ErrAmbiguousMeter) || !strings.Contains(err.Error(), "reservation term 1 Year") {
+		t.Errorf("reservationEstimates() error = %v, want an ambiguous 1 Year term", err)
+	}
+}
+
+func TestEstimate_IncludeReservations_AmbiguousTerm(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceTypeServer(t,
+		[]azureclient.PriceItem{
+			{ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", Type: "Consumption", RetailPrice: 0.1, CurrencyCode: "USD"},
+		},
+		[]azureclient.PriceItem{
+			{ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "1 Year", RetailPrice: 525.6},
+			{ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "1 Year", RetailPrice: 600},
+		},
+	)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "include_reservations": true}))
+	assertStatusCodeContains(t, err, codes.FailedPrecondition, "reservation term 1 Year", "ambiguous meter")
+}
@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// savingsPlanRate is the hourly savings plan rate of a VM compute meter for
+// one term.
+type savingsPlanRate struct {
+	Term   string
+	Hourly float64
+}
+
+// savingsPlanRates normalizes the savings plan prices of a VM meter to hourly
+// rates. Savings plan prices share the meter's unit of measure, so they are
+// divided by its packHours like the pay-as-you-go price.
+func savingsPlanRates(meter azureclient.PriceItem, packHours float64) []savingsPlanRate {
+	if packHours <= 0 {
+		return nil
+	}
+	var rates []savingsPlanRate
+	for _, plan := range meter.SavingsPlan {
+		price := plan.RetailPrice
+		if price == 0 {
+			price = plan.UnitPrice
+		}
+		rates = append(rates, savingsPlanRate{Term: strings.TrimSpace(plan.Term), Hourly: price / packHours})
+	}
+	return rates
+}
+
+// savingsPlanEstimates converts hourly savings plan rates to monthly costs,
+// adds licenseMonthly (savings plans cover compute only), and computes the
+// saving against payAsYouGoMonthly. The first rate of each known term is
+// used; terms are ordered shortest first.
+func savingsPlanEstimates(
+	rates []savingsPlanRate,
+	payAsYouGoMonthly float64,
+	licenseMonthly float64,
+) []SavingsPlanEstimate {
+	var plans []SavingsPlanEstimate
+	seen := make(map[string]bool)
+	for _, rate := range rates {
+		term := strings.ToLower(rate.Term)
+		if _, ok := reservationTermMonths[term]; !ok || seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		plan := SavingsPlanEstimate{Term: rate.Term, CostMonthly: rate.Hourly*pluginsdk.HoursPerMonth + licenseMonthly}
+		if payAsYouGoMonthly > 0 {
+			plan.SavingsPercent = (payAsYouGoMonthly - plan.CostMonthly) / payAsYouGoMonthly * 100
+		}
+		plans = append(plans, plan)
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		return reservationTermMonths[strings.ToLower(plans[i].Term)] <
+			reservationTermMonths[strings.ToLower(plans[j].Term)]
+	})
+	return plans
+}
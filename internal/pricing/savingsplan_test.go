@@ -0,0 +1,201 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// newSavingsPlanTestServer serves D2s v3 Linux and Windows consumption meters
+// with savings plan prices, and a 1 year reservation at 40% off.
+func newSavingsPlanTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	consumption := []azureclient.PriceItem{
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series",
+			Type: "Consumption", RetailPrice: 0.1, CurrencyCode: "USD",
+			SavingsPlan: []azureclient.SavingsPlanPrice{
+				{Term: "3 Years", RetailPrice: 0.05},
+				{Term: "1 Year", UnitPrice: 0.07},
+			},
+		},
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows",
+			Type: "Consumption", RetailPrice: 0.18, CurrencyCode: "USD",
+			SavingsPlan: []azureclient.SavingsPlanPrice{{Term: "1 Year", RetailPrice: 0.16}},
+		},
+	}
+	reservation := []azureclient.PriceItem{
+		{ArmSkuName: "Standard_D2s_v3", Type: "Reservation", ReservationTerm: "1 Year", RetailPrice: 525.6},
+	}
+	return newPriceTypeServer(t, consumption, reservation)
+}
+
+func TestEstimate_IncludeReservations_SavingsPlans(t *testing.T) {
+	t.Parallel()
+
+	server := newSavingsPlanTestServer(t)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name string
+		os   string
+		want []SavingsPlanEstimate
+	}{
+		{
+			name: "linux",
+			os:   "Linux",
+			want: []SavingsPlanEstimate{
+				{Term: "1 Year", CostMonthly: 0.07 * 730, SavingsPercent: 30},
+				{Term: "3 Years", CostMonthly: 0.05 * 730, SavingsPercent: 50},
+			},
+		},
+		{
+			// The Linux compute rates apply; the license stays pay-as-you-go.
+			name: "windows",
+			os:   "Windows",
+			want: []SavingsPlanEstimate{
+				{Term: "1 Year", CostMonthly: (0.07 + 0.08) * 730, SavingsPercent: 0.03 / 0.18 * 100},
+				{Term: "3 Years", CostMonthly: (0.05 + 0.08) * 730, SavingsPercent: 0.05 / 0.18 * 100},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+				map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": tc.os, "includeReservations": true}))
+			if err != nil {
+				t.Fatalf("Estimate() failed: %v", err)
+			}
+			if len(estimate.SavingsPlans) != len(tc.want) {
+				t.Fatalf("SavingsPlans = %+v, want %+v", estimate.SavingsPlans, tc.want)
+			}
+			for i, got := range estimate.SavingsPlans {
+				if got.Term != tc.want[i].Term ||
+					math.Abs(got.CostMonthly-tc.want[i].CostMonthly) > 0.000001 ||
+					math.Abs(got.SavingsPercent-tc.want[i].SavingsPercent) > 0.000001 {
+					t.Errorf("SavingsPlans[%d] = %+v, want %+v", i, got, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEstimate_SavingsPlansRequireReservationComparison(t *testing.T) {
+	t.Parallel()
+
+	server := newSavingsPlanTestServer(t)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3"}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+	if len(estimate.SavingsPlans) != 0 || len(estimate.Reservations) != 0 {
+		t.Errorf("commitments = %+v %+v, want none", estimate.Reservations, estimate.SavingsPlans)
+	}
+}
+
+func TestEstimateCost_SendsCommitmentDetail(t *testing.T) {
+	t.Parallel()
+
+	server := newSavingsPlanTestServer(t)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	detail := estimateDetailFromCall(t, calc,
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "include_reservations": "true"},
+		"compute/VirtualMachine")
+
+	if len(detail.Reservations) != 1 || detail.Reservations[0].Term != "1 Year" ||
+		math.Abs(detail.Reservations[0].SavingsPercent-40) > 0.000001 {
+		t.Errorf("Reservations = %+v, want the 1 Year term at 40%%", detail.Reservations)
+	}
+	if len(detail.SavingsPlans) != 2 || detail.SavingsPlans[1].Term != "3 Years" ||
+		math.Abs(detail.SavingsPlans[1].SavingsPercent-50) > 0.000001 {
+		t.Errorf("SavingsPlans = %+v, want 1 Year and 3 Years terms", detail.SavingsPlans)
+	}
+}
+
+func TestSavingsPlanRates_NormalizesPackHours(t *testing.T) {
+	t.Parallel()
+
+	meter := azureclient.PriceItem{SavingsPlan: []azureclient.SavingsPlanPrice{{Term: " 1 Year ", RetailPrice: 5}}}
+	got := savingsPlanRates(meter, 100)
+	if len(got) != 1 || got[0].Term != "1 Year" || got[0].Hourly != 0.05 {
+		t.Errorf("savingsPlanRates() = %+v, want 1 Year at 0.05/hour", got)
+	}
+
+	plans := savingsPlanEstimates([]savingsPlanRate{{Term: "5 Years", Hourly: 1}, got[0], got[0]}, 0, 0)
+	if len(plans) != 1 || plans[0].SavingsPercent != 0 {
+		t.Errorf("savingsPlanEstimates() = %+v, want one 1 Year term", plans)
+	}
+}
+
+func TestEstimate_SavingsPlanAPIVersionOnlyForReservationComparison(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	apiVersions := make(map[string]string)
+	inner := newSavingsPlanTestServer(t)
+	defer inner.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		apiVersions[r.URL.Query().Get("$filter")] = r.URL.Query().Get("api-version")
+		mu.Unlock()
+		http.Redirect(w, r, inner.URL+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	for _, attributes := range []map[string]any{
+		{"region": "eastus", "sku": "Standard_D2s_v3"},
+		{"region": "westus", "sku": "Standard_D2s_v3", "includeReservations": true},
+	} {
+		if _, err := calc.Estimate(context.Background(),
+			newEstimateCostRequest(t, "compute/VirtualMachine", attributes)); err != nil {
+			t.Fatalf("Estimate() failed: %v", err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(apiVersions) != 3 {
+		t.Fatalf("got %d distinct queries, want 3: %v", len(apiVersions), apiVersions)
+	}
+	for filter, version := range apiVersions {
+		wantVersion := ""
+		if strings.Contains(filter, "westus") && !strings.Contains(filter, "Reservation") {
+			wantVersion = azureclient.SavingsPlanAPIVersion
+		}
+		if version != wantVersion {
+			t.Errorf("api-version of %q = %q, want %q", filter, version, wantVersion)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+// maxScaleSetInstances is the largest instance count Azure allows in a single
+// Virtual Machine Scale Set.
+const maxScaleSetInstances = 1000
+
+// Scale set instance count attribute aliases, in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	capacityAttributeKeys    = []string{"capacity", "instances", "instanceCount"}
+	minCapacityAttributeKeys = []string{"minCapacity", "min_capacity"}
+	maxCapacityAttributeKeys = []string{"maxCapacity", "max_capacity"}
+)
+
+// scaleSetBounds holds the instance counts used to price a scale set.
+// Min and Max default to Capacity when no autoscale bounds are given.
+type scaleSetBounds struct {
+	Min      int
+	Capacity int
+	Max      int
+}
+
+// estimateScaleSetCost handles Virtual Machine Scale Set cost estimation.
+// The per-instance price is resolved through the VM lookup and multiplied by
+// the instance counts to produce a low/expected/high monthly range.
+func (c *Calculator) estimateScaleSetCost(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	vm, bounds, err := estimateScaleSetFromRequest(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost scale set validation failed")
+		return nil, err
+	}
+
+	unitPrice, err := c.lookupVMUnitPrice(ctx, vm, resourceType)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceMonthly := unitPrice.Hourly * pluginsdk.HoursPerMonth
+	licenseMonthly := unitPrice.LicenseHourly * pluginsdk.HoursPerMonth * float64(bounds.Capacity)
+	costRange := &CostRange{
+		Low:      instanceMonthly * float64(bounds.Min),
+		Expected: instanceMonthly * float64(bounds.Capacity),
+		High:     instanceMonthly * float64(bounds.Max),
+	}
+
+	log.Info().
+		Str("region", vm.Query.ArmRegionName).
+		Str("sku", vm.Query.ArmSkuName).
+		Str("pricing_model", string(vm.Model)).
+		Str("os", vm.OS).
+		Bool("azure_hybrid_benefit", vm.HybridBenefit).
+		Str("resource_type", resourceType).
+		Int("capacity", bounds.Capacity).
+		Int("min_capacity", bounds.Min).
+		Int("max_capacity", bounds.Max).
+		Float64("cost_monthly", costRange.Expected).
+		Float64("cost_monthly_low", costRange.Low).
+		Float64("cost_monthly_high", costRange.High).
+		Float64("license_cost_monthly", licenseMonthly).
+		Str("currency", unitPrice.Currency).
+		Str("result_status", "success").
+		Msg("EstimateCost scale set completed")
+
+	return &CostEstimate{
+		ResourceType:       routeVirtualMachineScaleSet.canonicalResourceType(),
+		Currency:           unitPrice.Currency,
+		CostMonthly:        costRange.Expected,
+		LicenseCostMonthly: licenseMonthly,
+		Range:              costRange,
+		PricingCategory:    vm.Model.pricingCategory(),
+		LineItems:          unitPrice.lineItems(pluginsdk.HoursPerMonth * float64(bounds.Capacity)),
+	}, nil
+}
+
+// estimateScaleSetFromRequest extracts the per-instance VM pricing and the
+// instance count bounds from an EstimateCostRequest. Returns an error
+// listing all missing fields, or describing invalid instance counts.
+func estimateScaleSetFromRequest(
+	req *finfocusv1.EstimateCostRequest,
+) (vmPricing, scaleSetBounds, error) {
+	attributes := estimateAttributes(req)
+
+	query, missingFields := vmQueryFromAttributes(attributes)
+	capacityStr := firstNonEmptyMapValue(attributes, capacityAttributeKeys...)
+	if capacityStr == "" {
+		missingFields = append(missingFields, "capacity")
+	}
+	if len(missingFields) > 0 {
+		return vmPricing{}, scaleSetBounds{},
+			fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+	}
+
+	if err := normalizeQuery(&query); err != nil {
+		return vmPricing{}, scaleSetBounds{}, err
+	}
+	capacity, err := parseInstanceCount("capacity", capacityStr)
+	if err != nil {
+		return vmPricing{}, scaleSetBounds{}, err
+	}
+	bounds := scaleSetBounds{Min: capacity, Capacity: capacity, Max: capacity}
+
+	if minStr := firstNonEmptyMapValue(attributes, minCapacityAttributeKeys...); minStr != "" {
+		if bounds.Min, err = parseInstanceCount("min_capacity", minStr); err != nil {
+			return vmPricing{}, scaleSetBounds{}, err
+		}
+	}
+	if maxStr := firstNonEmptyMapValue(attributes, maxCapacityAttributeKeys...); maxStr != "" {
+		if bounds.Max, err = parseInstanceCount("max_capacity", maxStr); err != nil {
+			return vmPricing{}, scaleSetBounds{}, err
+		}
+	}
+
+	if bounds.Min > bounds.Capacity {
+		return vmPricing{}, scaleSetBounds{},
+			fmt.Errorf("min_capacity (%d) must not exceed capacity (%d)", bounds.Min, bounds.Capacity)
+	}
+	if bounds.Max < bounds.Capacity {
+		return vmPricing{}, scaleSetBounds{},
+			fmt.Errorf("max_capacity (%d) must not be less than capacity (%d)", bounds.Max, bounds.Capacity)
+	}
+
+	model, err := pricingModelFromAttributes(attributes)
+	if err != nil {
+		return vmPricing{}, scaleSetBounds{}, err
+	}
+	osName, hybridBenefit, err := vmOSFromAttributes(attributes)
+	if err != nil {
+		return vmPricing{}, scaleSetBounds{}, err
+	}
+
+	return vmPricing{Query: query, Model: model, OS: osName, HybridBenefit: hybridBenefit}, bounds, nil
+}
+
+// parseInstanceCount parses and validates a scale set instance count
+// attribute value. Counts must be whole numbers between 0 and
+// maxScaleSetInstances.
+func parseInstanceCount(name, value string) (int, error) {
+	count, err := strconv.ParseFloat(value, 64)
+	if err != nil || count != math.Trunc(count) {
+		return 0, fmt.Errorf("%s must be a whole number: %s", name, value)
+	}
+	if count < 0 || count > maxScaleSetInstances {
+		return 0, fmt.Errorf("%s must be between 0 and %d: %s", name, maxScaleSetInstances, value)
+	}
+	return int(count), nil
+}
+
+// isScaleSetResourceType checks whether the lowercased resource type refers
+// to compute/virtualmachinescaleset as a full segment.
+func isScaleSetResourceType(lower string) bool {
+	return hasResourceTypeSegment(lower, "compute/virtualmachinescaleset")
+}
@@ -0,0 +1,170 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestEstimate_ScaleSet_ReturnsRange(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_D2s_v3", CurrencyCode: "USD", RetailPrice: 0.096},
+	}, &calls)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	req := newEstimateCostRequest(t, "azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet", map[string]any{
+		"location":    "eastus",
+		"sku":         "Standard_D2s_v3",
+		"capacity":    3,
+		"minCapacity": 2,
+		"maxCapacity": 10,
+	})
+
+	estimate, err := calc.Estimate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	instanceMonthly := 0.096 * 730.0
+	if estimate.Range == nil {
+		t.Fatal("expected a cost range for a scale set")
+	}
+	checks := map[string][2]float64{
+		"low":          {estimate.Range.Low, 2 * instanceMonthly},
+		"expected":     {estimate.Range.Expected, 3 * instanceMonthly},
+		"high":         {estimate.Range.High, 10 * instanceMonthly},
+		"cost_monthly": {estimate.CostMonthly, 3 * instanceMonthly},
+	}
+	for name, pair := range checks {
+		if math.Abs(pair[0]-pair[1]) > 0.000001 {
+			t.Errorf("%s = %.6f, want %.6f", name, pair[0], pair[1])
+		}
+	}
+	if estimate.ResourceType != "compute/VirtualMachineScaleSet" {
+		t.Errorf("ResourceType = %q", estimate.ResourceType)
+	}
+
+	resp, err := calc.EstimateCost(context.Background(), req)
+	if err != nil {
+		t.Fatalf("EstimateCost() failed: %v", err)
+	}
+	if math.Abs(resp.GetCostMonthly()-3*instanceMonthly) > 0.000001 {
+		t.Errorf("EstimateCost cost_monthly = %.6f, want expected cost", resp.GetCostMonthly())
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected per-instance price to be served from cache, got %d upstream calls", got)
+	}
+}
+
+func TestEstimate_ScaleSet_DefaultsBoundsToCapacity(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, []azureclient.PriceItem{
+		{ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", CurrencyCode: "USD", RetailPrice: 0.0104},
+	}, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t,
+		"compute/VirtualMachineScaleSet",
+		map[string]any{"region": "eastus", "vmSize": "Standard_B1s", "instances": "4"},
+	))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+	if estimate.Range.Low != estimate.Range.Expected || estimate.Range.High != estimate.Range.Expected {
+		t.Errorf("expected a degenerate range without autoscale bounds, got %+v", estimate.Range)
+	}
+}
+
+func TestEstimateCost_ScaleSet_ValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		substrings []string
+	}{
+		{
+			name:       "missing capacity",
+			attributes: map[string]any{"location": "eastus", "vmSize": "Standard_B1s"},
+			substrings: []string{"missing required field(s): capacity"},
+		},
+		{
+			name:       "missing everything",
+			attributes: map[string]any{},
+			substrings: []string{"region", "sku", "capacity"},
+		},
+		{
+			name:       "fractional capacity",
+			attributes: map[string]any{"location": "eastus", "vmSize": "Standard_B1s", "capacity": 2.5},
+			substrings: []string{"capacity must be a whole number"},
+		},
+		{
+			name:       "negative capacity",
+			attributes: map[string]any{"location": "eastus", "vmSize": "Standard_B1s", "capacity": -1},
+			substrings: []string{"capacity must be between 0 and 1000"},
+		},
+		{
+			name: "min above capacity",
+			attributes: map[string]any{
+				"location": "eastus", "vmSize": "Standard_B1s", "capacity": 2, "minCapacity": 3,
+			},
+			substrings: []string{"min_capacity (3) must not exceed capacity (2)"},
+		},
+		{
+			name: "max below capacity",
+			attributes: map[string]any{
+				"location": "eastus", "vmSize": "Standard_B1s", "capacity": 5, "max_capacity": 4,
+			},
+			substrings: []string{"max_capacity (4) must not be less than capacity (5)"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t,
+				"azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet", tc.attributes))
+			assertStatusCodeContains(t, err, codes.InvalidArgument, tc.substrings...)
+		})
+	}
+}
+
+func TestRouteEstimate_ScaleSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		resourceType string
+		want         estimateRoute
+	}{
+		{"azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet", routeVirtualMachineScaleSet},
+		{"compute/VirtualMachineScaleSet", routeVirtualMachineScaleSet},
+		{"azure:compute/virtualMachine:VirtualMachine", routeVirtualMachine},
+		{"compute/VirtualMachineScaleSetExtension", routeUnsupported},
+	}
+
+	for _, tc := range tests {
+		if got := routeEstimate(tc.resourceType); got != tc.want {
+			t.Errorf("routeEstimate(%q) = %v, want %v", tc.resourceType, got, tc.want)
+		}
+	}
+}
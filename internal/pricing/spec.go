@@ -0,0 +1,453 @@
+package pricing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// pricingSource identifies the Azure Retail Prices API as the origin of a
+// pricing specification or cost result.
+const pricingSource = "azure-retail-prices"
+
+// Attribute key aliases accepted by EstimateCost, in lookup precedence order.
+// The same slices drive attribute extraction and the GetPricingSpec catalog so
+// the advertised aliases can never drift from the accepted ones.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	regionAttributeKeys      = []string{"location", "region"}
+	vmSizeAttributeKeys      = []string{"vmSize", "sku", "armSkuName"}
+	serviceNameAttributeKeys = []string{"serviceName"}
+	productNameAttributeKeys = []string{"productName"}
+	currencyAttributeKeys    = []string{"currencyCode", "currency"}
+	includeUSDAttributeKeys  = []string{"includeUsd", "include_usd"}
+	diskTypeAttributeKeys    = []string{"diskType", "disk_type", "sku"}
+	sizeGBAttributeKeys      = []string{"sizeGb", "size_gb", "diskSizeGb"}
+)
+
+// attributeSpec describes a single EstimateCost attribute for a resource type.
+type attributeSpec struct {
+	// Name is the canonical attribute name used in validation errors.
+	Name string
+	// Keys are the accepted attribute keys, in lookup precedence order.
+	Keys []string
+	// Required reports whether EstimateCost rejects requests without it.
+	Required bool
+	// Description is a short human-readable explanation of the attribute.
+	Description string
+	// Values lists the accepted values for enumerated attributes.
+	Values []string
+}
+
+// resourceSpec describes how a supported resource type is priced.
+type resourceSpec struct {
+	BillingMode       string
+	Unit              string
+	Formula           string
+	Description       string
+	EstimateSupported bool
+	Attributes        []attributeSpec
+	MetricHints       []*finfocusv1.UsageMetricHint
+}
+
+// resourceSpecs is the catalog of pricing specifications keyed by the same
+// normalized resource type identifiers as resourceTypeToService.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var resourceSpecs = map[string]resourceSpec{
+	"compute/virtualmachine": {
+		BillingMode:       "per_hour",
+		Unit:              "hour",
+		Formula:           "cost_monthly = retailPrice * 730",
+		Description:       "Azure Virtual Machines pay-as-you-go compute pricing",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{Name: "sku", Keys: vmSizeAttributeKeys, Required: true, Description: "VM size (armSkuName)"},
+			{
+				Name:        "pricing_model",
+				Keys:        pricingModelAttributeKeys,
+				Description: "Meter family to price (default standard); spot is reported as dynamic pricing",
+				Values:      supportedPricingModelNames,
+			},
+			{
+				Name:        "os",
+				Keys:        osAttributeKeys,
+				Description: "Instance operating system; selects the Windows or Linux meter (default Linux)",
+				Values:      []string{osLinux, osWindows},
+			},
+			{
+				Name:        "azure_hybrid_benefit",
+				Keys:        hybridBenefitAttributeKeys,
+				Description: "Price Windows at the Linux compute rate; licenseType=Windows_Server also enables it",
+			},
+			{
+				Name:        "include_reservations",
+				Keys:        includeReservationsAttributeKeys,
+				Description: "Also price 1- and 3-year reservations and savings plans (monthly cost, savings percent)",
+			},
+			{Name: "service_name", Keys: serviceNameAttributeKeys, Description: "Azure service name override"},
+			{Name: "product_name", Keys: productNameAttributeKeys, Description: "Azure product name filter"},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "vm_hours", Unit: "hour"}},
+	},
+	"compute/virtualmachinescaleset": {
+		BillingMode:       "per_hour",
+		Unit:              "hour",
+		Formula:           "cost_monthly = retailPrice * 730 * capacity",
+		Description:       "Azure Virtual Machine Scale Sets pay-as-you-go compute pricing per instance",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{Name: "sku", Keys: vmSizeAttributeKeys, Required: true, Description: "Instance VM size (armSkuName)"},
+			{Name: "capacity", Keys: capacityAttributeKeys, Required: true, Description: "Expected instance count"},
+			{
+				Name:        "min_capacity",
+				Keys:        minCapacityAttributeKeys,
+				Description: "Autoscale minimum instance count; prices the low end of the range",
+			},
+			{
+				Name:        "max_capacity",
+				Keys:        maxCapacityAttributeKeys,
+				Description: "Autoscale maximum instance count; prices the high end of the range",
+			},
+			{
+				Name:        "pricing_model",
+				Keys:        pricingModelAttributeKeys,
+				Description: "Meter family to price (default standard); spot is reported as dynamic pricing",
+				Values:      supportedPricingModelNames,
+			},
+			{
+				Name:        "os",
+				Keys:        osAttributeKeys,
+				Description: "Instance operating system; selects the Windows or Linux meter (default Linux)",
+				Values:      []string{osLinux, osWindows},
+			},
+			{
+				Name:        "azure_hybrid_benefit",
+				Keys:        hybridBenefitAttributeKeys,
+				Description: "Price Windows at the Linux compute rate; licenseType=Windows_Server also enables it",
+			},
+			{Name: "service_name", Keys: serviceNameAttributeKeys, Description: "Azure service name override"},
+			{Name: "product_name", Keys: productNameAttributeKeys, Description: "Azure product name filter"},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "vm_hours", Unit: "hour"}},
+	},
+	"storage/manageddisk": {
+		BillingMode:       "per_month",
+		Unit:              "month",
+		Formula:           "cost_monthly = retailPrice of the smallest disk tier whose capacity >= size_gb",
+		Description:       "Azure Managed Disks provisioned-tier pricing",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{
+				Name:        "disk_type",
+				Keys:        diskTypeAttributeKeys,
+				Required:    true,
+				Description: "Managed disk type",
+				Values:      supportedDiskTypeNames(),
+			},
+			{Name: "size_gb", Keys: sizeGBAttributeKeys, Required: true, Description: "Provisioned disk size in GiB"},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "storage_gb", Unit: "GB"}},
+	},
+	"containerservice/managedcluster": {
+		BillingMode: "per_hour",
+		Unit:        "hour",
+		Formula: "cost_monthly = sum over node_pools of count * (vm retailPrice * 730 + os disk tier retailPrice)" +
+			" + uptime SLA retailPrice * 730",
+		Description:       "Azure Kubernetes Service node pools and control-plane tier",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{
+				Name:     "node_pools",
+				Keys:     nodePoolsAttributeKeys,
+				Required: true,
+				Description: "List of node pools with vmSize, count, osType (Linux or Windows; default Linux), " +
+					"osDiskType (managed disk type, Managed, or Ephemeral; default Managed) and osDiskSizeGB " +
+					"(default 128)",
+			},
+			{
+				Name:        "sku_tier",
+				Keys:        skuTierAttributeKeys,
+				Description: "Control-plane tier; Standard and Premium add the uptime SLA charge (default Free)",
+				Values:      aksSKUTierNames,
+			},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "vm_hours", Unit: "hour"}},
+	},
+	"web/appserviceplan": {
+		BillingMode:       "per_hour",
+		Unit:              "hour",
+		Formula:           "cost_monthly = plan sku retailPrice * 730 * capacity",
+		Description:       "Azure App Service Plan instance pricing",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{
+				Name:        "sku",
+				Keys:        planSKUAttributeKeys,
+				Required:    true,
+				Description: "Plan SKU such as B1, S1, P1v3, or I1v2",
+			},
+			{Name: "capacity", Keys: planCapacityAttributeKeys, Description: "Instance count (default 1)"},
+			{
+				Name:        "os",
+				Keys:        osAttributeKeys,
+				Description: "Plan operating system; kind=linux or reserved=true also select Linux (default Windows)",
+				Values:      []string{osWindows, osLinux},
+			},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "instance_hours", Unit: "hour"}},
+	},
+	"web/functionapp": {
+		BillingMode: "per_unit",
+		Unit:        "execution",
+		Formula: "cost_monthly = graduated(executions, Total Executions meter) + " +
+			"graduated(gb_seconds, Execution Time meter); the zero-priced first tier is the free grant",
+		Description:       "Azure Functions Consumption plan pricing",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{Name: "executions", Keys: executionsAttributeKeys, Required: true, Description: "Executions per month"},
+			{
+				Name:        "gb_seconds",
+				Keys:        gbSecondsAttributeKeys,
+				Required:    true,
+				Description: "Execution time per month in GB-seconds",
+			},
+			{
+				Name:        "plan",
+				Keys:        planAttributeKeys,
+				Description: "Hosting plan; only Consumption is supported",
+				Values:      []string{"Consumption"},
+			},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{
+			{Metric: "executions", Unit: "count"},
+			{Metric: "gb_seconds", Unit: "GB-second"},
+		},
+	},
+	"storage/blobstorage": {
+		BillingMode: "per_gb_month",
+		Unit:        "GB-month",
+		Formula: "cost_monthly = graduated(capacity_gb, \"<access_tier> <redundancy> Data Stored\" meter); " +
+			"volume tiers start at each price's tierMinimumUnits",
+		Description:       "Azure Storage blob capacity pricing",
+		EstimateSupported: true,
+		Attributes: []attributeSpec{
+			{Name: "region", Keys: regionAttributeKeys, Required: true, Description: "Azure region (armRegionName)"},
+			{Name: "capacity_gb", Keys: capacityGBAttributeKeys, Required: true, Description: "Stored capacity in GB"},
+			{
+				Name:        "redundancy",
+				Keys:        redundancyAttributeKeys,
+				Required:    true,
+				Description: "Replication; derived from an account sku such as Standard_RAGRS when omitted",
+				Values:      storageRedundancyNames,
+			},
+			{
+				Name:        "access_tier",
+				Keys:        accessTierAttributeKeys,
+				Description: "Blob access tier (default Hot)",
+				Values:      storageAccessTierNames,
+			},
+			{Name: "sku", Keys: storageSKUAttributeKeys, Description: "Storage account SKU (e.g., Standard_LRS)"},
+			{
+				Name:        "product_name",
+				Keys:        productNameAttributeKeys,
+				Description: "Azure product name filter (default General Block Blob v2)",
+			},
+			{
+				Name: "currency", Keys: currencyAttributeKeys, Values: azureclient.SupportedCurrencies(),
+				Description: "ISO 4217 currency code (default USD)",
+			},
+			{
+				Name: "include_usd", Keys: includeUSDAttributeKeys,
+				Description: "true to also report USD amounts for a non-USD currency",
+			},
+		},
+		MetricHints: []*finfocusv1.UsageMetricHint{{Metric: "storage_gb", Unit: "GB"}},
+	},
+}
+
+// lookupResourceTypeKey resolves a resource type (either a bare identifier
+// such as "compute/VirtualMachine" or a full Pulumi type token such as
+// "azure:compute/virtualMachine:VirtualMachine") to its normalized catalog key.
+func lookupResourceTypeKey(resourceType string) (string, bool) {
+	lower := strings.ToLower(strings.TrimSpace(resourceType))
+	if lower == "" {
+		return "", false
+	}
+	if _, ok := resourceTypeToService[lower]; ok {
+		return lower, true
+	}
+
+	keys := make([]string, 0, len(resourceTypeToService))
+	for key := range resourceTypeToService {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if hasResourceTypeSegment(lower, key) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// unitOfMeasureAssumption explains how retailPrice relates to the formulas.
+const unitOfMeasureAssumption = "retailPrice is divided by the pack size of its unitOfMeasure " +
+	"(e.g., \"100 Hours\", \"10K Transactions\") and converted to the formula's period using 730 hours per month"
+
+// buildPricingSpec renders the catalog entry for key as a PricingSpec. SKU,
+// region, and currency are echoed from the descriptor so callers can use the
+// spec to validate a concrete resource before requesting an estimate. Returns
+// an error wrapping azureclient.ErrUnsupportedCurrency for an unknown currency.
+func buildPricingSpec(key string, desc *finfocusv1.ResourceDescriptor) (*finfocusv1.PricingSpec, error) {
+	spec := resourceSpecs[key]
+
+	currency, err := currencyFromTags(desc.GetTags())
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]string{
+		"service_name":       resourceTypeToService[key],
+		"pricing_formula":    spec.Formula,
+		"estimate_supported": fmt.Sprintf("%t", spec.EstimateSupported),
+	}
+
+	var required, optional []string
+	assumptions := make([]string, 0, len(spec.Attributes)+2)
+	assumptions = append(assumptions, spec.Formula, unitOfMeasureAssumption)
+	for _, attr := range spec.Attributes {
+		metadata["attribute."+attr.Name+".keys"] = strings.Join(attr.Keys, ",")
+		if len(attr.Values) > 0 {
+			metadata["attribute."+attr.Name+".values"] = strings.Join(attr.Values, ",")
+		}
+		qualifier := "optional"
+		if attr.Required {
+			required = append(required, attr.Name)
+			qualifier = "required"
+		} else {
+			optional = append(optional, attr.Name)
+		}
+		assumptions = append(assumptions, fmt.Sprintf("%s (%s): %s; accepted keys: %s",
+			attr.Name, qualifier, attr.Description, strings.Join(attr.Keys, ", ")))
+	}
+	metadata["required_attributes"] = strings.Join(required, ",")
+	metadata["optional_attributes"] = strings.Join(optional, ",")
+
+	var tiers []*finfocusv1.PricingTier
+	if key == "storage/manageddisk" {
+		tiers = diskPricingTiers(desc.GetSku())
+	}
+
+	return &finfocusv1.PricingSpec{
+		Provider:       "azure",
+		ResourceType:   canonicalResourceTypes[key],
+		Sku:            desc.GetSku(),
+		Region:         desc.GetRegion(),
+		BillingMode:    spec.BillingMode,
+		Currency:       currency,
+		Description:    spec.Description,
+		MetricHints:    spec.MetricHints,
+		PluginMetadata: metadata,
+		Source:         pricingSource,
+		Unit:           spec.Unit,
+		Assumptions:    assumptions,
+		PricingTiers:   tiers,
+	}, nil
+}
+
+// supportedDiskTypeNames returns the accepted disk_type values, sorted.
+func supportedDiskTypeNames() []string {
+	names := make([]string, 0, len(supportedDiskTypes))
+	for name := range supportedDiskTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diskPricingTiers describes the provisioned-capacity bands from
+// diskTierCapacities. Tier names use the descriptor's disk type prefix when it
+// is recognized (e.g., "P10"); otherwise only the tier number is shown. Rates
+// are resolved from the Retail Prices API at estimate time and are left zero.
+func diskPricingTiers(diskType string) []*finfocusv1.PricingTier {
+	prefix := ""
+	if info, err := normalizeDiskType(diskType); err == nil {
+		prefix = info.TierPrefix
+	}
+
+	tiers := make([]*finfocusv1.PricingTier, 0, len(diskTierCapacities))
+	lower := 0
+	for _, tier := range diskTierCapacities {
+		name := fmt.Sprintf("tier %d", tier.Number)
+		if prefix != "" {
+			name = fmt.Sprintf("%s%d", prefix, tier.Number)
+		}
+		tiers = append(tiers, &finfocusv1.PricingTier{
+			MinQuantity: float64(lower),
+			MaxQuantity: float64(tier.Capacity),
+			Description: fmt.Sprintf("%s: up to %d GiB", name, tier.Capacity),
+		})
+		lower = tier.Capacity
+	}
+	return tiers
+}
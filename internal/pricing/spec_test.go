@@ -0,0 +1,216 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetPricingSpec_VirtualMachine(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "azure:compute/virtualMachine:VirtualMachine",
+			Sku:          "Standard_B1s",
+			Region:       "eastus",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetPricingSpec() failed: %v", err)
+	}
+
+	spec := resp.GetSpec()
+	if spec.GetResourceType() != "compute/VirtualMachine" {
+		t.Errorf("ResourceType = %q, want compute/VirtualMachine", spec.GetResourceType())
+	}
+	if spec.GetBillingMode() != "per_hour" {
+		t.Errorf("BillingMode = %q, want per_hour", spec.GetBillingMode())
+	}
+	if spec.GetSku() != "Standard_B1s" || spec.GetRegion() != "eastus" {
+		t.Errorf("expected descriptor SKU/region to be echoed, got %q/%q", spec.GetSku(), spec.GetRegion())
+	}
+	if spec.GetCurrency() != "USD" {
+		t.Errorf("Currency = %q, want USD", spec.GetCurrency())
+	}
+
+	metadata := spec.GetPluginMetadata()
+	wantMetadata := map[string]string{
+		"service_name":                   "Virtual Machines",
+		"required_attributes":            "region,sku",
+		"optional_attributes":            "pricing_model,os,azure_hybrid_benefit,include_reservations,service_name,product_name,currency,include_usd",
+		"attribute.region.keys":          "location,region",
+		"attribute.sku.keys":             "vmSize,sku,armSkuName",
+		"attribute.pricing_model.values": "standard,spot,low_priority",
+		"estimate_supported":             "true",
+		"pricing_formula":                "cost_monthly = retailPrice * 730",
+	}
+	for key, want := range wantMetadata {
+		if got := metadata[key]; got != want {
+			t.Errorf("metadata[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestGetPricingSpec_ManagedDisk(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "storage/ManagedDisk",
+			Sku:          "Premium_SSD_LRS",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetPricingSpec() failed: %v", err)
+	}
+
+	spec := resp.GetSpec()
+	if spec.GetBillingMode() != "per_month" {
+		t.Errorf("BillingMode = %q, want per_month", spec.GetBillingMode())
+	}
+
+	metadata := spec.GetPluginMetadata()
+	if got := metadata["required_attributes"]; got != "region,disk_type,size_gb" {
+		t.Errorf("required_attributes = %q", got)
+	}
+	if got := metadata["attribute.size_gb.keys"]; got != "sizeGb,size_gb,diskSizeGb" {
+		t.Errorf("attribute.size_gb.keys = %q", got)
+	}
+	for name := range supportedDiskTypes {
+		if !strings.Contains(metadata["attribute.disk_type.values"], name) {
+			t.Errorf("disk_type values %q missing %q", metadata["attribute.disk_type.values"], name)
+		}
+	}
+
+	tiers := spec.GetPricingTiers()
+	if len(tiers) != len(diskTierCapacities) {
+		t.Fatalf("expected %d tiers, got %d", len(diskTierCapacities), len(tiers))
+	}
+	if !strings.HasPrefix(tiers[5].GetDescription(), "P10:") || tiers[5].GetMaxQuantity() != 128 {
+		t.Errorf("unexpected P10 tier: %v", tiers[5])
+	}
+}
+
+func TestGetPricingSpec_ScaleSetIsNotVirtualMachine(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+		Resource: &finfocusv1.ResourceDescriptor{
+			Provider:     "azure",
+			ResourceType: "azure:compute/virtualMachineScaleSet:VirtualMachineScaleSet",
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetPricingSpec() failed: %v", err)
+	}
+
+	spec := resp.GetSpec()
+	if spec.GetResourceType() != "compute/VirtualMachineScaleSet" {
+		t.Errorf("ResourceType = %q, want compute/VirtualMachineScaleSet", spec.GetResourceType())
+	}
+	metadata := spec.GetPluginMetadata()
+	if got := metadata["required_attributes"]; got != "region,sku,capacity" {
+		t.Errorf("required_attributes = %q", got)
+	}
+	if got := metadata["optional_attributes"]; !strings.HasPrefix(got, "min_capacity,max_capacity") {
+		t.Errorf("optional_attributes = %q, want autoscale bounds first", got)
+	}
+}
+
+func TestGetPricingSpec_ManagedCluster(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+		Resource: &finfocusv1.ResourceDescriptor{Provider: "azure", ResourceType: "containerservice/ManagedCluster"},
+	})
+	if err != nil {
+		t.Fatalf("GetPricingSpec() failed: %v", err)
+	}
+
+	metadata := resp.GetSpec().GetPluginMetadata()
+	wantMetadata := map[string]string{
+		"service_name":              "Azure Kubernetes Service",
+		"required_attributes":       "region,node_pools",
+		"attribute.sku_tier.values": "Free,Standard,Premium",
+		"attribute.node_pools.keys": "agentPoolProfiles,nodePools,node_pools",
+	}
+	for key, want := range wantMetadata {
+		if got := metadata[key]; got != want {
+			t.Errorf("metadata[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestGetPricingSpec_BlobStorageEstimable(t *testing.T) {
+	t.Parallel()
+
+	calc := NewCalculator(zerolog.Nop())
+	resp, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+		Resource: &finfocusv1.ResourceDescriptor{Provider: "azure", ResourceType: "storage/BlobStorage"},
+	})
+	if err != nil {
+		t.Fatalf("GetPricingSpec() failed: %v", err)
+	}
+	metadata := resp.GetSpec().GetPluginMetadata()
+	if got := metadata["estimate_supported"]; got != "true" {
+		t.Errorf("estimate_supported = %q, want true", got)
+	}
+	if got := metadata["attribute.access_tier.values"]; got != "Hot,Cool,Cold,Archive" {
+		t.Errorf("attribute.access_tier.values = %q", got)
+	}
+}
+
+func TestGetPricingSpec_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resource *finfocusv1.ResourceDescriptor
+		wantCode codes.Code
+	}{
+		{name: "nil resource", resource: nil, wantCode: codes.InvalidArgument},
+		{
+			name:     "non-azure provider",
+			resource: &finfocusv1.ResourceDescriptor{Provider: "aws", ResourceType: "compute/VirtualMachine"},
+			wantCode: codes.Unimplemented,
+		},
+		{
+			name:     "unsupported resource type",
+			resource: &finfocusv1.ResourceDescriptor{Provider: "azure", ResourceType: "network/LoadBalancer"},
+			wantCode: codes.Unimplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			calc := NewCalculator(zerolog.Nop())
+			_, err := calc.GetPricingSpec(context.Background(), &finfocusv1.GetPricingSpecRequest{
+				Resource: tc.resource,
+			})
+			assertStatusCodeContains(t, err, tc.wantCode)
+		})
+	}
+}
+
+func TestResourceSpecsCoverResourceTypeToService(t *testing.T) {
+	t.Parallel()
+
+	for key := range resourceTypeToService {
+		if _, ok := resourceSpecs[key]; !ok {
+			t.Errorf("resource type %q has no pricing spec", key)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+	"github.com/rshade/finfocus-plugin-azure-public/internal/logging"
+)
+
+const (
+	// storageServiceName is the Retail Prices serviceName of Azure Storage.
+	storageServiceName = "Storage"
+	// defaultStorageProductName is the general-purpose v2 block blob product,
+	// which carries the Hot/Cool/Cold/Archive capacity meters.
+	defaultStorageProductName = "General Block Blob v2"
+	// defaultAccessTier is the access tier Azure assigns to new accounts.
+	defaultAccessTier = "Hot"
+)
+
+// Storage attribute aliases, in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	capacityGBAttributeKeys = []string{"capacityGb", "capacity_gb", "storageGb", "storage_gb"}
+	accessTierAttributeKeys = []string{"accessTier", "access_tier"}
+	redundancyAttributeKeys = []string{"redundancy", "replication", "accountReplicationType"}
+	storageSKUAttributeKeys = []string{"sku", "skuName", "sku_name"}
+)
+
+// storageAccessTiers maps lowercased access tiers to their meter prefix.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var storageAccessTiers = map[string]string{
+	"hot":     "Hot",
+	"cool":    "Cool",
+	"cold":    "Cold",
+	"archive": "Archive",
+}
+
+// storageRedundancies maps redundancy codes (uppercased, hyphens removed) to
+// their meter form.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var storageRedundancies = map[string]string{
+	"LRS":    "LRS",
+	"ZRS":    "ZRS",
+	"GRS":    "GRS",
+	"RAGRS":  "RA-GRS",
+	"GZRS":   "GZRS",
+	"RAGZRS": "RA-GZRS",
+}
+
+// Display orders for the accepted access tiers and redundancies.
+//
+//nolint:gochecknoglobals // Static lookup tables; immutable after init.
+var (
+	storageAccessTierNames = []string{"Hot", "Cool", "Cold", "Archive"}
+	storageRedundancyNames = []string{"LRS", "ZRS", "GRS", "RA-GRS", "GZRS", "RA-GZRS"}
+)
+
+// storageCapacity is a validated storage capacity description.
+type storageCapacity struct {
+	Query      azureclient.PriceQuery
+	CapacityGB float64
+	AccessTier string
+	Redundancy string
+}
+
+// meterName returns the capacity meter, e.g. "Hot LRS Data Stored".
+func (s storageCapacity) meterName() string {
+	return s.AccessTier + " " + s.Redundancy + " Data Stored"
+}
+
+// estimateStorageCost handles storage account capacity estimation. The
+// "<Tier> <Redundancy> Data Stored" meter is priced through its volume tiers
+// (first 50 TB, next 450 TB, ...) rather than a single flat rate.
+func (c *Calculator) estimateStorageCost(
+	ctx context.Context,
+	req *finfocusv1.EstimateCostRequest,
+	resourceType string,
+) (*CostEstimate, error) {
+	log := logging.RequestLogger(ctx, c.logger)
+
+	storage, err := estimateStorageFromRequest(req)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, err.Error())
+		log.Warn().
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost storage validation failed")
+		return nil, err
+	}
+
+	if c.cachedClient == nil {
+		unimplementedErr := status.Error(codes.Unimplemented, "not yet implemented")
+		log.Warn().
+			Str("region", storage.Query.ArmRegionName).
+			Str("meter", storage.meterName()).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(unimplementedErr).
+			Msg("EstimateCost unavailable")
+		return nil, unimplementedErr
+	}
+
+	result, err := c.cachedClient.GetPrices(ctx, storage.Query)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", storage.Query.ArmRegionName).
+			Str("meter", storage.meterName()).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost storage pricing lookup failed")
+		return nil, err
+	}
+
+	charge, err := graduatedMeterCharge(result.Items, storage.meterName(), storage.CapacityGB)
+	if err != nil {
+		err = MapToGRPCStatus(err).Err()
+		log.Error().
+			Str("region", storage.Query.ArmRegionName).
+			Str("meter", storage.meterName()).
+			Str("resource_type", resourceType).
+			Str("result_status", "error").
+			Err(err).
+			Msg("EstimateCost storage meter lookup failed")
+		return nil, err
+	}
+
+	log.Info().
+		Str("region", storage.Query.ArmRegionName).
+		Str("meter", storage.meterName()).
+		Float64("capacity_gb", storage.CapacityGB).
+		Str("resource_type", resourceType).
+		Float64("cost_monthly", charge.Cost).
+		Str("currency", charge.Currency).
+		Str("result_status", "success").
+		Msg("EstimateCost storage completed")
+
+	return &CostEstimate{
+		ResourceType:    routeStorage.canonicalResourceType(),
+		Currency:        charge.Currency,
+		CostMonthly:     charge.Cost,
+		PricingCategory: finfocusv1.FocusPricingCategory_FOCUS_PRICING_CATEGORY_STANDARD,
+		LineItems:       charge.LineItems,
+	}, nil
+}
+
+// estimateStorageFromRequest extracts and validates storage capacity
+// attributes. Redundancy may be given directly or derived from an account SKU
+// such as "Standard_RAGRS"; the access tier defaults to Hot.
+func estimateStorageFromRequest(req *finfocusv1.EstimateCostRequest) (storageCapacity, error) {
+	attributes := estimateAttributes(req)
+
+	region := firstNonEmptyMapValue(attributes, regionAttributeKeys...)
+	capacityStr := firstNonEmptyMapValue(attributes, capacityGBAttributeKeys...)
+	redundancy := firstNonEmptyMapValue(attributes, redundancyAttributeKeys...)
+	if redundancy == "" {
+		if sku := firstNonEmptyMapValue(attributes, storageSKUAttributeKeys...); sku != "" {
+			_, redundancy, _ = strings.Cut(sku, "_")
+		}
+	}
+
+	var missingFields []string
+	if region == "" {
+		missingFields = append(missingFields, "region")
+	}
+	if capacityStr == "" {
+		missingFields = append(missingFields, "capacity_gb")
+	}
+	if redundancy == "" {
+		missingFields = append(missingFields, "redundancy")
+	}
+	if len(missingFields) > 0 {
+		return storageCapacity{}, fmt.Errorf("missing required field(s): %s", strings.Join(missingFields, ", "))
+	}
+
+	region, err := azureclient.NormalizeRegion(region)
+	if err != nil {
+		return storageCapacity{}, err
+	}
+	capacityGB, err := parseUsageQuantity("capacity_gb", capacityStr)
+	if err != nil {
+		return storageCapacity{}, err
+	}
+	canonicalRedundancy, ok := storageRedundancies[strings.ReplaceAll(strings.ToUpper(redundancy), "-", "")]
+	if !ok {
+		return storageCapacity{}, fmt.Errorf("unsupported redundancy: %s (supported: %s)",
+			redundancy, strings.Join(storageRedundancyNames, ", "))
+	}
+	accessTier := defaultAccessTier
+	if tier := firstNonEmptyMapValue(attributes, accessTierAttributeKeys...); tier != "" {
+		if accessTier, ok = storageAccessTiers[strings.ToLower(tier)]; !ok {
+			return storageCapacity{}, fmt.Errorf("unsupported access tier: %s (supported: %s)",
+				tier, strings.Join(storageAccessTierNames, ", "))
+		}
+	}
+
+	query := azureclient.PriceQuery{
+		ArmRegionName: region,
+		ServiceName:   storageServiceName,
+		ProductName:   firstNonEmptyMapValue(attributes, productNameAttributeKeys...),
+	}
+	if query.ProductName == "" {
+		query.ProductName = defaultStorageProductName
+	}
+	if query.CurrencyCode, err = currencyFromAttributes(attributes); err != nil {
+		return storageCapacity{}, err
+	}
+
+	return storageCapacity{
+		Query:      query,
+		CapacityGB: capacityGB,
+		AccessTier: accessTier,
+		Redundancy: canonicalRedundancy,
+	}, nil
+}
+
+// isBlobStorageResourceType checks whether the lowercased resource type
+// refers to storage/blobstorage as a full segment.
+func isBlobStorageResourceType(lower string) bool {
+	return hasResourceTypeSegment(lower, "storage/blobstorage")
+}
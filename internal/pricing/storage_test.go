@@ -0,0 +1,196 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// storageTestPriceItems returns the Hot LRS volume tiers (first 50 TB, next
+// 450 TB, over 500 TB) alongside meters the estimate must not pick up.
+func storageTestPriceItems() []azureclient.PriceItem {
+	item := func(meter string, minimum, price float64) azureclient.PriceItem {
+		return azureclient.PriceItem{
+			ServiceName: storageServiceName, ProductName: defaultStorageProductName, MeterName: meter,
+			UnitOfMeasure: "1 GB/Month", TierMinimumUnits: minimum, RetailPrice: price, CurrencyCode: "USD",
+		}
+	}
+	return []azureclient.PriceItem{
+		item("Hot LRS Write Operations", 0, 0.055),
+		item("Hot LRS Data Stored", 512000, 0.0166),
+		item("Hot LRS Data Stored", 0, 0.0184),
+		item("Hot LRS Data Stored", 51200, 0.0177),
+		item("Hot RA-GRS Data Stored", 0, 0.046),
+		item("Cool LRS Data Stored", 0, 0.01),
+	}
+}
+
+func TestEstimateCost_Storage_AppliesVolumeTiers(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, storageTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		want       float64
+	}{
+		{
+			name:       "zero capacity",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 0, "redundancy": "LRS"},
+			want:       0,
+		},
+		{
+			name:       "within first tier",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 1000, "redundancy": "LRS"},
+			want:       1000 * 0.0184,
+		},
+		{
+			name:       "spans all tiers",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 600000, "redundancy": "lrs"},
+			want:       51200*0.0184 + (512000-51200)*0.0177 + (600000-512000)*0.0166,
+		},
+		{
+			name: "redundancy from account sku",
+			attributes: map[string]any{
+				"location": "eastus", "capacityGb": 100, "sku": "Standard_RAGRS", "accessTier": "hot",
+			},
+			want: 100 * 0.046,
+		},
+		{
+			name:       "cool tier",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 100, "redundancy": "LRS", "access_tier": "Cool"},
+			want:       100 * 0.01,
+		},
+	}
+
+	for _, tc := range tests {
+		resp, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, "storage/BlobStorage", tc.attributes))
+		if err != nil {
+			t.Fatalf("%s: EstimateCost() failed: %v", tc.name, err)
+		}
+		if math.Abs(resp.GetCostMonthly()-tc.want) > 0.000001 {
+			t.Errorf("%s: CostMonthly = %.6f, want %.6f", tc.name, resp.GetCostMonthly(), tc.want)
+		}
+	}
+}
+
+func TestEstimate_Storage_LineItemPerTier(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, storageTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "storage/BlobStorage",
+		map[string]any{"region": "eastus", "capacity_gb": 60000, "redundancy": "LRS"}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	assertLineItems(t, estimate, []LineItem{
+		{
+			Description: "tier from 0 units", MeterName: "Hot LRS Data Stored", UnitOfMeasure: "1 GB/Month",
+			UnitPrice: 0.0184, Quantity: 51200, CostMonthly: 51200 * 0.0184,
+		},
+		{
+			Description: "tier from 51200 units", MeterName: "Hot LRS Data Stored", UnitOfMeasure: "1 GB/Month",
+			UnitPrice: 0.0177, Quantity: 60000 - 51200, CostMonthly: (60000 - 51200) * 0.0177,
+		},
+	})
+}
+
+func TestEstimateCost_Storage_MissingMeter_ReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, storageTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, "storage/BlobStorage",
+		map[string]any{"region": "eastus", "capacity_gb": 10, "redundancy": "GZRS", "access_tier": "Archive"}))
+	assertStatusCodeContains(t, err, codes.NotFound, "Archive GZRS Data Stored")
+}
+
+func TestEstimateStorageFromRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		wantMeter  string
+		wantErr    string
+	}{
+		{
+			name:       "defaults to hot",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 1, "redundancy": "ra-gzrs"},
+			wantMeter:  "Hot RA-GZRS Data Stored",
+		},
+		{
+			name:       "sku without redundancy suffix",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 1, "sku": "Standard"},
+			wantErr:    "missing required field(s): redundancy",
+		},
+		{
+			name:       "missing fields",
+			attributes: map[string]any{},
+			wantErr:    "missing required field(s): region, capacity_gb, redundancy",
+		},
+		{
+			name:       "negative capacity",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": -5, "redundancy": "LRS"},
+			wantErr:    "capacity_gb must not be negative",
+		},
+		{
+			name:       "unknown redundancy",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 1, "redundancy": "XRS"},
+			wantErr:    "unsupported redundancy: XRS",
+		},
+		{
+			name:       "unknown access tier",
+			attributes: map[string]any{"region": "eastus", "capacity_gb": 1, "redundancy": "LRS", "access_tier": "Warm"},
+			wantErr:    "unsupported access tier: Warm",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			storage, err := estimateStorageFromRequest(newEstimateCostRequest(t, "storage/BlobStorage", tc.attributes))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := storage.meterName(); got != tc.wantMeter {
+				t.Errorf("meterName() = %q, want %q", got, tc.wantMeter)
+			}
+			if storage.Query.ServiceName != storageServiceName || storage.Query.ProductName != defaultStorageProductName {
+				t.Errorf("query = %+v, want Storage / %s", storage.Query, defaultStorageProductName)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// tieredCharge is the graduated cost of a usage quantity on one meter.
+type tieredCharge struct {
+	Cost     float64
+	Currency string
+	// FreeUnits is the usage covered by zero-priced leading tiers, or by the
+	// gap below the first tier's minimum, in billing units.
+	FreeUnits float64
+	// Tiers has one entry per tier the usage reaches, lowest first.
+	Tiers []tierCharge
+}
+
+// tierCharge is the part of a usage quantity billed at one tier's rate.
+type tierCharge struct {
+	// Meter is the price item of the tier.
+	Meter azureclient.PriceItem
+	// MinimumUnits and MaximumUnits bound the tier in billing units; the last
+	// tier is unbounded (+Inf).
+	MinimumUnits float64
+	MaximumUnits float64
+	UnitPrice    float64
+	// Quantity is the usage billed in this tier, in billing units.
+	Quantity float64
+	Cost     float64
+}
+
+// tieredRate prices quantity, in billing units, against the graduated tiers
+// of a single meter. Each tier applies from its TierMinimumUnits up to the
+// next tier's minimum; usage below the lowest minimum is free. Items for the
+// same minimum are narrowed like selectMeter (primary meter region, then the
+// latest EffectiveStartDate) and must then agree on price.
+//
+// Returns azureclient.ErrNotFound without items and ErrAmbiguousMeter when
+// the tiers disagree on price, currency, or unit of measure.
+func tieredRate(items []azureclient.PriceItem, quantity float64) (tieredCharge, error) {
+	if quantity < 0 || math.IsNaN(quantity) {
+		return tieredCharge{}, fmt.Errorf("usage quantity must not be negative: %g", quantity)
+	}
+	tiers, err := rateTiers(items)
+	if err != nil {
+		return tieredCharge{}, err
+	}
+
+	_, currency := itemPriceAndCurrency(tiers[0])
+	charge := tieredCharge{Currency: currency, FreeUnits: tiers[0].TierMinimumUnits}
+	for i, tier := range tiers {
+		upper := math.Inf(1)
+		if i+1 < len(tiers) {
+			upper = tiers[i+1].TierMinimumUnits
+		}
+		price, _ := itemPriceAndCurrency(tier)
+		if price == 0 && charge.FreeUnits == tier.TierMinimumUnits {
+			charge.FreeUnits = upper
+		}
+		if quantity <= tier.TierMinimumUnits {
+			continue
+		}
+		billed := math.Min(quantity, upper) - tier.TierMinimumUnits
+		charge.Tiers = append(charge.Tiers, tierCharge{
+			Meter:        tier,
+			MinimumUnits: tier.TierMinimumUnits,
+			MaximumUnits: upper,
+			UnitPrice:    price,
+			Quantity:     billed,
+			Cost:         price * billed,
+		})
+		charge.Cost += price * billed
+	}
+	charge.FreeUnits = math.Min(quantity, charge.FreeUnits)
+	return charge, nil
+}
+
+// rateTiers returns one price item per tier minimum, sorted by minimum.
+func rateTiers(items []azureclient.PriceItem) ([]azureclient.PriceItem, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no tier prices found: %w", azureclient.ErrNotFound)
+	}
+
+	byMinimum := make(map[float64][]azureclient.PriceItem)
+	for _, item := range items {
+		byMinimum[item.TierMinimumUnits] = append(byMinimum[item.TierMinimumUnits], item)
+	}
+
+	tiers := make([]azureclient.PriceItem, 0, len(byMinimum))
+	for minimum, candidates := range byMinimum {
+		candidates = preferLatestEffectiveStart(preferPrimaryMeterRegion(candidates))
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].MeterID < candidates[j].MeterID })
+		price, currency := itemPriceAndCurrency(candidates[0])
+		for _, candidate := range candidates[1:] {
+			otherPrice, otherCurrency := itemPriceAndCurrency(candidate)
+			if otherPrice != price || otherCurrency != currency {
+				return nil, fmt.Errorf("%w: %d prices for the tier from %g units",
+					ErrAmbiguousMeter, len(candidates), minimum)
+			}
+		}
+		tiers = append(tiers, candidates[0])
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].TierMinimumUnits < tiers[j].TierMinimumUnits })
+
+	_, currency := itemPriceAndCurrency(tiers[0])
+	for _, tier := range tiers[1:] {
+		if _, otherCurrency := itemPriceAndCurrency(tier); otherCurrency != currency {
+			return nil, fmt.Errorf("%w: tiers are priced in both %s and %s", ErrAmbiguousMeter, currency, otherCurrency)
+		}
+		if !strings.EqualFold(strings.TrimSpace(tier.UnitOfMeasure), strings.TrimSpace(tiers[0].UnitOfMeasure)) {
+			return nil, fmt.Errorf("%w: tiers are measured in both %q and %q",
+				ErrAmbiguousMeter, tiers[0].UnitOfMeasure, tier.UnitOfMeasure)
+		}
+	}
+	return tiers, nil
+}
+
+// lineItems returns one line item per tier reached.
+func (c tieredCharge) lineItems() []LineItem {
+	lineItems := make([]LineItem, 0, len(c.Tiers))
+	for _, tier := range c.Tiers {
+		lineItems = append(lineItems,
+			newLineItem(tierDescription(tier.MinimumUnits, tier.UnitPrice), tier.Meter, tier.UnitPrice, tier.Quantity))
+	}
+	return lineItems
+}
+
+// tierDescription describes a graduated tier for line items.
+func tierDescription(minimumUnits, price float64) string {
+	if price == 0 {
+		return "free grant"
+	}
+	return fmt.Sprintf("tier from %g units", minimumUnits)
+}
@@ -0,0 +1,143 @@
+package pricing
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// bandwidthTiers mirrors the per-GB internet egress meter: the first 100 GB
+// are free, then the rate drops with volume.
+func bandwidthTiers() []azureclient.PriceItem {
+	tier := func(minimum, price float64) azureclient.PriceItem {
+		return azureclient.PriceItem{
+			MeterName: "Standard Data Transfer Out", UnitOfMeasure: "1 GB",
+			TierMinimumUnits: minimum, RetailPrice: price, CurrencyCode: "USD",
+		}
+	}
+	// Deliberately out of order.
+	return []azureclient.PriceItem{tier(10340, 0.083), tier(0, 0), tier(100, 0.087), tier(51300, 0.07)}
+}
+
+func TestTieredRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		quantity  float64
+		wantCost  float64
+		wantFree  float64
+		wantTiers []float64 // billed quantity per tier reached
+	}{
+		{name: "zero", quantity: 0, wantCost: 0, wantFree: 0},
+		{name: "within_free_tier", quantity: 60, wantCost: 0, wantFree: 60, wantTiers: []float64{60}},
+		{name: "tier_boundary", quantity: 100, wantCost: 0, wantFree: 100, wantTiers: []float64{100}},
+		{
+			name: "second_tier", quantity: 1100, wantCost: 1000 * 0.087, wantFree: 100,
+			wantTiers: []float64{100, 1000},
+		},
+		{
+			name: "last_tier", quantity: 60000, wantCost: 10240*0.087 + 40960*0.083 + 8700*0.07, wantFree: 100,
+			wantTiers: []float64{100, 10240, 40960, 8700},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			charge, err := tieredRate(bandwidthTiers(), tt.quantity)
+			if err != nil {
+				t.Fatalf("tieredRate() error = %v", err)
+			}
+			if math.Abs(charge.Cost-tt.wantCost) > 1e-9 || charge.FreeUnits != tt.wantFree || charge.Currency != "USD" {
+				t.Errorf("charge = %+v, want cost %v, free %v", charge, tt.wantCost, tt.wantFree)
+			}
+			if len(charge.Tiers) != len(tt.wantTiers) {
+				t.Fatalf("got %d tiers, want %d: %+v", len(charge.Tiers), len(tt.wantTiers), charge.Tiers)
+			}
+			var total float64
+			for i, want := range tt.wantTiers {
+				if charge.Tiers[i].Quantity != want {
+					t.Errorf("Tiers[%d].Quantity = %v, want %v", i, charge.Tiers[i].Quantity, want)
+				}
+				total += charge.Tiers[i].Cost
+			}
+			if math.Abs(total-charge.Cost) > 1e-9 {
+				t.Errorf("tier costs add up to %v, want %v", total, charge.Cost)
+			}
+			if n := len(charge.Tiers); n == len(bandwidthTiers()) && !math.IsInf(charge.Tiers[n-1].MaximumUnits, 1) {
+				t.Errorf("last tier MaximumUnits = %v, want +Inf", charge.Tiers[n-1].MaximumUnits)
+			}
+		})
+	}
+}
+
+func TestTieredRate_ImpliedFreeGrant(t *testing.T) {
+	t.Parallel()
+
+	items := []azureclient.PriceItem{{UnitOfMeasure: "1 GB", TierMinimumUnits: 5, RetailPrice: 0.02}}
+	charge, err := tieredRate(items, 15)
+	if err != nil {
+		t.Fatalf("tieredRate() error = %v", err)
+	}
+	if charge.FreeUnits != 5 || math.Abs(charge.Cost-0.2) > 1e-12 || charge.Currency != defaultCurrency {
+		t.Errorf("charge = %+v, want 5 free units and cost 0.2", charge)
+	}
+}
+
+func TestTieredRate_DuplicateTiers(t *testing.T) {
+	t.Parallel()
+
+	newer := azureclient.PriceItem{
+		UnitOfMeasure: "1 GB", RetailPrice: 0.02, EffectiveStartDate: "2024-01-01T00:00:00Z", MeterID: "b",
+	}
+	older := newer
+	older.RetailPrice, older.EffectiveStartDate = 0.03, "2020-01-01T00:00:00Z"
+	charge, err := tieredRate([]azureclient.PriceItem{older, newer}, 10)
+	if err != nil {
+		t.Fatalf("tieredRate() error = %v", err)
+	}
+	if math.Abs(charge.Cost-0.2) > 1e-12 {
+		t.Errorf("Cost = %v, want the latest price applied", charge.Cost)
+	}
+
+	conflicting := newer
+	conflicting.RetailPrice, conflicting.MeterID = 0.05, "a"
+	if _, err = tieredRate([]azureclient.PriceItem{newer, conflicting}, 10); !errors.Is(err, ErrAmbiguousMeter) {
+		t.Errorf("tieredRate() error = %v, want ErrAmbiguousMeter", err)
+	}
+}
+
+func TestTieredRate_Errors(t *testing.T) {
+	t.Parallel()
+
+	mixedUnits := bandwidthTiers()
+	mixedUnits[2].UnitOfMeasure = "100 GB"
+	mixedCurrency := bandwidthTiers()
+	mixedCurrency[0].CurrencyCode = "EUR"
+
+	tests := []struct {
+		name     string
+		items    []azureclient.PriceItem
+		quantity float64
+		want     error
+	}{
+		{name: "no_items", quantity: 1, want: azureclient.ErrNotFound},
+		{name: "mixed_units", items: mixedUnits, quantity: 1, want: ErrAmbiguousMeter},
+		{name: "mixed_currency", items: mixedCurrency, quantity: 1, want: ErrAmbiguousMeter},
+		{name: "negative_quantity", items: bandwidthTiers(), quantity: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tieredRate(tt.items, tt.quantity)
+			if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Errorf("tieredRate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
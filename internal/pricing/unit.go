@@ -0,0 +1,206 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rshade/finfocus-spec/sdk/go/pluginsdk"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// ErrUnsupportedUnitOfMeasure is returned when a price item's UnitOfMeasure
+// cannot be parsed or does not fit the calculation (e.g., a per-GB price where
+// an hourly rate is expected).
+// Maps to gRPC codes.FailedPrecondition via MapToGRPCStatus.
+var ErrUnsupportedUnitOfMeasure = errors.New("unsupported unit of measure")
+
+// unitPeriod is the span of time one unit of a price covers.
+type unitPeriod string
+
+const (
+	// periodNone marks usage units ("10K Transactions", "1 GB Second") that
+	// are not billed over time.
+	periodNone  unitPeriod = ""
+	periodHour  unitPeriod = "hour"
+	periodDay   unitPeriod = "day"
+	periodMonth unitPeriod = "month"
+	periodYear  unitPeriod = "year"
+)
+
+// periodHours maps periods to their length in hours, with a month of
+// pluginsdk.HoursPerMonth (730) hours.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var periodHours = map[unitPeriod]float64{
+	periodHour:  1,
+	periodDay:   24,
+	periodMonth: pluginsdk.HoursPerMonth,
+	periodYear:  12 * pluginsdk.HoursPerMonth,
+}
+
+// unitPeriodNames maps lowercased period words to periods.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var unitPeriodNames = map[string]unitPeriod{
+	"hour": periodHour, "hours": periodHour, "hr": periodHour, "hrs": periodHour,
+	"day": periodDay, "days": periodDay,
+	"month": periodMonth, "months": periodMonth,
+	"year": periodYear, "years": periodYear,
+}
+
+// unitOfMeasure is a parsed Retail Prices unit of measure. A price applies to
+// Multiplier units of Dimension, each covering Period:
+//
+//	"1 Hour"           → {"", 1, hour}: one instance-hour
+//	"100 Hours"        → {"", 100, hour}
+//	"1/Month"          → {"", 1, month}: one instance for a month
+//	"1 GB/Month"       → {"gb", 1, month}
+//	"10K Transactions" → {"transactions", 10000, none}
+type unitOfMeasure struct {
+	// Dimension is the lowercased quantity counted (e.g., "gb",
+	// "transactions"). Empty for plain counts of instances or time.
+	Dimension string
+	// Multiplier is the pack size the price covers.
+	Multiplier float64
+	// Period is the time one unit covers; periodNone for usage units.
+	Period unitPeriod
+}
+
+// parseUnitOfMeasure parses a Retail Prices unit of measure: a pack size with
+// an optional K (thousand) or M (million) suffix, an optional dimension, and
+// an optional "/period". A dimension that is itself a period ("Hour",
+// "Days") makes the unit a time unit.
+func parseUnitOfMeasure(text string) (unitOfMeasure, error) {
+	left, right, hasPeriod := strings.Cut(strings.TrimSpace(text), "/")
+	fields := strings.Fields(left)
+	if len(fields) == 0 {
+		return unitOfMeasure{}, fmt.Errorf("%w: %q", ErrUnsupportedUnitOfMeasure, text)
+	}
+
+	// The pack size may be glued to the dimension or the slash ("1/Month").
+	multiplier, err := parsePackSize(fields[0])
+	if err != nil {
+		return unitOfMeasure{}, fmt.Errorf("%w: %q has no pack size", ErrUnsupportedUnitOfMeasure, text)
+	}
+	unit := unitOfMeasure{
+		Dimension:  strings.ToLower(strings.Join(fields[1:], " ")),
+		Multiplier: multiplier,
+	}
+
+	if hasPeriod {
+		period, ok := unitPeriodNames[strings.ToLower(strings.TrimSpace(right))]
+		if !ok {
+			return unitOfMeasure{}, fmt.Errorf("%w: %q has unknown period %q", ErrUnsupportedUnitOfMeasure, text, right)
+		}
+		unit.Period = period
+	} else if period, ok := unitPeriodNames[unit.Dimension]; ok {
+		unit.Dimension, unit.Period = "", period
+	}
+	return unit, nil
+}
+
+// parsePackSize parses "1", "100", "10K", or "1M".
+func parsePackSize(text string) (float64, error) {
+	text = strings.ToUpper(strings.ReplaceAll(text, ",", ""))
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(text, "K"):
+		multiplier, text = 1e3, strings.TrimSuffix(text, "K")
+	case strings.HasSuffix(text, "M"):
+		multiplier, text = 1e6, strings.TrimSuffix(text, "M")
+	}
+	size, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("pack size must be positive: %g", size)
+	}
+	return size * multiplier, nil
+}
+
+// meterUnit parses the unit of measure of item. Items without one are
+// assumed to be priced per single unit of fallback.
+func meterUnit(item azureclient.PriceItem, fallback unitPeriod) (unitOfMeasure, error) {
+	if strings.TrimSpace(item.UnitOfMeasure) == "" {
+		return unitOfMeasure{Multiplier: 1, Period: fallback}, nil
+	}
+	return parseUnitOfMeasure(item.UnitOfMeasure)
+}
+
+// packHours returns the hours one price of a time unit covers, e.g. 100 for
+// "100 Hours" and 730 for "1/Month". Fails for usage units and for units
+// with a dimension, whose price also depends on a quantity.
+func (u unitOfMeasure) packHours() (float64, error) {
+	hours, ok := periodHours[u.Period]
+	if !ok || u.Dimension != "" {
+		return 0, fmt.Errorf("%w: want a time unit, got %s", ErrUnsupportedUnitOfMeasure, u)
+	}
+	return u.Multiplier * hours, nil
+}
+
+// usagePackSize returns the units one price of a usage unit covers, e.g.
+// 10,000 for "10K Transactions". Units billed per month ("1 GB/Month") count
+// as usage for the month; other periods fail.
+func (u unitOfMeasure) usagePackSize() (float64, error) {
+	if u.Period != periodNone && u.Period != periodMonth {
+		return 0, fmt.Errorf("%w: want a monthly or usage unit, got %s", ErrUnsupportedUnitOfMeasure, u)
+	}
+	return u.Multiplier, nil
+}
+
+// String renders the unit for error messages, e.g. "100 hour" or
+// "1 gb/month".
+func (u unitOfMeasure) String() string {
+	text := strconv.FormatFloat(u.Multiplier, 'g', -1, 64)
+	if u.Dimension != "" {
+		text += " " + u.Dimension
+	}
+	switch {
+	case u.Period == periodNone:
+	case u.Dimension == "":
+		text += " " + string(u.Period)
+	default:
+		text += "/" + string(u.Period)
+	}
+	return text
+}
+
+// timePrice is the price of a time-based meter normalized to an hourly rate.
+type timePrice struct {
+	Hourly   float64
+	Currency string
+	// PackHours is the hours the meter's published price covers.
+	PackHours float64
+	Meter     azureclient.PriceItem
+}
+
+// newTimePrice normalizes the price of a time-based meter. Meters without a
+// unit of measure are assumed to be priced per fallback period.
+func newTimePrice(item azureclient.PriceItem, fallback unitPeriod) (timePrice, error) {
+	unit, err := meterUnit(item, fallback)
+	if err != nil {
+		return timePrice{}, err
+	}
+	packHours, err := unit.packHours()
+	if err != nil {
+		return timePrice{}, fmt.Errorf("meter %q: %w", item.MeterName, err)
+	}
+	price, currency := itemPriceAndCurrency(item)
+	return timePrice{Hourly: price / packHours, Currency: currency, PackHours: packHours, Meter: item}, nil
+}
+
+// Monthly returns the price of one instance for a month.
+func (p timePrice) Monthly() float64 {
+	return p.Hourly * pluginsdk.HoursPerMonth
+}
+
+// lineItem returns the charge for hours instance-hours at hourly, a rate that
+// may be a component of the meter's price. The quantity is in the meter's
+// packs so UnitPrice matches its UnitOfMeasure.
+func (p timePrice) lineItem(description string, hourly, hours float64) LineItem {
+	return newLineItem(description, p.Meter, hourly*p.PackHours, hours/p.PackHours)
+}
@@ -0,0 +1,176 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/rs/zerolog"
+	finfocusv1 "github.com/rshade/finfocus-spec/sdk/go/proto/finfocus/v1"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+func TestParseUnitOfMeasure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  unitOfMeasure
+	}{
+		{input: "1 Hour", want: unitOfMeasure{Multiplier: 1, Period: periodHour}},
+		{input: "100 Hours", want: unitOfMeasure{Multiplier: 100, Period: periodHour}},
+		{input: "1 Day", want: unitOfMeasure{Multiplier: 1, Period: periodDay}},
+		{input: "1/Month", want: unitOfMeasure{Multiplier: 1, Period: periodMonth}},
+		{input: "1 /Month", want: unitOfMeasure{Multiplier: 1, Period: periodMonth}},
+		{input: "1 Year", want: unitOfMeasure{Multiplier: 1, Period: periodYear}},
+		{input: "1 GB/Month", want: unitOfMeasure{Dimension: "gb", Multiplier: 1, Period: periodMonth}},
+		{input: "100 GB/Hour", want: unitOfMeasure{Dimension: "gb", Multiplier: 100, Period: periodHour}},
+		{input: "10K Transactions", want: unitOfMeasure{Dimension: "transactions", Multiplier: 1e4}},
+		{input: "1M", want: unitOfMeasure{Multiplier: 1e6}},
+		{input: "10", want: unitOfMeasure{Multiplier: 10}},
+		{input: "1 GB Second", want: unitOfMeasure{Dimension: "gb second", Multiplier: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := parseUnitOfMeasure(tt.input)
+			if err != nil {
+				t.Fatalf("parseUnitOfMeasure(%q) error = %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseUnitOfMeasure(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnitOfMeasure_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "Per Month", "0 Hours", "1 GB/Fortnight", "-1 Hour"} {
+		if _, err := parseUnitOfMeasure(input); !errors.Is(err, ErrUnsupportedUnitOfMeasure) {
+			t.Errorf("parseUnitOfMeasure(%q) error = %v, want ErrUnsupportedUnitOfMeasure", input, err)
+		}
+	}
+}
+
+func TestNewTimePrice(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		unit        string
+		fallback    unitPeriod
+		price       float64
+		wantHourly  float64
+		wantMonthly float64
+	}{
+		{name: "hourly", unit: "1 Hour", fallback: periodHour, price: 0.1, wantHourly: 0.1, wantMonthly: 73},
+		{name: "hour_pack", unit: "100 Hours", fallback: periodHour, price: 10, wantHourly: 0.1, wantMonthly: 73},
+		{name: "monthly", unit: "1/Month", fallback: periodHour, price: 73, wantHourly: 0.1, wantMonthly: 73},
+		{name: "daily", unit: "1 Day", fallback: periodHour, price: 2.4, wantHourly: 0.1, wantMonthly: 73},
+		{name: "missing_hourly", fallback: periodHour, price: 0.1, wantHourly: 0.1, wantMonthly: 73},
+		{name: "missing_monthly", fallback: periodMonth, price: 73, wantHourly: 0.1, wantMonthly: 73},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := newTimePrice(azureclient.PriceItem{UnitOfMeasure: tt.unit, RetailPrice: tt.price}, tt.fallback)
+			if err != nil {
+				t.Fatalf("newTimePrice() error = %v", err)
+			}
+			if math.Abs(got.Hourly-tt.wantHourly) > 1e-12 || math.Abs(got.Monthly()-tt.wantMonthly) > 1e-9 {
+				t.Errorf("hourly = %v, monthly = %v, want %v and %v", got.Hourly, got.Monthly(), tt.wantHourly, tt.wantMonthly)
+			}
+
+			// Line items stay in the meter's packs.
+			line := got.lineItem("compute", got.Hourly, 730)
+			if math.Abs(line.CostMonthly-tt.wantMonthly) > 1e-9 || math.Abs(line.UnitPrice-tt.price) > 1e-12 {
+				t.Errorf("line item = %+v, want unit price %v and cost %v", line, tt.price, tt.wantMonthly)
+			}
+		})
+	}
+
+	for _, unit := range []string{"1 GB/Month", "10K Transactions"} {
+		if _, err := newTimePrice(azureclient.PriceItem{UnitOfMeasure: unit}, periodHour); !errors.Is(err, ErrUnsupportedUnitOfMeasure) {
+			t.Errorf("newTimePrice(%q) error = %v, want ErrUnsupportedUnitOfMeasure", unit, err)
+		}
+	}
+}
+
+func TestEstimateCost_VM_DividesByPackSize(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, []azureclient.PriceItem{{
+		ArmRegionName: "eastus", ArmSkuName: "Standard_B1s", ServiceName: "Virtual Machines",
+		CurrencyCode: "USD", UnitOfMeasure: "100 Hours", RetailPrice: 1.04,
+	}}, nil)
+	defer server.Close()
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "azure:compute/virtualMachine:VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_B1s"}))
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if want := 0.0104 * 730; math.Abs(estimate.CostMonthly-want) > 1e-9 {
+		t.Errorf("CostMonthly = %v, want %v", estimate.CostMonthly, want)
+	}
+	if line := estimate.LineItems[0]; line.UnitPrice != 1.04 || math.Abs(line.Quantity-7.3) > 1e-12 {
+		t.Errorf("line item = %+v, want 7.3 packs of 100 hours", line)
+	}
+}
+
+func TestGetProjectedCost_ConvertsUnitOfMeasure(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		unit        string
+		price       float64
+		wantMonthly float64
+		wantCode    codes.Code
+	}{
+		{name: "monthly_meter", unit: "1/Month", price: 19.71, wantMonthly: 19.71},
+		{name: "hour_pack", unit: "10 Hours", price: 0.5, wantMonthly: 36.5},
+		{name: "usage_meter", unit: "1 GB/Month", price: 0.02, wantCode: codes.FailedPrecondition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := newPriceServer(t, []azureclient.PriceItem{{
+				ArmRegionName: "eastus", ArmSkuName: "Premium_LRS", ServiceName: "Managed Disks",
+				CurrencyCode: "USD", UnitOfMeasure: tt.unit, RetailPrice: tt.price,
+			}}, nil)
+			defer server.Close()
+			cachedClient := newCalculatorTestCachedClient(t, server.URL)
+			defer cachedClient.Close()
+			calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+			resp, err := calc.GetProjectedCost(context.Background(), &finfocusv1.GetProjectedCostRequest{
+				Resource: &finfocusv1.ResourceDescriptor{
+					Provider: "azure", ResourceType: "azure:storage/managedDisk:ManagedDisk",
+					Region: "eastus", Sku: "Premium_LRS", Tags: map[string]string{"service": "Managed Disks"},
+				},
+			})
+			if tt.wantCode != codes.OK {
+				assertStatusCodeContains(t, err, tt.wantCode, "unit of measure")
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetProjectedCost() error = %v", err)
+			}
+			if math.Abs(resp.GetCostPerMonth()-tt.wantMonthly) > 1e-9 {
+				t.Errorf("CostPerMonth = %v, want %v", resp.GetCostPerMonth(), tt.wantMonthly)
+			}
+		})
+	}
+}
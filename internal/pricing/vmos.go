@@ -0,0 +1,134 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// hybridBenefitAttributeKeys are the Azure Hybrid Benefit attribute aliases,
+// in lookup precedence order.
+//
+//nolint:gochecknoglobals // Static lookup table; immutable after init.
+var hybridBenefitAttributeKeys = []string{"azureHybridBenefit", "azure_hybrid_benefit"}
+
+// licenseTypeAttributeKey is the ARM property that enables Azure Hybrid
+// Benefit on a Windows VM (e.g., "Windows_Server").
+const licenseTypeAttributeKey = "licenseType"
+
+// vmOSFromAttributes resolves the VM operating system and Azure Hybrid
+// Benefit flag. The OS defaults to Linux, or to Windows when Azure Hybrid
+// Benefit is requested, since the benefit only covers Windows licenses.
+func vmOSFromAttributes(attributes map[string]any) (string, bool, error) {
+	hybridBenefit := false
+	if value := firstNonEmptyMapValue(attributes, hybridBenefitAttributeKeys...); value != "" {
+		var err error
+		if hybridBenefit, err = strconv.ParseBool(value); err != nil {
+			return "", false, fmt.Errorf("azure_hybrid_benefit must be true or false: %s", value)
+		}
+	} else if licenseType := firstNonEmptyMapValue(attributes, licenseTypeAttributeKey); licenseType != "" {
+		hybridBenefit = strings.HasPrefix(strings.ToLower(licenseType), "windows_")
+	}
+
+	value := strings.ToLower(firstNonEmptyMapValue(attributes, osAttributeKeys...))
+	switch value {
+	case "":
+		if hybridBenefit {
+			return osWindows, true, nil
+		}
+		return osLinux, false, nil
+	case "linux":
+		if hybridBenefit {
+			return "", false, fmt.Errorf("azure_hybrid_benefit requires os %s", osWindows)
+		}
+		return osLinux, false, nil
+	case "windows":
+		return osWindows, hybridBenefit, nil
+	default:
+		return "", false, fmt.Errorf("unsupported os: %s (supported: %s, %s)", value, osLinux, osWindows)
+	}
+}
+
+// itemOS classifies a VM price item by its ProductName, which ends in
+// " Windows" for Windows meters (e.g., "Virtual Machines DSv3 Series Windows").
+func itemOS(item azureclient.PriceItem) string {
+	if strings.Contains(strings.ToLower(item.ProductName), " windows") {
+		return osWindows
+	}
+	return osLinux
+}
+
+// vmUnitPrice is the hourly price of a VM instance. Hourly includes
+// LicenseHourly, the Windows license component.
+type vmUnitPrice struct {
+	Hourly        float64
+	LicenseHourly float64
+	Currency      string
+	// PackHours is the hours the meter's published price covers (e.g., 1 for
+	// "1 Hour").
+	PackHours float64
+	// Meter is the selected price item.
+	Meter azureclient.PriceItem
+	// SavingsPlans are the hourly savings plan rates of the compute meter.
+	SavingsPlans []savingsPlanRate
+}
+
+// lineItems returns the compute and, for Windows, license charges of hours
+// instance-hours. Both reference the billed meter.
+func (p vmUnitPrice) lineItems(hours float64) []LineItem {
+	price := timePrice{Hourly: p.Hourly, Currency: p.Currency, PackHours: p.PackHours, Meter: p.Meter}
+	lineItems := []LineItem{price.lineItem("compute", p.Hourly-p.LicenseHourly, hours)}
+	if p.LicenseHourly > 0 {
+		lineItems = append(lineItems, price.lineItem("license", p.LicenseHourly, hours))
+	}
+	return lineItems
+}
+
+// selectVMPrice resolves the hourly price of a VM in the requested pricing
+// model and OS through selectMeter, normalized from the meter's unit of
+// measure (e.g., "100 Hours"). Windows prices are split into the Linux
+// compute rate and the license component; with Azure Hybrid Benefit only the
+// compute rate applies.
+func selectVMPrice(items []azureclient.PriceItem, vm vmPricing) (vmUnitPrice, error) {
+	linux, linuxErr := findVMPrice(items, vm, osLinux)
+	if vm.OS == osLinux || vm.HybridBenefit {
+		return linux, linuxErr
+	}
+
+	windows, err := findVMPrice(items, vm, osWindows)
+	if err != nil {
+		return vmUnitPrice{}, err
+	}
+	if linuxErr == nil && windows.Hourly > linux.Hourly {
+		windows.LicenseHourly = windows.Hourly - linux.Hourly
+	}
+	// Savings plans cover compute only, so the Linux meter's rates apply.
+	windows.SavingsPlans = linux.SavingsPlans
+	return windows, nil
+}
+
+// findVMPrice selects the meter of the pricing model and OS.
+func findVMPrice(items []azureclient.PriceItem, vm vmPricing, osName string) (vmUnitPrice, error) {
+	item, err := selectMeter(items, meterCriteria{Model: vm.Model, OS: osName, ArmSkuName: vm.Query.ArmSkuName})
+	if errors.Is(err, azureclient.ErrNotFound) {
+		return vmUnitPrice{}, fmt.Errorf("no %s %s pricing found for sku %s: %w",
+			vm.Model, osName, vm.Query.ArmSkuName, azureclient.ErrNotFound)
+	}
+	if err != nil {
+		return vmUnitPrice{}, err
+	}
+	price, err := newTimePrice(item, periodHour)
+	if err != nil {
+		return vmUnitPrice{}, err
+	}
+	return vmUnitPrice{
+		Hourly:       price.Hourly,
+		Currency:     price.Currency,
+		PackHours:    price.PackHours,
+		Meter:        item,
+		SavingsPlans: savingsPlanRates(item, price.PackHours),
+	}, nil
+}
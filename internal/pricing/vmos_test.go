@@ -0,0 +1,236 @@
+package pricing
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+
+	"github.com/rshade/finfocus-plugin-azure-public/internal/azureclient"
+)
+
+// vmOSTestPriceItems lists the Windows meter first, so taking the first item
+// would price a Linux VM at the Windows rate.
+func vmOSTestPriceItems() []azureclient.PriceItem {
+	return []azureclient.PriceItem{
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series Windows",
+			RetailPrice: 0.188, CurrencyCode: "USD",
+		},
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3 Spot", ProductName: "Virtual Machines DSv3 Series Windows",
+			RetailPrice: 0.05, CurrencyCode: "USD",
+		},
+		{
+			ArmSkuName: "Standard_D2s_v3", SkuName: "D2s v3", ProductName: "Virtual Machines DSv3 Series",
+			RetailPrice: 0.096, CurrencyCode: "USD",
+		},
+	}
+}
+
+func TestEstimate_VirtualMachineOS(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, vmOSTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+
+	tests := []struct {
+		name        string
+		attributes  map[string]any
+		wantHourly  float64
+		wantLicense float64
+	}{
+		{
+			name:       "defaults to linux",
+			attributes: map[string]any{"region": "eastus", "sku": "Standard_D2s_v3"},
+			wantHourly: 0.096,
+		},
+		{
+			name:        "windows reports license component",
+			attributes:  map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": "Windows"},
+			wantHourly:  0.188,
+			wantLicense: 0.188 - 0.096,
+		},
+		{
+			name: "hybrid benefit prices windows at linux rate",
+			attributes: map[string]any{
+				"region": "eastus", "sku": "Standard_D2s_v3", "os": "windows", "azure_hybrid_benefit": true,
+			},
+			wantHourly: 0.096,
+		},
+		{
+			name:       "arm license type",
+			attributes: map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "licenseType": "Windows_Server"},
+			wantHourly: 0.096,
+		},
+	}
+
+	for _, tc := range tests {
+		estimate, err := calc.Estimate(context.Background(),
+			newEstimateCostRequest(t, "compute/VirtualMachine", tc.attributes))
+		if err != nil {
+			t.Fatalf("%s: Estimate() failed: %v", tc.name, err)
+		}
+		if want := tc.wantHourly * 730; math.Abs(estimate.CostMonthly-want) > 0.000001 {
+			t.Errorf("%s: CostMonthly = %.6f, want %.6f", tc.name, estimate.CostMonthly, want)
+		}
+		if want := tc.wantLicense * 730; math.Abs(estimate.LicenseCostMonthly-want) > 0.000001 {
+			t.Errorf("%s: LicenseCostMonthly = %.6f, want %.6f", tc.name, estimate.LicenseCostMonthly, want)
+		}
+	}
+}
+
+func TestEstimate_ScaleSetWindowsLicenseScalesWithCapacity(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, vmOSTestPriceItems(), nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachineScaleSet",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": "windows", "capacity": 3}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+	if want := 0.188 * 730 * 3; math.Abs(estimate.CostMonthly-want) > 0.000001 {
+		t.Errorf("CostMonthly = %.6f, want %.6f", estimate.CostMonthly, want)
+	}
+	if want := (0.188 - 0.096) * 730 * 3; math.Abs(estimate.LicenseCostMonthly-want) > 0.000001 {
+		t.Errorf("LicenseCostMonthly = %.6f, want %.6f", estimate.LicenseCostMonthly, want)
+	}
+}
+
+func TestEstimate_VirtualMachineWindows_LineItems(t *testing.T) {
+	t.Parallel()
+
+	items := vmOSTestPriceItems()
+	items[0].MeterID = "windows-meter"
+	items[0].MeterName = "D2s v3"
+	items[0].UnitOfMeasure = "1 Hour"
+	items[0].EffectiveStartDate = "2024-01-01T00:00:00Z"
+	server := newPriceServer(t, items, nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	estimate, err := calc.Estimate(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3", "os": "windows"}))
+	if err != nil {
+		t.Fatalf("Estimate() failed: %v", err)
+	}
+
+	want := []LineItem{
+		{
+			Description: "compute", MeterID: "windows-meter", MeterName: "D2s v3", UnitOfMeasure: "1 Hour",
+			UnitPrice: 0.096, Quantity: 730, CostMonthly: 0.096 * 730, EffectiveStartDate: "2024-01-01T00:00:00Z",
+		},
+		{
+			Description: "license", MeterID: "windows-meter", MeterName: "D2s v3", UnitOfMeasure: "1 Hour",
+			UnitPrice: 0.188 - 0.096, Quantity: 730, CostMonthly: (0.188 - 0.096) * 730,
+			EffectiveStartDate: "2024-01-01T00:00:00Z",
+		},
+	}
+	assertLineItems(t, estimate, want)
+}
+
+// assertLineItems compares line items with a float tolerance and checks that
+// they add up to the estimate's CostMonthly.
+func assertLineItems(t *testing.T, estimate *CostEstimate, want []LineItem) {
+	t.Helper()
+
+	if len(estimate.LineItems) != len(want) {
+		t.Fatalf("got %d line items, want %d: %+v", len(estimate.LineItems), len(want), estimate.LineItems)
+	}
+	for i, got := range estimate.LineItems {
+		w := want[i]
+		if got.Description != w.Description || got.MeterID != w.MeterID || got.MeterName != w.MeterName ||
+			got.UnitOfMeasure != w.UnitOfMeasure || got.EffectiveStartDate != w.EffectiveStartDate ||
+			math.Abs(got.UnitPrice-w.UnitPrice) > 0.000001 || math.Abs(got.Quantity-w.Quantity) > 0.000001 ||
+			math.Abs(got.CostMonthly-w.CostMonthly) > 0.000001 {
+			t.Errorf("line item %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if total := lineItemsTotal(estimate.LineItems); math.Abs(total-estimate.CostMonthly) > 0.000001 {
+		t.Errorf("line items total %.6f, want CostMonthly %.6f", total, estimate.CostMonthly)
+	}
+}
+
+func TestEstimateCost_VirtualMachineOS_MissingVariant_ReturnsNotFound(t *testing.T) {
+	t.Parallel()
+
+	server := newPriceServer(t, vmOSTestPriceItems()[:2], nil)
+	defer server.Close()
+
+	cachedClient := newCalculatorTestCachedClient(t, server.URL)
+	defer cachedClient.Close()
+
+	calc := NewCalculator(zerolog.Nop(), cachedClient)
+	_, err := calc.EstimateCost(context.Background(), newEstimateCostRequest(t, "compute/VirtualMachine",
+		map[string]any{"region": "eastus", "sku": "Standard_D2s_v3"}))
+	assertStatusCodeContains(t, err, codes.NotFound, "no standard Linux pricing found for sku Standard_D2s_v3")
+}
+
+func TestVMOSFromAttributes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		attributes map[string]any
+		wantOS     string
+		wantAHB    bool
+		wantErr    string
+	}{
+		{name: "default", attributes: map[string]any{}, wantOS: osLinux},
+		{
+			name:       "hybrid benefit implies windows",
+			attributes: map[string]any{"azureHybridBenefit": "true"},
+			wantOS:     osWindows,
+			wantAHB:    true,
+		},
+		{name: "license type none", attributes: map[string]any{"licenseType": "None"}, wantOS: osLinux},
+		{
+			name:       "hybrid benefit on linux",
+			attributes: map[string]any{"os": "linux", "azure_hybrid_benefit": true},
+			wantErr:    "azure_hybrid_benefit requires os Windows",
+		},
+		{
+			name:       "invalid flag",
+			attributes: map[string]any{"azure_hybrid_benefit": "maybe"},
+			wantErr:    "azure_hybrid_benefit must be true or false: maybe",
+		},
+		{name: "unknown os", attributes: map[string]any{"osType": "BeOS"}, wantErr: "unsupported os: beos"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			osName, hybridBenefit, err := vmOSFromAttributes(tc.attributes)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if osName != tc.wantOS || hybridBenefit != tc.wantAHB {
+				t.Errorf("got (%s, %v), want (%s, %v)", osName, hybridBenefit, tc.wantOS, tc.wantAHB)
+			}
+		})
+	}
+}
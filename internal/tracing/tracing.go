@@ -0,0 +1,166 @@
+// Package tracing sets up OpenTelemetry tracing for the plugin.
+// It installs the global tracer provider and W3C trace-context propagator,
+// exports spans over OTLP when an endpoint is configured, and provides the
+// gRPC server interceptor that starts one span per RPC.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// instrumentationName is the instrumentation scope of the RPC spans.
+const instrumentationName = "github.com/rshade/finfocus-plugin-azure-public/internal/tracing"
+
+// OTLP protocols accepted in OTEL_EXPORTER_OTLP_PROTOCOL.
+const (
+	protocolGRPC         = "grpc"
+	protocolHTTPProtobuf = "http/protobuf"
+)
+
+// Setup installs the W3C trace-context and baggage propagator and, when
+// OTEL_EXPORTER_OTLP_ENDPOINT or OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set,
+// a tracer provider exporting spans over OTLP. The protocol is read from
+// OTEL_EXPORTER_OTLP_TRACES_PROTOCOL or OTEL_EXPORTER_OTLP_PROTOCOL
+// ("http/protobuf" by default, or "grpc"); the exporters read the remaining
+// standard OTEL_EXPORTER_OTLP_* variables themselves.
+//
+// Without an endpoint spans are not recorded, but incoming trace context is
+// still propagated. It reports whether export is enabled and returns a
+// function that flushes and stops the exporter.
+func Setup(ctx context.Context, serviceName, version string) (func(context.Context) error, bool, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	noop := func(context.Context) error { return nil }
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return noop, false, nil
+	}
+
+	exporter, err := newExporter(ctx, otlpProtocol())
+	if err != nil {
+		return noop, false, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", version),
+		),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return noop, false, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, true, nil
+}
+
+// otlpProtocol returns the configured OTLP trace protocol.
+func otlpProtocol() string {
+	for _, key := range []string{"OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL"} {
+		if val := strings.TrimSpace(os.Getenv(key)); val != "" {
+			return strings.ToLower(val)
+		}
+	}
+	return protocolHTTPProtobuf
+}
+
+// newExporter creates the OTLP span exporter for protocol.
+func newExporter(ctx context.Context, protocol string) (sdktrace.SpanExporter, error) {
+	switch protocol {
+	case protocolGRPC:
+		return otlptracegrpc.New(ctx)
+	case protocolHTTPProtobuf:
+		return otlptracehttp.New(ctx)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol: %s (supported: %s, %s)",
+			protocol, protocolGRPC, protocolHTTPProtobuf)
+	}
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor that continues the trace
+// context of the incoming metadata and wraps each RPC in a server span named
+// after the method (e.g., "finfocus.v1.CostSource/EstimateCost").
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return unaryServerInterceptor(otel.GetTracerProvider(), otel.GetTextMapPropagator())
+}
+
+func unaryServerInterceptor(
+	provider trace.TracerProvider,
+	propagator propagation.TextMapPropagator,
+) grpc.UnaryServerInterceptor {
+	tracer := provider.Tracer(instrumentationName)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+
+		name := strings.TrimPrefix(info.FullMethod, "/")
+		service, method, _ := strings.Cut(name, "/")
+		ctx, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("rpc.system", "grpc"),
+				attribute.String("rpc.service", service),
+				attribute.String("rpc.method", method),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		span.SetAttributes(attribute.Int("rpc.grpc.status_code", int(code)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, code.String())
+		}
+		return resp, err
+	}
+}
+
+// metadataCarrier adapts incoming gRPC metadata to a propagation carrier.
+type metadataCarrier metadata.MD
+
+// Get returns the first value of key.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set replaces the values of key.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns the metadata keys.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
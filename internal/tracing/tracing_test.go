@@ -0,0 +1,115 @@
+package tracing
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor_ContinuesIncomingTrace(t *testing.T) {
+	t.Parallel()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	interceptor := unaryServerInterceptor(provider, propagation.TraceContext{})
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	))
+	info := &grpc.UnaryServerInfo{FullMethod: "/finfocus.v1.CostSource/EstimateCost"}
+	var handlerSpan trace.SpanContext
+	_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil, status.Error(codes.NotFound, "no price")
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("error = %v, want the handler's NotFound", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "finfocus.v1.CostSource/EstimateCost" {
+		t.Errorf("span name = %q", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want the incoming trace", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span ID = %s, want the incoming span", got)
+	}
+	if handlerSpan.SpanID() != span.SpanContext().SpanID() {
+		t.Error("handler context should carry the RPC span")
+	}
+	want := []attribute.KeyValue{
+		attribute.String("rpc.method", "EstimateCost"),
+		attribute.Int("rpc.grpc.status_code", int(codes.NotFound)),
+	}
+	for _, kv := range want {
+		if !slices.Contains(span.Attributes(), kv) {
+			t.Errorf("missing attribute %s=%s in %v", kv.Key, kv.Value.Emit(), span.Attributes())
+		}
+	}
+}
+
+func TestSetup_WithoutEndpointOnlyPropagates(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown, enabled, err := Setup(context.Background(), "test", "dev")
+	if err != nil {
+		t.Fatalf("Setup() error: %v", err)
+	}
+	if enabled {
+		t.Error("export should be disabled without an endpoint")
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown error: %v", err)
+	}
+	if !slices.Contains(otel.GetTextMapPropagator().Fields(), "traceparent") {
+		t.Error("trace-context propagator should be installed")
+	}
+}
+
+func TestSetup_WithEndpointExports(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	for _, protocol := range []string{"", "grpc", "http/protobuf"} {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:4318")
+		t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", protocol)
+
+		shutdown, enabled, err := Setup(context.Background(), "test", "dev")
+		if err != nil {
+			t.Fatalf("Setup(%q) error: %v", protocol, err)
+		}
+		if !enabled {
+			t.Errorf("Setup(%q) should enable export", protocol)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Errorf("Setup(%q) shutdown error: %v", protocol, err)
+		}
+	}
+}
+
+func TestSetup_RejectsUnsupportedProtocol(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/json")
+
+	if _, _, err := Setup(context.Background(), "test", "dev"); err == nil {
+		t.Error("expected an error for http/json")
+	}
+}